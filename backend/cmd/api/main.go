@@ -1,17 +1,22 @@
 package main
 
 import (
+	"bytes"
 	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/infrastructure/catalog"
+	"github.com/security-checksheets/backend/internal/infrastructure/excel_client"
 	"github.com/security-checksheets/backend/internal/infrastructure/repository"
 	"github.com/security-checksheets/backend/internal/interface/handler"
 	"github.com/security-checksheets/backend/internal/usecase"
@@ -32,29 +37,119 @@ func main() {
 	projectUseCase := usecase.NewProjectUseCase(projectRepo)
 	projectHandler := handler.NewProjectHandler(projectUseCase)
 
-	// ファイル管理
+	// ファイル管理（案件のチェックシートとエビデンスで同じアップロード先を使う）
+	const uploadBasePath = "/app/uploads"
 	fileRepo := repository.NewFileRepository(db)
-	fileUseCase := usecase.NewFileUseCase(fileRepo, projectRepo, "/app/uploads")
+	fileUseCase := usecase.NewFileUseCase(fileRepo, projectRepo, uploadBasePath)
 	fileHandler := handler.NewFileHandler(fileUseCase)
 
 	// ナレッジ管理
 	knowledgeRepo := repository.NewKnowledgeRepository(db)
-	knowledgeUseCase := usecase.NewKnowledgeUseCase(knowledgeRepo, projectRepo)
+	knowledgeAnswerRepo := repository.NewKnowledgeAnswerRepository(db)
+	departmentRepo := repository.NewDepartmentRepository(db)
+	knowledgeUseCase := usecase.NewKnowledgeUseCase(knowledgeRepo, projectRepo, knowledgeAnswerRepo, departmentRepo)
 	knowledgeHandler := handler.NewKnowledgeHandler(knowledgeUseCase)
 
+	// ナレッジの分割・統合
+	lineageRepo := repository.NewKnowledgeLineageRepository(db)
+	lineageUseCase := usecase.NewLineageUseCase(knowledgeRepo, lineageRepo)
+	lineageHandler := handler.NewLineageHandler(lineageUseCase)
+
+	// 重複ナレッジの検出・解消
+	duplicateRepo := repository.NewKnowledgeDuplicateRepository(db)
+	duplicateUseCase := usecase.NewDuplicateUseCase(knowledgeRepo, projectRepo, duplicateRepo)
+	duplicateHandler := handler.NewDuplicateHandler(duplicateUseCase)
+
+	// 標準質問ライブラリ
+	canonicalRepo := repository.NewCanonicalQuestionRepository(db)
+	canonicalUseCase := usecase.NewCanonicalUseCase(canonicalRepo, knowledgeRepo)
+	canonicalHandler := handler.NewCanonicalHandler(canonicalUseCase)
+
+	// 統制フレームワークとカバレッジ
+	controlRepo := repository.NewControlRepository(db)
+	controlUseCase := usecase.NewControlUseCase(controlRepo, knowledgeRepo, projectRepo)
+	controlHandler := handler.NewControlHandler(controlUseCase)
+	// 同梱のカタログを読み込む（失敗しても起動は続け、取込APIで再登録できる）
+	if _, err := controlUseCase.LoadCatalog(bytes.NewReader(catalog.DefaultControlCatalog), usecase.CatalogFormatJSON); err != nil {
+		log.Printf("統制カタログの読み込みに失敗しました: %v", err)
+	}
+
+	// タグ分類
+	tagRepo := repository.NewTagRepository(db)
+	tagUseCase := usecase.NewTagUseCase(tagRepo, knowledgeRepo)
+	tagHandler := handler.NewTagHandler(tagUseCase)
+
+	// エビデンス
+	evidenceRepo := repository.NewEvidenceRepository(db)
+	evidenceUseCase := usecase.NewEvidenceUseCase(evidenceRepo, knowledgeRepo, uploadBasePath)
+	evidenceHandler := handler.NewEvidenceHandler(evidenceUseCase)
+
+	// 回答のプレースホルダーと案件変数
+	templateUseCase := usecase.NewTemplateUseCase(projectRepo, knowledgeRepo)
+	templateHandler := handler.NewTemplateHandler(templateUseCase)
+
+	// 回答推薦
+	recommendationRepo := repository.NewKnowledgeRecommendationRepository(db)
+	recommendationUseCase := usecase.NewRecommendationUseCase(knowledgeRepo, projectRepo, knowledgeAnswerRepo, recommendationRepo)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationUseCase)
+
+	// ナレッジ変更履歴
+	revisionRepo := repository.NewKnowledgeRevisionRepository(db)
+	revisionUseCase := usecase.NewRevisionUseCase(knowledgeRepo, revisionRepo, knowledgeUseCase)
+	revisionHandler := handler.NewRevisionHandler(revisionUseCase)
+
+	// ナレッジのレビュー・承認ワークフロー
+	workflowRepo := repository.NewKnowledgeWorkflowRepository(db)
+	workflowUseCase := usecase.NewWorkflowUseCase(knowledgeRepo, workflowRepo)
+	workflowHandler := handler.NewWorkflowHandler(workflowUseCase)
+
 	// 部門管理
-	departmentRepo := repository.NewDepartmentRepository(db)
 	departmentHandler := handler.NewDepartmentHandler(departmentRepo)
 
+	// 回答の有効期限と定期レビュー
+	reviewRepo := repository.NewKnowledgeReviewRepository(db)
+	reviewUseCase := usecase.NewReviewUseCase(reviewRepo, workflowRepo, departmentRepo)
+	reviewHandler := handler.NewReviewHandler(reviewUseCase)
+	startReviewScheduler(reviewUseCase)
+
+	// 担当割り当てと進捗
+	assignmentRepo := repository.NewKnowledgeAssignmentRepository(db)
+	assignmentUseCase := usecase.NewAssignmentUseCase(assignmentRepo, knowledgeRepo, projectRepo, departmentRepo)
+	assignmentHandler := handler.NewAssignmentHandler(assignmentUseCase)
+
+	// コメントスレッド
+	commentRepo := repository.NewCommentRepository(db)
+	commentUseCase := usecase.NewCommentUseCase(commentRepo, knowledgeRepo, projectRepo, departmentRepo)
+	commentHandler := handler.NewCommentHandler(commentUseCase)
+
+	// ゴミ箱（削除したデータの復元と、保持期間経過後の完全な削除）
+	trashRepo := repository.NewTrashRepository(db)
+	trashUseCase := usecase.NewTrashUseCase(trashRepo, projectRepo, fileRepo, knowledgeRepo, trashRetentionDays())
+	trashHandler := handler.NewTrashHandler(trashUseCase)
+	startTrashPurgeScheduler(trashUseCase)
+
+	// エクスポート
+	exportUseCase := usecase.NewExportUseCase(knowledgeRepo, projectRepo, fileRepo, departmentRepo, evidenceRepo)
+	exportHandler := handler.NewExportHandler(exportUseCase)
+
+	// インポート（ExcelファイルはアップロードフォルダをExcel処理APIと共有して読み取る）
+	excelServiceURL := os.Getenv("EXCEL_SERVICE_URL")
+	if excelServiceURL == "" {
+		excelServiceURL = "http://localhost:8000"
+	}
+	excelClient := excel_client.NewExcelClient(excelServiceURL)
+	importUseCase := usecase.NewImportUseCase(knowledgeUseCase, departmentRepo, excelClient, uploadBasePath)
+	importHandler := handler.NewImportHandler(importUseCase)
+
 	// Ginルーターの初期化
 	router := gin.Default()
 
 	// CORS設定
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -84,7 +179,10 @@ func main() {
 			projects.GET("", projectHandler.ListProjects)
 			projects.GET("/:id", projectHandler.GetProject)
 			projects.PUT("/:id", projectHandler.UpdateProject)
+			projects.PATCH("/:id", projectHandler.UpdateProject)
 			projects.DELETE("/:id", projectHandler.DeleteProject)
+			projects.PUT("/:id/variables", templateHandler.SetProjectVariables)
+			projects.POST("/:id/render", templateHandler.RenderAnswer)
 
 			// 案件に紐づくファイル管理
 			projects.POST("/:id/files", fileHandler.UploadFile)
@@ -92,6 +190,21 @@ func main() {
 
 			// 案件に紐づくナレッジ
 			projects.GET("/:id/knowledge", knowledgeHandler.ListKnowledgeByProject)
+			projects.GET("/:id/knowledge/export.csv", exportHandler.ExportProjectKnowledge)
+			projects.GET("/:id/knowledge/export.zip", exportHandler.ExportProjectKnowledgeZip)
+			projects.GET("/:id/knowledge/duplicates", duplicateHandler.FindDuplicates)
+			projects.POST("/:id/knowledge/duplicates/resolve", duplicateHandler.ResolveDuplicates)
+
+			// 案件の未回答の質問に対する回答推薦
+			projects.POST("/:id/recommendations", recommendationHandler.GenerateRecommendations)
+			projects.GET("/:id/recommendations", recommendationHandler.ListRecommendations)
+
+			// 質問ごとの担当割り当てと進捗
+			projects.POST("/:id/assignments", assignmentHandler.BulkAssign)
+			projects.GET("/:id/progress", assignmentHandler.GetProgress)
+
+			// 提出前に確認が必要な未解決のコメントスレッド
+			projects.GET("/:id/open-threads", commentHandler.ListOpenThreads)
 		}
 
 		// ファイル管理エンドポイント
@@ -106,12 +219,111 @@ func main() {
 		{
 			knowledge.POST("", knowledgeHandler.CreateKnowledge)
 			knowledge.POST("/bulk", knowledgeHandler.BulkCreateKnowledge)
+			knowledge.POST("/bulk-update", knowledgeHandler.BulkUpdateKnowledge)
+			knowledge.POST("/bulk-delete", knowledgeHandler.BulkDeleteKnowledge)
+			knowledge.POST("/import", importHandler.ImportKnowledge)
+			knowledge.POST("/merge", lineageHandler.MergeKnowledge)
+			knowledge.POST("/bulk-tag", tagHandler.BulkTag)
 			knowledge.GET("/search", knowledgeHandler.SearchKnowledge)
+			knowledge.GET("/similar", knowledgeHandler.FindSimilarKnowledge)
+			knowledge.GET("/search/export.csv", exportHandler.ExportSearchKnowledge)
+			knowledge.GET("/search/export.zip", exportHandler.ExportSearchKnowledgeZip)
+			knowledge.GET("/due-for-review", reviewHandler.ListDueForReview)
 			knowledge.GET("/:id", knowledgeHandler.GetKnowledge)
 			knowledge.PUT("/:id", knowledgeHandler.UpdateKnowledge)
+			knowledge.PATCH("/:id", knowledgeHandler.UpdateKnowledge)
 			knowledge.DELETE("/:id", knowledgeHandler.DeleteKnowledge)
+			knowledge.GET("/:id/revisions", revisionHandler.ListRevisions)
+			knowledge.GET("/:id/revisions/diff", revisionHandler.DiffRevisions)
+			knowledge.POST("/:id/revisions/:rev/restore", revisionHandler.RestoreRevision)
+			knowledge.POST("/:id/submit", workflowHandler.Submit)
+			knowledge.POST("/:id/approve", workflowHandler.Approve)
+			knowledge.POST("/:id/reject", workflowHandler.Reject)
+			knowledge.POST("/:id/publish", workflowHandler.Publish)
+			knowledge.POST("/:id/archive", workflowHandler.Archive)
+			knowledge.GET("/:id/transitions", workflowHandler.ListTransitions)
+			knowledge.POST("/:id/split", lineageHandler.SplitKnowledge)
+			knowledge.GET("/:id/lineage", lineageHandler.ListLineage)
+			knowledge.PUT("/:id/canonical", canonicalHandler.LinkKnowledge)
+			knowledge.DELETE("/:id/canonical", canonicalHandler.UnlinkKnowledge)
+			knowledge.GET("/:id/controls", controlHandler.GetKnowledgeControls)
+			knowledge.PUT("/:id/controls", controlHandler.SetKnowledgeControls)
+			knowledge.GET("/:id/render", templateHandler.RenderKnowledge)
+			knowledge.GET("/:id/tags", tagHandler.GetKnowledgeTags)
+			knowledge.PUT("/:id/tags", tagHandler.SetKnowledgeTags)
+			knowledge.POST("/:id/evidence", evidenceHandler.UploadEvidence)
+			knowledge.GET("/:id/evidence", evidenceHandler.ListKnowledgeEvidence)
+			knowledge.PUT("/:id/evidence/:evidenceId", evidenceHandler.LinkEvidence)
+			knowledge.DELETE("/:id/evidence/:evidenceId", evidenceHandler.UnlinkEvidence)
+			knowledge.PUT("/:id/assignment", assignmentHandler.UpdateTask)
+			knowledge.GET("/:id/comments", commentHandler.GetThread)
+			knowledge.POST("/:id/comments", commentHandler.AddComment)
+			knowledge.POST("/:id/comments/resolve", commentHandler.ResolveThread)
+			knowledge.POST("/:id/comments/reopen", commentHandler.ReopenThread)
+		}
+
+		// タグ分類エンドポイント
+		tags := api.Group("/tags")
+		{
+			tags.POST("", tagHandler.CreateTag)
+			tags.GET("", tagHandler.ListTags)
+			tags.POST("/migrate-question-groups", tagHandler.MigrateQuestionGroups)
+			tags.GET("/:id", tagHandler.GetTag)
+			tags.PUT("/:id", tagHandler.UpdateTag)
+			tags.DELETE("/:id", tagHandler.DeleteTag)
+		}
+
+		// 標準質問ライブラリエンドポイント
+		canonical := api.Group("/canonical-questions")
+		{
+			canonical.POST("", canonicalHandler.CreateCanonicalQuestion)
+			canonical.GET("", canonicalHandler.ListCanonicalQuestions)
+			canonical.GET("/:id", canonicalHandler.GetCanonicalQuestion)
+			canonical.PUT("/:id", canonicalHandler.UpdateCanonicalQuestion)
+			canonical.DELETE("/:id", canonicalHandler.DeleteCanonicalQuestion)
+			canonical.GET("/:id/variants", canonicalHandler.ListVariants)
+		}
+
+		// 統制フレームワークエンドポイント
+		frameworks := api.Group("/control-frameworks")
+		{
+			frameworks.GET("", controlHandler.ListFrameworks)
+			frameworks.POST("/import", controlHandler.ImportCatalog)
+			frameworks.GET("/:code/controls", controlHandler.ListControls)
+			frameworks.GET("/:code/coverage", controlHandler.GetCoverage)
+		}
+
+		// 質問グループと統制項目の紐づけエンドポイント
+		api.GET("/question-groups/controls", controlHandler.GetQuestionGroupControls)
+		api.PUT("/question-groups/controls", controlHandler.SetQuestionGroupControls)
+
+		// エビデンスエンドポイント
+		evidence := api.Group("/evidence")
+		{
+			evidence.GET("", evidenceHandler.ListEvidence)
+			evidence.PUT("/:id", evidenceHandler.UpdateEvidence)
+			evidence.GET("/:id/download", evidenceHandler.DownloadEvidence)
+		}
+
+		// コメントエンドポイント
+		comments := api.Group("/comments")
+		{
+			comments.PUT("/:id", commentHandler.EditComment)
+			comments.DELETE("/:id", commentHandler.DeleteComment)
 		}
 
+		// ゴミ箱エンドポイント
+		trash := api.Group("/trash")
+		{
+			trash.GET("", trashHandler.ListTrash)
+			trash.POST("/projects/:id/restore", trashHandler.RestoreProject)
+			trash.POST("/files/:id/restore", trashHandler.RestoreFile)
+			trash.POST("/knowledge/:id/restore", trashHandler.RestoreKnowledge)
+		}
+
+		// 回答推薦エンドポイント
+		api.POST("/recommendations/:id/accept", recommendationHandler.AcceptRecommendation)
+
 		// 部門管理エンドポイント
 		api.GET("/departments", departmentHandler.ListDepartments)
 	}
@@ -128,6 +340,91 @@ func main() {
 	}
 }
 
+// startReviewScheduler は期限を過ぎたナレッジをneeds_reviewにする確認を起動時と一定間隔ごとに実行する
+// 間隔はREVIEW_CHECK_INTERVAL（例: 30m, 1h）で指定し、既定は1時間
+func startReviewScheduler(reviewUseCase usecase.ReviewUseCase) {
+	interval := time.Hour
+	if v := os.Getenv("REVIEW_CHECK_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Printf("REVIEW_CHECK_INTERVALが不正なため既定の間隔を使います: %s", v)
+		} else {
+			interval = d
+		}
+	}
+
+	check := func() {
+		result, err := reviewUseCase.MarkDueForReview(time.Now())
+		if err != nil {
+			log.Printf("定期レビューの確認に失敗しました: %v", err)
+			return
+		}
+		if result.Marked > 0 || result.Skipped > 0 {
+			log.Printf("定期レビューの確認: %d件を再レビュー対象にしました（競合により%d件を次回に持ち越し）", result.Marked, result.Skipped)
+		}
+	}
+
+	go func() {
+		check()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			check()
+		}
+	}()
+}
+
+// trashRetentionDays はゴミ箱のデータを完全に削除するまでの保持日数をTRASH_RETENTION_DAYSから取得する
+func trashRetentionDays() int {
+	v := os.Getenv("TRASH_RETENTION_DAYS")
+	if v == "" {
+		return domain.DefaultTrashRetentionDays
+	}
+	days, err := strconv.Atoi(v)
+	if err != nil || days <= 0 {
+		log.Printf("TRASH_RETENTION_DAYSが不正なため既定の保持日数を使います: %s", v)
+		return domain.DefaultTrashRetentionDays
+	}
+	return days
+}
+
+// startTrashPurgeScheduler は保持期間を過ぎたゴミ箱のデータの完全な削除を起動時と一定間隔ごとに実行する
+// 間隔はTRASH_PURGE_INTERVAL（例: 6h, 24h）で指定し、既定は24時間
+func startTrashPurgeScheduler(trashUseCase usecase.TrashUseCase) {
+	interval := 24 * time.Hour
+	if v := os.Getenv("TRASH_PURGE_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Printf("TRASH_PURGE_INTERVALが不正なため既定の間隔を使います: %s", v)
+		} else {
+			interval = d
+		}
+	}
+
+	purge := func() {
+		result, err := trashUseCase.PurgeExpired(time.Now())
+		if result == nil {
+			log.Printf("ゴミ箱の完全な削除に失敗しました: %v", err)
+			return
+		}
+		if err != nil {
+			log.Printf("ゴミ箱の物理ファイルの一部を削除できませんでした: %v", err)
+		}
+		if result.Projects > 0 || result.Files > 0 || result.Knowledge > 0 || result.Evidence > 0 {
+			log.Printf("ゴミ箱の完全な削除: 案件%d件、ファイル%d件、ナレッジ%d件、エビデンス%d件", result.Projects, result.Files, result.Knowledge, result.Evidence)
+		}
+	}
+
+	go func() {
+		purge()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			purge()
+		}
+	}()
+}
+
 // initDB はデータベース接続を初期化する
 func initDB() *sql.DB {
 	// 環境変数からDB接続情報を取得
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 組み込みのプレースホルダー（案件の項目から解決する）
+const (
+	PlaceholderCustomerName = "customer_name"
+	PlaceholderOwner        = "owner"
+)
+
+// placeholderPattern は回答中の {{name}} 形式のプレースホルダー
+var placeholderPattern = regexp.MustCompile(`\{\{([^{}]*)\}\}`)
+
+// placeholderNamePattern はプレースホルダー名・案件変数名として使える形式
+var placeholderNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// RenderedAnswer は回答のプレースホルダーを案件の値で置き換えた結果
+type RenderedAnswer struct {
+	ProjectID int    `json:"project_id"`
+	Template  string `json:"template"`
+	Rendered  string `json:"rendered"`
+	// Placeholders は回答に含まれるプレースホルダー、Unknown はそのうち案件で解決できなかったもの（置き換えずに残す）
+	Placeholders []string `json:"placeholders"`
+	Unknown      []string `json:"unknown"`
+}
+
+// IsBuiltinPlaceholder は案件の項目から解決する組み込みのプレースホルダーかを返す
+func IsBuiltinPlaceholder(name string) bool {
+	return name == PlaceholderCustomerName || name == PlaceholderOwner
+}
+
+// ExtractPlaceholders は回答に含まれるプレースホルダー名を出現順に重複なく返す
+// 名前の形式が不正なもの、閉じられていない {{ や対応しない }} はエラーとする
+func ExtractPlaceholders(text string) ([]string, error) {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.TrimSpace(m[1])
+		if !placeholderNamePattern.MatchString(name) {
+			return nil, &ValidationError{Field: "answer", Message: fmt.Sprintf("不正なプレースホルダーです: %s", m[0])}
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	rest := placeholderPattern.ReplaceAllString(text, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return nil, &ValidationError{Field: "answer", Message: "プレースホルダーの括弧が対応していません"}
+	}
+
+	return names, nil
+}
+
+// HasPlaceholders は回答にプレースホルダーの記法が含まれるかを返す
+func HasPlaceholders(text string) bool {
+	return strings.Contains(text, "{{") || strings.Contains(text, "}}")
+}
+
+// TemplateValues は案件のプレースホルダーの値を返す（組み込みの項目は案件変数より優先する）
+func (p *Project) TemplateValues() map[string]string {
+	values := make(map[string]string, len(p.Variables)+2)
+	for name, value := range p.Variables {
+		values[name] = value
+	}
+	values[PlaceholderCustomerName] = p.CustomerName
+	values[PlaceholderOwner] = p.Owner
+	return values
+}
+
+// ValidateVariables は案件変数の名前を検証する
+func (p *Project) ValidateVariables() error {
+	names := make([]string, 0, len(p.Variables))
+	for name := range p.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !placeholderNamePattern.MatchString(name) {
+			return &ValidationError{Field: "variables", Message: fmt.Sprintf("変数名は英小文字で始まり英小文字・数字・_のみで指定してください: %s", name)}
+		}
+		if IsBuiltinPlaceholder(name) {
+			return &ValidationError{Field: "variables", Message: fmt.Sprintf("%sは案件の項目から解決されるため変数として定義できません", name)}
+		}
+	}
+	return nil
+}
+
+// RenderAnswer は回答のプレースホルダーを案件の値で置き換える
+// 解決できないプレースホルダーは置き換えずに残し、Unknownに含める
+func RenderAnswer(template string, project *Project) (*RenderedAnswer, error) {
+	names, err := ExtractPlaceholders(template)
+	if err != nil {
+		return nil, err
+	}
+
+	values := project.TemplateValues()
+	result := &RenderedAnswer{
+		ProjectID:    project.ID,
+		Template:     template,
+		Placeholders: names,
+		Unknown:      []string{},
+	}
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			result.Unknown = append(result.Unknown, name)
+		}
+	}
+
+	result.Rendered = placeholderPattern.ReplaceAllStringFunc(template, func(m string) string {
+		name := strings.TrimSpace(m[2 : len(m)-2])
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return m
+	})
+
+	return result, nil
+}
+
+// ValidatePlaceholders は回答のプレースホルダーが案件の項目または案件変数で解決できるかを検証する
+func (k *KnowledgeItem) ValidatePlaceholders(project *Project) error {
+	if !HasPlaceholders(k.Answer) {
+		return nil
+	}
+
+	rendered, err := RenderAnswer(k.Answer, project)
+	if err != nil {
+		return err
+	}
+	if len(rendered.Unknown) > 0 {
+		return &ValidationError{Field: "answer", Message: fmt.Sprintf("未定義のプレースホルダーがあります: %s", strings.Join(rendered.Unknown, ", "))}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractPlaceholders(t *testing.T) {
+	names, err := ExtractPlaceholders("{{customer_name}}様向けの{{ product }}は{{retention_days}}日間、{{customer_name}}のログを保持します")
+	if err != nil {
+		t.Fatalf("ExtractPlaceholders() error = %v", err)
+	}
+	want := []string{"customer_name", "product", "retention_days"}
+	if len(names) != len(want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("names[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestExtractPlaceholders_Malformed(t *testing.T) {
+	tests := []string{
+		"{{customer name}}",
+		"{{Product}}",
+		"{{}}",
+		"{{product",
+		"product}}",
+	}
+
+	for _, text := range tests {
+		_, err := ExtractPlaceholders(text)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("ExtractPlaceholders(%q) error = %v, want *ValidationError", text, err)
+		}
+	}
+}
+
+func TestRenderAnswer(t *testing.T) {
+	project := &Project{
+		ID:           3,
+		CustomerName: "テスト株式会社",
+		Owner:        "山田太郎",
+		Variables:    map[string]string{"product": "SecureBox", "retention_days": "90"},
+	}
+
+	rendered, err := RenderAnswer("{{customer_name}}様向けの{{ product }}はログを{{retention_days}}日間保持します。{{sla}}", project)
+	if err != nil {
+		t.Fatalf("RenderAnswer() error = %v", err)
+	}
+	if want := "テスト株式会社様向けのSecureBoxはログを90日間保持します。{{sla}}"; rendered.Rendered != want {
+		t.Errorf("Rendered = %q, want %q", rendered.Rendered, want)
+	}
+	if len(rendered.Unknown) != 1 || rendered.Unknown[0] != "sla" {
+		t.Errorf("Unknown = %v, want [sla]", rendered.Unknown)
+	}
+	if rendered.ProjectID != 3 {
+		t.Errorf("ProjectID = %d, want 3", rendered.ProjectID)
+	}
+}
+
+func TestKnowledgeItem_ValidatePlaceholders(t *testing.T) {
+	project := &Project{CustomerName: "テスト株式会社", Variables: map[string]string{"product": "SecureBox"}}
+
+	if err := (&KnowledgeItem{Answer: "{{product}}を{{customer_name}}に提供します"}).ValidatePlaceholders(project); err != nil {
+		t.Errorf("ValidatePlaceholders() error = %v", err)
+	}
+	if err := (&KnowledgeItem{Answer: "ログは{{retention_days}}日間保持します"}).ValidatePlaceholders(project); err == nil {
+		t.Error("unknown placeholder should be rejected")
+	}
+	if err := (&KnowledgeItem{Answer: "はい"}).ValidatePlaceholders(nil); err != nil {
+		t.Errorf("answer without placeholders should not require a project: %v", err)
+	}
+}
+
+func TestProject_ValidateVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables map[string]string
+		wantErr   bool
+	}{
+		{"valid", map[string]string{"product": "SecureBox", "retention_days": "90"}, false},
+		{"uppercase", map[string]string{"Product": "SecureBox"}, true},
+		{"builtin", map[string]string{"customer_name": "別名"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Project{CustomerName: "テスト株式会社", Variables: tt.variables}
+			if err := p.ValidateVariables(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
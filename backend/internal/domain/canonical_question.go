@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// CanonicalQuestion は顧客ごとに表現の異なる同じ統制項目の質問をまとめる標準質問
+type CanonicalQuestion struct {
+	ID       int    `json:"id"`
+	Question string `json:"question"`
+	// MasterAnswer は承認済みの標準回答。紐づくアイテムの回答との乖離判定に使う
+	MasterAnswer string `json:"master_answer"`
+	DepartmentID *int   `json:"department_id,omitempty"`
+	// LinkedCount は紐づくナレッジアイテム数、DriftedCount はそのうち回答が標準回答と異なる数（取得時に集計する）
+	LinkedCount  int       `json:"linked_count"`
+	DriftedCount int       `json:"drifted_count"`
+	CreatedBy    string    `json:"created_by"`
+	UpdatedBy    string    `json:"updated_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CanonicalQuestionRepository は標準質問リポジトリのインターフェース
+type CanonicalQuestionRepository interface {
+	Create(cq *CanonicalQuestion) error
+	GetByID(id int) (*CanonicalQuestion, error)
+	// GetAll はdepartmentIDが0以外の場合、その部門の標準質問に限定する
+	GetAll(departmentID int) ([]*CanonicalQuestion, error)
+	// Update は標準質問を更新し、紐づくアイテムの乖離フラグを標準回答に合わせて判定し直す
+	Update(cq *CanonicalQuestion) error
+	Delete(id int) error
+	// GetVariants は標準質問に紐づくナレッジアイテムを取得する
+	GetVariants(id int) ([]*KnowledgeItem, error)
+}
+
+// NewCanonicalQuestion は新しい標準質問を生成する
+func NewCanonicalQuestion(question, masterAnswer string, departmentID *int, createdBy string) *CanonicalQuestion {
+	now := time.Now()
+	return &CanonicalQuestion{
+		Question:     strings.TrimSpace(question),
+		MasterAnswer: masterAnswer,
+		DepartmentID: departmentID,
+		CreatedBy:    createdBy,
+		UpdatedBy:    createdBy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Validate は標準質問の内容を検証する
+func (c *CanonicalQuestion) Validate() error {
+	if strings.TrimSpace(c.Question) == "" {
+		return &ValidationError{Field: "question", Message: "質問は必須です"}
+	}
+	return nil
+}
+
+// LinkCanonicalQuestion はアイテムを標準質問に紐づける（版は新しい版として進める）
+func (k *KnowledgeItem) LinkCanonicalQuestion(canonicalQuestionID int, actor string) {
+	k.CanonicalQuestionID = &canonicalQuestionID
+	k.UpdatedBy = actor
+	k.Version++
+	k.UpdatedAt = time.Now()
+}
+
+// UnlinkCanonicalQuestion は標準質問との紐づけを解除する（版は新しい版として進める）
+func (k *KnowledgeItem) UnlinkCanonicalQuestion(actor string) {
+	k.CanonicalQuestionID = nil
+	k.AnswerDrifted = false
+	k.UpdatedBy = actor
+	k.Version++
+	k.UpdatedAt = time.Now()
+}
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// メンションの種類
+const (
+	MentionUser       = "user"
+	MentionDepartment = "department"
+)
+
+// MaxCommentLength はコメント本文の最大文字数
+const MaxCommentLength = 10000
+
+// AnonymousCommentAuthor は投稿者を名乗らずに投稿されたコメントの投稿者名
+// 名乗らない操作者どうしを区別できないため、このコメントは誰も編集・削除できない
+const AnonymousCommentAuthor = "anonymous"
+
+// ErrNotCommentAuthor はコメントの投稿者以外（操作者を名乗らない場合を含む）が編集・削除しようとしたことを表す
+var ErrNotCommentAuthor = errors.New("コメントは投稿者のみ編集・削除できます")
+
+// mentionPattern は本文中の @名前 形式のメンション（メールアドレスと区別するため行頭・空白・句読点の直後のみ）
+var mentionPattern = regexp.MustCompile(`(?:^|[\s　、。,，(（「])[@＠]([^\s　@＠、。,，!！?？()（）「」]+)`)
+
+// KnowledgeComment はナレッジアイテムのスレッドへのコメント
+type KnowledgeComment struct {
+	ID              int               `json:"id"`
+	KnowledgeItemID int               `json:"knowledge_item_id"`
+	Author          string            `json:"author"`
+	Body            string            `json:"body"`
+	Mentions        []*CommentMention `json:"mentions"`
+	// Edited は投稿後に本文が編集されたかどうか
+	Edited    bool      `json:"edited"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentMention はコメントで言及されたユーザーまたは部門
+type CommentMention struct {
+	Type           string `json:"type"`
+	UserName       string `json:"user_name,omitempty"`
+	DepartmentID   *int   `json:"department_id,omitempty"`
+	DepartmentName string `json:"department_name,omitempty"`
+}
+
+// CommentThread はナレッジアイテムごとのコメントスレッド
+type CommentThread struct {
+	KnowledgeItemID int                 `json:"knowledge_item_id"`
+	Resolved        bool                `json:"resolved"`
+	ResolvedBy      string              `json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time          `json:"resolved_at,omitempty"`
+	Comments        []*KnowledgeComment `json:"comments"`
+}
+
+// OpenThread は未解決のスレッドの概要
+type OpenThread struct {
+	KnowledgeItemID int    `json:"knowledge_item_id"`
+	Question        string `json:"question"`
+	Status          string `json:"status"`
+	DepartmentID    *int   `json:"department_id,omitempty"`
+	CommentCount    int    `json:"comment_count"`
+	// LastComment はスレッドの最新のコメント
+	LastComment *KnowledgeComment `json:"last_comment"`
+}
+
+// OpenThreadFilter は未解決のスレッドの絞り込み条件
+type OpenThreadFilter struct {
+	// MentionedUser が空でない場合、そのユーザーがメンションされたスレッドに限定する
+	MentionedUser string
+	// MentionedDepartmentID がnilでない場合、その部門がメンションされたスレッドに限定する
+	MentionedDepartmentID *int
+}
+
+// CommentRepository はコメントスレッドリポジトリのインターフェース
+type CommentRepository interface {
+	// Create はコメントとメンションを登録し、解決済みのスレッドを未解決に戻す
+	Create(comment *KnowledgeComment) error
+	GetByID(id int) (*KnowledgeComment, error)
+	// Update は本文とメンションを更新する
+	Update(comment *KnowledgeComment) error
+	Delete(id int) error
+	// GetThread はアイテムのスレッドをコメントの投稿順に取得する（コメントがない場合も空のスレッドを返す）
+	GetThread(knowledgeID int) (*CommentThread, error)
+	SetResolved(knowledgeID int, resolved bool, actor string, at time.Time) error
+	// GetOpenThreads は案件内でコメントがあり未解決のスレッドをアイテムのID順に取得する
+	GetOpenThreads(projectID int, filter OpenThreadFilter) ([]*OpenThread, error)
+}
+
+// NewKnowledgeComment は新しいコメントを生成する
+func NewKnowledgeComment(knowledgeID int, author string, body string) *KnowledgeComment {
+	now := time.Now()
+	return &KnowledgeComment{
+		KnowledgeItemID: knowledgeID,
+		Author:          author,
+		Body:            strings.TrimSpace(body),
+		Mentions:        []*CommentMention{},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Validate はコメントの妥当性を検証する
+func (c *KnowledgeComment) Validate() error {
+	if c.Body == "" {
+		return &ValidationError{Field: "body", Message: "コメントは必須です"}
+	}
+	if len([]rune(c.Body)) > MaxCommentLength {
+		return &ValidationError{Field: "body", Message: "コメントは10000文字以内で入力してください"}
+	}
+	return nil
+}
+
+// Edit は投稿者による本文の編集を行う
+func (c *KnowledgeComment) Edit(actor string, body string) error {
+	if !c.isAuthor(actor) {
+		return ErrNotCommentAuthor
+	}
+	c.Body = strings.TrimSpace(body)
+	c.Edited = true
+	c.UpdatedAt = time.Now()
+	return c.Validate()
+}
+
+// CanDelete は投稿者による削除かどうかを確認する
+func (c *KnowledgeComment) CanDelete(actor string) error {
+	if !c.isAuthor(actor) {
+		return ErrNotCommentAuthor
+	}
+	return nil
+}
+
+// isAuthor は操作者がコメントの投稿者かどうかを返す。操作者を名乗らない場合は投稿者として扱わない
+func (c *KnowledgeComment) isAuthor(actor string) bool {
+	actor = strings.TrimSpace(actor)
+	return actor != "" && actor != AnonymousCommentAuthor && actor == c.Author
+}
+
+// ParseMentions は本文中の @名前 をメンションとして取り出す
+// 名前が部門名と一致する場合は部門、それ以外はユーザーへのメンションとし、重複は除く
+func ParseMentions(body string, departments []*Department) []*CommentMention {
+	byName := make(map[string]*Department, len(departments))
+	for _, d := range departments {
+		byName[NormalizeForSearch(d.Name)] = d
+	}
+
+	mentions := []*CommentMention{}
+	seen := map[string]bool{}
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.TrimRight(m[1], ".．")
+		if name == "" {
+			continue
+		}
+
+		if d, ok := byName[NormalizeForSearch(name)]; ok {
+			key := MentionDepartment + ":" + d.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			id := d.ID
+			mentions = append(mentions, &CommentMention{Type: MentionDepartment, DepartmentID: &id, DepartmentName: d.Name})
+			continue
+		}
+
+		key := MentionUser + ":" + name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		mentions = append(mentions, &CommentMention{Type: MentionUser, UserName: name})
+	}
+
+	return mentions
+}
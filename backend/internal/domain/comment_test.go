@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	departments := []*Department{
+		{ID: 1, Name: "情報システム部"},
+		{ID: 2, Name: "法務部"},
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want []CommentMention
+	}{
+		{
+			name: "ユーザーと部門",
+			body: "@山田 さん、@情報システム部 に確認をお願いします",
+			want: []CommentMention{
+				{Type: MentionUser, UserName: "山田"},
+				{Type: MentionDepartment, DepartmentID: intPtr(1), DepartmentName: "情報システム部"},
+			},
+		},
+		{
+			name: "全角の＠と句読点の直前で区切る",
+			body: "確認済みです。\n＠法務部、＠佐藤。",
+			want: []CommentMention{
+				{Type: MentionDepartment, DepartmentID: intPtr(2), DepartmentName: "法務部"},
+				{Type: MentionUser, UserName: "佐藤"},
+			},
+		},
+		{
+			name: "重複は除く",
+			body: "@山田 @山田 @法務部 ＠法務部",
+			want: []CommentMention{
+				{Type: MentionUser, UserName: "山田"},
+				{Type: MentionDepartment, DepartmentID: intPtr(2), DepartmentName: "法務部"},
+			},
+		},
+		{
+			name: "メールアドレスはメンションにしない",
+			body: "security@example.com に送付済み",
+			want: []CommentMention{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseMentions(tt.body, departments)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseMentions() = %d件, want %d件", len(got), len(tt.want))
+			}
+			for i, m := range got {
+				w := tt.want[i]
+				if m.Type != w.Type || m.UserName != w.UserName || m.DepartmentName != w.DepartmentName {
+					t.Errorf("ParseMentions()[%d] = %+v, want %+v", i, *m, w)
+				}
+				if (m.DepartmentID == nil) != (w.DepartmentID == nil) || (m.DepartmentID != nil && *m.DepartmentID != *w.DepartmentID) {
+					t.Errorf("ParseMentions()[%d].DepartmentID = %v, want %v", i, m.DepartmentID, w.DepartmentID)
+				}
+			}
+		})
+	}
+}
+
+func TestKnowledgeComment_Edit(t *testing.T) {
+	t.Run("投稿者は編集できる", func(t *testing.T) {
+		comment := NewKnowledgeComment(1, "山田", "確認中です")
+		if err := comment.Edit("山田", " 確認しました "); err != nil {
+			t.Fatalf("Edit() error = %v", err)
+		}
+		if comment.Body != "確認しました" || !comment.Edited {
+			t.Errorf("Edit() Body = %q, Edited = %v", comment.Body, comment.Edited)
+		}
+	})
+
+	t.Run("投稿者以外は編集できない", func(t *testing.T) {
+		comment := NewKnowledgeComment(1, "山田", "確認中です")
+		err := comment.Edit("佐藤", "確認しました")
+		if !errors.Is(err, ErrNotCommentAuthor) {
+			t.Errorf("Edit() error = %v, want ErrNotCommentAuthor", err)
+		}
+		if comment.Body != "確認中です" {
+			t.Errorf("Edit() Body = %q, want unchanged", comment.Body)
+		}
+	})
+
+	t.Run("操作者を名乗らない場合は編集できない", func(t *testing.T) {
+		for _, comment := range []*KnowledgeComment{
+			NewKnowledgeComment(1, "山田", "確認中です"),
+			NewKnowledgeComment(1, AnonymousCommentAuthor, "確認中です"),
+		} {
+			for _, actor := range []string{"", " ", AnonymousCommentAuthor} {
+				if err := comment.Edit(actor, "確認しました"); !errors.Is(err, ErrNotCommentAuthor) {
+					t.Errorf("Edit(%q) on %q's comment error = %v, want ErrNotCommentAuthor", actor, comment.Author, err)
+				}
+				if err := comment.CanDelete(actor); !errors.Is(err, ErrNotCommentAuthor) {
+					t.Errorf("CanDelete(%q) on %q's comment error = %v, want ErrNotCommentAuthor", actor, comment.Author, err)
+				}
+			}
+		}
+	})
+
+	t.Run("空の本文にはできない", func(t *testing.T) {
+		comment := NewKnowledgeComment(1, "山田", "確認中です")
+		if err := comment.Edit("山田", "  "); err == nil {
+			t.Error("Edit() error = nil, want validation error")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 統制項目の紐づけ元
+const (
+	// ControlSourceItem はナレッジアイテムに直接紐づけた統制項目
+	ControlSourceItem = "item"
+	// ControlSourceQuestionGroup はアイテムの質問グループを通じて紐づく統制項目
+	ControlSourceQuestionGroup = "question_group"
+)
+
+// ControlFramework はISO 27001・SOC 2・CAIQなどの統制フレームワーク
+type ControlFramework struct {
+	ID      int    `json:"id"`
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Controls はカタログの読み込み時にのみ使う
+	Controls  []*Control `json:"controls,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Control はフレームワークに含まれる統制項目
+type Control struct {
+	ID            int    `json:"id"`
+	FrameworkID   int    `json:"framework_id"`
+	FrameworkCode string `json:"framework_code"`
+	// ControlID はフレームワーク内の統制番号（例: A.9.1.1、CC6.1、IAM-01）
+	ControlID    string `json:"control_id"`
+	Title        string `json:"title"`
+	Category     string `json:"category"`
+	Description  string `json:"description,omitempty"`
+	DisplayOrder int    `json:"display_order"`
+}
+
+// KnowledgeControl はナレッジアイテムに紐づく統制項目と紐づけ元
+type KnowledgeControl struct {
+	*Control
+	Source string `json:"source"`
+}
+
+// ControlCoverage は統制項目ごとの回答の有無
+type ControlCoverage struct {
+	*Control
+	// ItemCount は紐づくナレッジアイテム数、ApprovedCount はそのうち承認済み・公開済みの数
+	ItemCount     int  `json:"item_count"`
+	ApprovedCount int  `json:"approved_count"`
+	Covered       bool `json:"covered"`
+}
+
+// ControlCoverageReport はフレームワークの統制項目に対する回答の網羅状況
+type ControlCoverageReport struct {
+	Framework *ControlFramework `json:"framework"`
+	// Prefix は集計対象の統制番号の前方一致条件（例: A.9）
+	Prefix    string             `json:"prefix,omitempty"`
+	ProjectID int                `json:"project_id,omitempty"`
+	Total     int                `json:"total"`
+	Covered   int                `json:"covered"`
+	Rate      float64            `json:"rate"`
+	Controls  []*ControlCoverage `json:"controls"`
+}
+
+// ControlRepository は統制フレームワーク・統制項目リポジトリのインターフェース
+type ControlRepository interface {
+	// UpsertCatalog はフレームワークと統制項目をコード・統制番号で作成または更新する
+	UpsertCatalog(frameworks []*ControlFramework) error
+	GetFrameworks() ([]*ControlFramework, error)
+	GetFrameworkByCode(code string) (*ControlFramework, error)
+	GetControls(frameworkID int) ([]*Control, error)
+	// CountControls は指定されたIDのうち存在する統制項目の数を返す
+	CountControls(ids []int) (int, error)
+	// GetByKnowledgeID はアイテムに直接または質問グループを通じて紐づく統制項目を取得する
+	GetByKnowledgeID(knowledgeID int) ([]*KnowledgeControl, error)
+	ReplaceKnowledgeControls(knowledgeID int, controlIDs []int, actor string) error
+	GetByQuestionGroup(questionGroup string) ([]*Control, error)
+	ReplaceQuestionGroupControls(questionGroup string, controlIDs []int, actor string) error
+	// GetCoverage はフレームワークの統制項目ごとに紐づくアイテム数を集計する
+	// prefixが空でない場合は統制番号がprefixと一致するかprefixの下位の番号（prefix.x）のもの、projectIDが0以外の場合はその案件のアイテムに限定する
+	GetCoverage(frameworkID int, prefix string, projectID int) ([]*ControlCoverage, error)
+}
+
+// Validate はフレームワークと統制項目の内容を検証する
+func (f *ControlFramework) Validate() error {
+	if strings.TrimSpace(f.Code) == "" {
+		return &ValidationError{Field: "code", Message: "フレームワークのコードは必須です"}
+	}
+	if strings.TrimSpace(f.Name) == "" {
+		return &ValidationError{Field: "name", Message: fmt.Sprintf("フレームワークの名称は必須です: %s", f.Code)}
+	}
+
+	seen := make(map[string]bool, len(f.Controls))
+	for _, c := range f.Controls {
+		if strings.TrimSpace(c.ControlID) == "" {
+			return &ValidationError{Field: "control_id", Message: fmt.Sprintf("統制番号は必須です: %s", f.Code)}
+		}
+		if seen[c.ControlID] {
+			return &ValidationError{Field: "control_id", Message: fmt.Sprintf("統制番号が重複しています: %s %s", f.Code, c.ControlID)}
+		}
+		seen[c.ControlID] = true
+	}
+	return nil
+}
+
+// NewControlCoverageReport は統制項目ごとの集計から網羅状況を生成する
+// 承認済みまたは公開済みのアイテムが1件以上ある統制項目を回答済みとする
+func NewControlCoverageReport(framework *ControlFramework, prefix string, projectID int, coverage []*ControlCoverage) *ControlCoverageReport {
+	report := &ControlCoverageReport{
+		Framework: framework,
+		Prefix:    prefix,
+		ProjectID: projectID,
+		Total:     len(coverage),
+		Controls:  coverage,
+	}
+
+	for _, c := range coverage {
+		c.Covered = c.ApprovedCount > 0
+		if c.Covered {
+			report.Covered++
+		}
+	}
+	if report.Total > 0 {
+		report.Rate = float64(report.Covered) / float64(report.Total)
+	}
+
+	return report
+}
@@ -2,6 +2,9 @@ package domain
 
 import "time"
 
+// UnassignedDepartmentName は部門未設定のアイテムをまとめる際の表示名
+const UnassignedDepartmentName = "未設定"
+
 // Department は部門のドメインモデル
 type Department struct {
 	ID           int       `json:"id"`
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// EvidenceFile は顧客に提出するエビデンス（規程類のPDF、ペネトレーションテストの結果概要、認証の証明書など）のファイル
+// 案件のチェックシートとは独立して保管し、複数のナレッジアイテムから参照できる
+type EvidenceFile struct {
+	ID          int    `json:"id"`
+	FileName    string `json:"file_name"`
+	FilePath    string `json:"file_path"`
+	FileSize    int64  `json:"file_size"`
+	ContentType string `json:"content_type"`
+	Description string `json:"description"`
+	// ExpiresAt はエビデンスの有効期限（未設定の場合は期限なし）
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	UploadedBy string     `json:"uploaded_by"`
+	UploadedAt time.Time  `json:"uploaded_at"`
+	// ItemCount は紐づくナレッジアイテム数（一覧の取得時に集計する）
+	ItemCount int `json:"item_count"`
+}
+
+// KnowledgeEvidence はナレッジアイテムとエビデンスの紐づけ
+type KnowledgeEvidence struct {
+	KnowledgeItemID int `json:"knowledge_item_id"`
+	*EvidenceFile
+	LinkedBy string    `json:"linked_by"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// EvidenceRepository はエビデンスリポジトリのインターフェース
+type EvidenceRepository interface {
+	// CreateAndLink はエビデンスを登録し、ナレッジアイテムに紐づける
+	CreateAndLink(file *EvidenceFile, knowledgeID int, actor string) error
+	GetByID(id int) (*EvidenceFile, error)
+	// GetAll はすべてのエビデンスを紐づくアイテム数とともに有効期限の早い順に取得する
+	GetAll() ([]*EvidenceFile, error)
+	// Update は説明と有効期限を更新する
+	Update(file *EvidenceFile) error
+	// Link はナレッジアイテムにエビデンスを紐づける（紐づけ済みの場合は何もしない）
+	Link(knowledgeID int, evidenceID int, actor string) error
+	// Unlink は紐づけを解除し、どのアイテムからも参照されなくなったエビデンスを同じトランザクションで削除する
+	// エビデンスを削除した場合はtrueを返す。紐づけがない場合はsql.ErrNoRowsを返す
+	Unlink(knowledgeID int, evidenceID int) (bool, error)
+	GetByKnowledgeID(knowledgeID int) ([]*KnowledgeEvidence, error)
+	// GetByKnowledgeIDs は複数のアイテムに紐づくエビデンスをアイテムIDごとに取得する
+	GetByKnowledgeIDs(knowledgeIDs []int) (map[int][]*EvidenceFile, error)
+}
+
+// NewEvidenceFile は新しいエビデンスを生成する
+func NewEvidenceFile(fileName, filePath string, fileSize int64, contentType, description string, expiresAt *time.Time, uploadedBy string) *EvidenceFile {
+	return &EvidenceFile{
+		FileName:    fileName,
+		FilePath:    filePath,
+		FileSize:    fileSize,
+		ContentType: contentType,
+		Description: strings.TrimSpace(description),
+		ExpiresAt:   expiresAt,
+		UploadedBy:  uploadedBy,
+		UploadedAt:  time.Now(),
+	}
+}
+
+// Validate はエビデンスの妥当性を検証する
+func (f *EvidenceFile) Validate() error {
+	if f.FileName == "" {
+		return &ValidationError{Field: "file_name", Message: "ファイル名は必須です"}
+	}
+	if f.FilePath == "" {
+		return &ValidationError{Field: "file_path", Message: "ファイルパスは必須です"}
+	}
+	if len(f.Description) > 1000 {
+		return &ValidationError{Field: "description", Message: "説明は1000文字以内で入力してください"}
+	}
+	return nil
+}
+
+// IsExpired はエビデンスの有効期限がnow時点で過ぎているかどうかを返す
+func (f *EvidenceFile) IsExpired(now time.Time) bool {
+	return f.ExpiresAt != nil && !f.ExpiresAt.After(now)
+}
@@ -17,7 +17,11 @@ type UploadedFile struct {
 type FileRepository interface {
 	Create(file *UploadedFile) error
 	GetByID(id int) (*UploadedFile, error)
-	GetByProjectID(projectID int) ([]*UploadedFile, error)
+	// GetByIDIncludingTrash はゴミ箱のファイルも含めて取得する（ナレッジアイテムの取込元の表示用）
+	GetByIDIncludingTrash(id int) (*UploadedFile, error)
+	// GetByProjectID は案件のファイル一覧と総件数を取得する
+	GetByProjectID(projectID int, page PageRequest) ([]*UploadedFile, int, error)
+	// Delete はファイルをゴミ箱に移す
 	Delete(id int) error
 }
 
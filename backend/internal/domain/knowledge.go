@@ -2,35 +2,94 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
 // KnowledgeItem はナレッジQ/Aのドメインモデル
 type KnowledgeItem struct {
-	ID            int       `json:"id"`
-	ProjectID     int       `json:"project_id"`
-	FileID        *int      `json:"file_id,omitempty"`
-	SheetName     string    `json:"sheet_name"`
-	SourceRange   string    `json:"source_range"`
-	Question      string    `json:"question"`
-	Answer        string    `json:"answer"`
-	DepartmentID  *int      `json:"department_id,omitempty"`
-	QuestionGroup string    `json:"question_group"`
-	Status        string    `json:"status"`
-	Version       int       `json:"version"`
-	CreatedBy     string    `json:"created_by"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID          int    `json:"id"`
+	ProjectID   int    `json:"project_id"`
+	FileID      *int   `json:"file_id,omitempty"`
+	SheetName   string `json:"sheet_name"`
+	SourceRange string `json:"source_range"`
+	Question    string `json:"question"`
+	// Answer は主回答（回答バリエーションがある場合はそれらを連結したもの）
+	Answer string `json:"answer"`
+	// Answers は回答バリエーション（取得時に読み込まれる）
+	Answers       []*KnowledgeAnswer `json:"answers,omitempty"`
+	DepartmentID  *int               `json:"department_id,omitempty"`
+	QuestionGroup string             `json:"question_group"`
+	Status        string             `json:"status"`
+	// DerivedFrom は分割・統合で作成された場合の派生元（分割・統合の結果にのみ含まれる）
+	DerivedFrom []*KnowledgeLineage `json:"derived_from,omitempty"`
+	// RejectionReason は直近の差し戻し理由（rejected以外では空）
+	RejectionReason string `json:"rejection_reason,omitempty"`
+	// AnswerSourceID は推薦から回答をコピーした場合のコピー元ナレッジID
+	AnswerSourceID *int `json:"answer_source_id,omitempty"`
+	// CanonicalQuestionID は紐づく標準質問のID
+	CanonicalQuestionID *int `json:"canonical_question_id,omitempty"`
+	// AnswerDrifted は回答が紐づく標準質問の標準回答と異なるかどうか（保存時にDBで判定する）
+	AnswerDrifted bool `json:"answer_drifted"`
+	// ReviewDueAt は次回の定期レビューの期限、ValidUntil は回答の有効期限（いずれも未設定の場合は期限なし）
+	ReviewDueAt *time.Time `json:"review_due_at,omitempty"`
+	ValidUntil  *time.Time `json:"valid_until,omitempty"`
+	// ReviewIntervalDays は再レビューの承認時に次回のレビュー期限を決める間隔（未設定の場合はDefaultReviewIntervalDays）
+	ReviewIntervalDays *int `json:"review_interval_days,omitempty"`
+	// Assignee は回答の担当者、AssignmentDueAt は担当の期限、TaskState は担当タスクの状態
+	// 割り当ては内容の更新と独立して管理し、版を進めない
+	Assignee        string     `json:"assignee"`
+	AssignmentDueAt *time.Time `json:"assignment_due_at,omitempty"`
+	TaskState       string     `json:"task_state"`
+	Version         int        `json:"version"`
+	CreatedBy       string     `json:"created_by"`
+	UpdatedBy       string     `json:"updated_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // KnowledgeRepository はナレッジリポジトリのインターフェース
 type KnowledgeRepository interface {
 	Create(item *KnowledgeItem) error
+	CreateBatch(items []*KnowledgeItem, bestEffort bool) ([]error, error)
 	GetByID(id int) (*KnowledgeItem, error)
-	GetByProjectID(projectID int) ([]*KnowledgeItem, error)
-	Update(item *KnowledgeItem) error
+	// GetByProjectID は案件のナレッジ一覧と総件数を取得する（page.Limitが0の場合は全件）
+	GetByProjectID(projectID int, page PageRequest) ([]*KnowledgeItem, int, error)
+	// Update は版がexpectedVersionと一致する場合のみ更新し、一致しない場合はErrVersionConflictを返す
+	Update(item *KnowledgeItem, expectedVersion int) error
+	// Delete はアイテムをゴミ箱に移す（完全な削除は保持期間の経過後にTrashRepository.Purgeで行う）
 	Delete(id int) error
-	Search(query string, filters map[string]interface{}) ([]*KnowledgeItem, error)
+	// UpdateBatch は複数のアイテムの更新とステータス遷移の記録を1トランザクションで行う
+	// bestEffortの扱いと戻り値の[]errorはCreateBatchと同じ
+	UpdateBatch(updates []*KnowledgeBatchUpdate, bestEffort bool) ([]error, error)
+	// DeleteBatch は複数のアイテムを1トランザクションでゴミ箱に移す（存在しないIDはsql.ErrNoRows）
+	DeleteBatch(ids []int, bestEffort bool) ([]error, error)
+	// Search は条件に一致するナレッジと総件数を取得する（page.Limitが0の場合は全件）
+	Search(query string, filter KnowledgeSearchFilter, page PageRequest) ([]*KnowledgeItem, int, error)
+	// SearchFacets は条件に一致するナレッジのファセットごとの件数を集計する
+	SearchFacets(query string, filter KnowledgeSearchFilter) (*KnowledgeFacets, error)
+	// FindSimilar はpg_trgmの類似度で質問が似ているアイテムをスコアの高い順に取得する
+	FindSimilar(text string, opts SimilarSearchOptions) ([]*ScoredKnowledgeItem, error)
+	// FindSimilarBatch は複数のテキストの類似アイテムを1回のクエリで取得する（戻り値はtextsと同じ並び）
+	FindSimilarBatch(texts []string, opts SimilarSearchOptions) ([][]*ScoredKnowledgeItem, error)
+}
+
+// ErrVersionConflict は更新対象の版が既に他の更新で進んでいることを表す
+var ErrVersionConflict = errors.New("ナレッジアイテムは他のユーザーによって更新されています")
+
+// VersionConflictError は版の競合とサーバー上の最新の内容を表す
+type VersionConflictError struct {
+	Current *KnowledgeItem
+}
+
+func (e *VersionConflictError) Error() string {
+	return ErrVersionConflict.Error()
+}
+
+// Unwrap はerrors.IsでErrVersionConflictと判定できるようにする
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
 }
 
 // NewKnowledgeItem は新しいナレッジアイテムを生成する
@@ -53,9 +112,11 @@ func NewKnowledgeItem(
 		Question:     question,
 		Answer:       answer,
 		DepartmentID: departmentID,
-		Status:       "draft",
+		Status:       StatusDraft,
+		TaskState:    TaskTodo,
 		Version:      1,
 		CreatedBy:    createdBy,
+		UpdatedBy:    createdBy,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -80,13 +141,24 @@ func (k *KnowledgeItem) Validate() error {
 	}
 
 	// ステータスの検証
-	validStatuses := map[string]bool{
-		"draft":     true,
-		"published": true,
-		"archived":  true,
+	if k.Status != "" && !IsValidStatus(k.Status) {
+		return fmt.Errorf("ステータスは%sのいずれかである必要があります", strings.Join(knowledgeStatuses, ", "))
 	}
-	if k.Status != "" && !validStatuses[k.Status] {
-		return errors.New("ステータスはdraft, published, archivedのいずれかである必要があります")
+
+	if err := validateAssignee(k.Assignee); err != nil {
+		return err
+	}
+	if k.TaskState != "" && !IsValidTaskState(k.TaskState) {
+		return fmt.Errorf("タスクの状態は%sのいずれかである必要があります", strings.Join(taskStates, ", "))
+	}
+
+	if k.ReviewIntervalDays != nil && (*k.ReviewIntervalDays < 1 || *k.ReviewIntervalDays > MaxReviewIntervalDays) {
+		return fmt.Errorf("review_interval_daysは1以上%d以下である必要があります", MaxReviewIntervalDays)
+	}
+
+	// 有効期限を過ぎてからのレビューは意味がないため、レビュー期限は有効期限以前とする
+	if k.ReviewDueAt != nil && k.ValidUntil != nil && k.ReviewDueAt.After(*k.ValidUntil) {
+		return errors.New("review_due_atはvalid_until以前である必要があります")
 	}
 
 	return nil
@@ -106,14 +178,12 @@ func (k *KnowledgeItem) UpdateAnswer(answer string) {
 	k.UpdatedAt = time.Now()
 }
 
-// Publish はナレッジアイテムを公開する
-func (k *KnowledgeItem) Publish() {
-	k.Status = "published"
-	k.UpdatedAt = time.Now()
+// Publish は承認済みのナレッジアイテムを公開する（approved → published）
+func (k *KnowledgeItem) Publish(actor string) (*StatusTransition, error) {
+	return k.TransitionTo(StatusPublished, actor, "")
 }
 
 // Archive はナレッジアイテムをアーカイブする
-func (k *KnowledgeItem) Archive() {
-	k.Status = "archived"
-	k.UpdatedAt = time.Now()
+func (k *KnowledgeItem) Archive(actor string) (*StatusTransition, error) {
+	return k.TransitionTo(StatusArchived, actor, "")
 }
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// KnowledgeAnswer は1つの質問に対する回答のバリエーション（例: SaaSプラン／オンプレミスプラン）
+type KnowledgeAnswer struct {
+	ID              int       `json:"id"`
+	KnowledgeItemID int       `json:"knowledge_item_id"`
+	Label           string    `json:"label"`
+	Answer          string    `json:"answer"`
+	DepartmentID    *int      `json:"department_id,omitempty"`
+	DisplayOrder    int       `json:"display_order"`
+	Status          string    `json:"status"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// KnowledgeAnswerRepository は回答バリエーションリポジトリのインターフェース
+type KnowledgeAnswerRepository interface {
+	GetByKnowledgeID(knowledgeID int) ([]*KnowledgeAnswer, error)
+	GetByKnowledgeIDs(knowledgeIDs []int) (map[int][]*KnowledgeAnswer, error)
+	// Replace はナレッジアイテムの更新とitem.Answersによる回答一覧の置き換えを同じトランザクションで行う
+	Replace(item *KnowledgeItem, expectedVersion int) error
+}
+
+// Validate は回答バリエーションのバリデーションを行う
+func (a *KnowledgeAnswer) Validate() error {
+	if len(a.Label) > 100 {
+		return &ValidationError{Field: "label", Message: "ラベルは100文字以内で入力してください"}
+	}
+	if len(a.Answer) > 50000 {
+		return &ValidationError{Field: "answer", Message: "回答は50000文字以内で入力してください"}
+	}
+	if a.Status != "" && !IsValidStatus(a.Status) {
+		return &ValidationError{Field: "status", Message: "無効なステータスです: " + a.Status}
+	}
+	return nil
+}
+
+// SetAnswers は回答バリエーションを設定し、互換用のAnswerを合成し直す
+// 空の一覧を指定した場合はバリエーションを外し、Answerはそのまま残す
+func (k *KnowledgeItem) SetAnswers(answers []*KnowledgeAnswer) error {
+	if len(answers) == 0 {
+		k.Answers = []*KnowledgeAnswer{}
+		return nil
+	}
+
+	for i, a := range answers {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		if a.Status == "" {
+			a.Status = StatusDraft
+		}
+		if a.CreatedBy == "" {
+			a.CreatedBy = k.UpdatedBy
+		}
+		a.KnowledgeItemID = k.ID
+		// 表示順の指定がなければ並び順をそのまま使う
+		if a.DisplayOrder == 0 {
+			a.DisplayOrder = i + 1
+		}
+	}
+
+	sort.SliceStable(answers, func(i, j int) bool {
+		return answers[i].DisplayOrder < answers[j].DisplayOrder
+	})
+
+	k.Answers = answers
+	k.Answer = ComposeAnswer(answers)
+	return nil
+}
+
+// HasAnswerVariants は回答バリエーションを持つかどうかを返す
+func (k *KnowledgeItem) HasAnswerVariants() bool {
+	return len(k.Answers) > 0
+}
+
+// ComposeAnswer は回答バリエーションを1つの回答テキストに連結する
+// アーカイブ済みのバリエーションは含めず、ラベルがあれば見出しとして付与する
+func ComposeAnswer(answers []*KnowledgeAnswer) string {
+	active := make([]*KnowledgeAnswer, 0, len(answers))
+	for _, a := range answers {
+		if a.Status != StatusArchived {
+			active = append(active, a)
+		}
+	}
+
+	if len(active) == 1 && active[0].Label == "" {
+		return active[0].Answer
+	}
+
+	parts := make([]string, 0, len(active))
+	for _, a := range active {
+		if a.Label == "" {
+			parts = append(parts, a.Answer)
+			continue
+		}
+		parts = append(parts, "【"+a.Label+"】\n"+a.Answer)
+	}
+	return strings.Join(parts, "\n\n")
+}
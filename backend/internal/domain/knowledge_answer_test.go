@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestComposeAnswer(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []*KnowledgeAnswer
+		want    string
+	}{
+		{
+			name:    "ラベルなしの単一回答",
+			answers: []*KnowledgeAnswer{{Answer: "はい"}},
+			want:    "はい",
+		},
+		{
+			name: "ラベル付きの複数回答",
+			answers: []*KnowledgeAnswer{
+				{Label: "SaaSプラン", Answer: "はい"},
+				{Label: "オンプレミスプラン", Answer: "いいえ"},
+			},
+			want: "【SaaSプラン】\nはい\n\n【オンプレミスプラン】\nいいえ",
+		},
+		{
+			name: "アーカイブ済みは含めない",
+			answers: []*KnowledgeAnswer{
+				{Label: "SaaSプラン", Answer: "はい"},
+				{Label: "旧プラン", Answer: "いいえ", Status: StatusArchived},
+			},
+			want: "【SaaSプラン】\nはい",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComposeAnswer(tt.answers); got != tt.want {
+				t.Errorf("ComposeAnswer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKnowledgeItem_SetAnswers(t *testing.T) {
+	item := &KnowledgeItem{ID: 1, Answer: "元の回答", UpdatedBy: "山田太郎"}
+
+	err := item.SetAnswers([]*KnowledgeAnswer{
+		{Label: "オンプレミスプラン", Answer: "いいえ", DisplayOrder: 2},
+		{Label: "SaaSプラン", Answer: "はい", DisplayOrder: 1},
+	})
+	if err != nil {
+		t.Fatalf("SetAnswers() error = %v", err)
+	}
+
+	if item.Answers[0].Label != "SaaSプラン" {
+		t.Errorf("Answers[0].Label = %s, want SaaSプラン", item.Answers[0].Label)
+	}
+	if item.Answers[1].Status != StatusDraft || item.Answers[1].CreatedBy != "山田太郎" {
+		t.Errorf("Answers[1] = %+v, want draft created by 山田太郎", item.Answers[1])
+	}
+	if item.Answer != "【SaaSプラン】\nはい\n\n【オンプレミスプラン】\nいいえ" {
+		t.Errorf("Answer = %q", item.Answer)
+	}
+
+	// 空の一覧ではバリエーションを外し、主回答は残す
+	if err := item.SetAnswers(nil); err != nil {
+		t.Fatalf("SetAnswers(nil) error = %v", err)
+	}
+	if len(item.Answers) != 0 || item.Answer == "" {
+		t.Errorf("Answers = %v, Answer = %q", item.Answers, item.Answer)
+	}
+
+	if err := item.SetAnswers([]*KnowledgeAnswer{{Answer: "はい", Status: "unknown"}}); err == nil {
+		t.Error("SetAnswers() with invalid status should return error")
+	}
+}
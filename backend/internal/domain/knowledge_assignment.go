@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 担当タスクの状態
+const (
+	TaskTodo       = "todo"
+	TaskInProgress = "in_progress"
+	TaskDone       = "done"
+)
+
+// taskStates は有効なタスクの状態の一覧
+var taskStates = []string{TaskTodo, TaskInProgress, TaskDone}
+
+// MaxAssigneeLength は担当者名の最大文字数
+const MaxAssigneeLength = 255
+
+// IsValidTaskState はタスクの状態が有効かどうかを判定する
+func IsValidTaskState(state string) bool {
+	for _, s := range taskStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignmentTarget は一括割り当ての対象
+// KnowledgeIDsを指定した場合はそのアイテム、指定しない場合はDepartmentIDの部門のアイテムすべてを対象とする
+type AssignmentTarget struct {
+	KnowledgeIDs []int
+	DepartmentID *int
+}
+
+// AssignmentChange は一括割り当てで変更する内容（nilの項目は変更しない）
+type AssignmentChange struct {
+	DepartmentID *int
+	// Assignee は担当者（空文字で担当者を外す）
+	Assignee        *string
+	AssignmentDueAt *time.Time
+}
+
+// KnowledgeAssignmentRepository は担当割り当てと進捗のリポジトリのインターフェース
+type KnowledgeAssignmentRepository interface {
+	// BulkAssign は案件内の対象アイテムに割り当てを反映し、更新後のアイテムをID順に返す
+	// 部門を変更する場合は版を進めて履歴を記録する
+	// KnowledgeIDsに案件内に存在しないIDが含まれる場合は何も更新せずValidationErrorを返す
+	BulkAssign(projectID int, target AssignmentTarget, change AssignmentChange, actor string) ([]*KnowledgeItem, error)
+	// UpdateTask はアイテムの担当者・期限・タスクの状態を置き換える
+	UpdateTask(item *KnowledgeItem) error
+	// GetProgress は案件のアイテムを部門ごとに集計する（部門の表示順、部門未設定は最後）
+	GetProgress(projectID int, now time.Time) ([]*DepartmentProgress, error)
+}
+
+// ProgressCounts は進捗の集計値
+type ProgressCounts struct {
+	Total      int `json:"total"`
+	Answered   int `json:"answered"`
+	Unanswered int `json:"unanswered"`
+	// Approved はapprovedまたはpublishedのアイテム数
+	Approved   int `json:"approved"`
+	Todo       int `json:"todo"`
+	InProgress int `json:"in_progress"`
+	Done       int `json:"done"`
+	// Overdue は期限を過ぎて完了していないアイテム数
+	Overdue int `json:"overdue"`
+}
+
+// DepartmentProgress は部門ごとの進捗
+type DepartmentProgress struct {
+	// DepartmentID は部門未設定のアイテムの集計ではnil
+	DepartmentID   *int   `json:"department_id"`
+	DepartmentName string `json:"department_name"`
+	ProgressCounts
+}
+
+// ProjectProgress は案件全体と部門ごとの進捗
+type ProjectProgress struct {
+	ProjectID int `json:"project_id"`
+	ProgressCounts
+	Departments []*DepartmentProgress `json:"departments"`
+}
+
+// Validate は割り当ての変更内容を検証する
+func (a AssignmentChange) Validate() error {
+	if a.DepartmentID == nil && a.Assignee == nil && a.AssignmentDueAt == nil {
+		return &ValidationError{Field: "assignment", Message: "department_id、assignee、assignment_due_atのいずれかを指定してください"}
+	}
+	if a.Assignee != nil {
+		return validateAssignee(*a.Assignee)
+	}
+	return nil
+}
+
+// Validate は一括割り当ての対象を検証する
+func (t AssignmentTarget) Validate() error {
+	if len(t.KnowledgeIDs) == 0 && t.DepartmentID == nil {
+		return &ValidationError{Field: "knowledge_ids", Message: "knowledge_idsまたはfrom_department_idで対象を指定してください"}
+	}
+	return nil
+}
+
+// AssignTask はアイテムの担当者・期限・タスクの状態を設定する
+func (k *KnowledgeItem) AssignTask(assignee string, dueAt *time.Time, taskState string) error {
+	assignee = strings.TrimSpace(assignee)
+	if err := validateAssignee(assignee); err != nil {
+		return err
+	}
+	if taskState == "" {
+		taskState = TaskTodo
+	}
+	if !IsValidTaskState(taskState) {
+		return &ValidationError{Field: "task_state", Message: fmt.Sprintf("タスクの状態は%sのいずれかである必要があります", strings.Join(taskStates, ", "))}
+	}
+
+	k.Assignee = assignee
+	k.AssignmentDueAt = dueAt
+	k.TaskState = taskState
+	return nil
+}
+
+// validateAssignee は担当者名を検証する
+func validateAssignee(assignee string) error {
+	if len([]rune(assignee)) > MaxAssigneeLength {
+		return &ValidationError{Field: "assignee", Message: "担当者は255文字以内で入力してください"}
+	}
+	return nil
+}
+
+// SummarizeProgress は部門ごとの進捗を合計して案件全体の進捗にする
+func SummarizeProgress(projectID int, departments []*DepartmentProgress) *ProjectProgress {
+	progress := &ProjectProgress{ProjectID: projectID, Departments: departments}
+	for _, d := range departments {
+		d.Unanswered = d.Total - d.Answered
+		if d.DepartmentID == nil {
+			d.DepartmentName = UnassignedDepartmentName
+		}
+
+		progress.Total += d.Total
+		progress.Answered += d.Answered
+		progress.Unanswered += d.Unanswered
+		progress.Approved += d.Approved
+		progress.Todo += d.Todo
+		progress.InProgress += d.InProgress
+		progress.Done += d.Done
+		progress.Overdue += d.Overdue
+	}
+	return progress
+}
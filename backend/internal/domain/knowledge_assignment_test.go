@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKnowledgeItem_AssignTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		assignee  string
+		taskState string
+		wantState string
+		wantErr   bool
+	}{
+		{name: "状態を省略するとtodo", assignee: " 山田 ", wantState: TaskTodo},
+		{name: "完了", assignee: "山田", taskState: TaskDone, wantState: TaskDone},
+		{name: "担当者なしでも状態は設定できる", taskState: TaskInProgress, wantState: TaskInProgress},
+		{name: "無効な状態", assignee: "山田", taskState: "closed", wantErr: true},
+		{name: "担当者名が長すぎる", assignee: strings.Repeat("あ", MaxAssigneeLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &KnowledgeItem{ID: 1, TaskState: TaskTodo}
+			err := item.AssignTask(tt.assignee, nil, tt.taskState)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AssignTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if item.Assignee != strings.TrimSpace(tt.assignee) {
+				t.Errorf("AssignTask() Assignee = %q", item.Assignee)
+			}
+			if item.TaskState != tt.wantState {
+				t.Errorf("AssignTask() TaskState = %q, want %q", item.TaskState, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestAssignmentChange_Validate(t *testing.T) {
+	empty := ""
+	if err := (AssignmentChange{}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for empty change")
+	}
+	if err := (AssignmentChange{Assignee: &empty}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil for unassigning", err)
+	}
+	if err := (AssignmentTarget{}).Validate(); err == nil {
+		t.Error("AssignmentTarget.Validate() error = nil, want error for empty target")
+	}
+}
+
+func TestSummarizeProgress(t *testing.T) {
+	departments := []*DepartmentProgress{
+		{DepartmentID: intPtr(1), DepartmentName: "情報システム部", ProgressCounts: ProgressCounts{Total: 10, Answered: 7, Approved: 4, Todo: 2, InProgress: 3, Done: 5, Overdue: 1}},
+		{DepartmentID: nil, ProgressCounts: ProgressCounts{Total: 3, Answered: 0, Todo: 3}},
+	}
+
+	progress := SummarizeProgress(5, departments)
+
+	if progress.ProjectID != 5 {
+		t.Errorf("ProjectID = %d, want 5", progress.ProjectID)
+	}
+	want := ProgressCounts{Total: 13, Answered: 7, Unanswered: 6, Approved: 4, Todo: 5, InProgress: 3, Done: 5, Overdue: 1}
+	if progress.ProgressCounts != want {
+		t.Errorf("ProgressCounts = %+v, want %+v", progress.ProgressCounts, want)
+	}
+	if departments[0].Unanswered != 3 {
+		t.Errorf("Departments[0].Unanswered = %d, want 3", departments[0].Unanswered)
+	}
+	if departments[1].DepartmentName != UnassignedDepartmentName {
+		t.Errorf("Departments[1].DepartmentName = %q, want %q", departments[1].DepartmentName, UnassignedDepartmentName)
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// KnowledgeBulkPatch は一括更新で変更する項目（nilの項目は変更しない）
+type KnowledgeBulkPatch struct {
+	// ProjectID は移動先の案件
+	ProjectID *int
+	// DepartmentID は付与する部門（有効な部門に限る）
+	DepartmentID  *int
+	QuestionGroup *string
+	// Status はワークフローの遷移規則に従って変更する
+	Status *string
+	// Reason はStatusをrejectedにする場合の差し戻し理由
+	Reason string
+}
+
+// KnowledgeBatchUpdate は一括更新の1件分
+type KnowledgeBatchUpdate struct {
+	// Item は変更を反映したアイテム
+	Item *KnowledgeItem
+	// ExpectedVersion は読み込み時の版（DB上の版と一致しない場合は競合として失敗する）
+	ExpectedVersion int
+	// Transition はステータスを変更した場合の遷移の記録（変更しない場合はnil）
+	Transition *StatusTransition
+}
+
+// IsEmpty は変更する項目がないかどうかを返す
+func (p KnowledgeBulkPatch) IsEmpty() bool {
+	return p.ProjectID == nil && p.DepartmentID == nil && p.QuestionGroup == nil && p.Status == nil
+}
+
+// Validate は一括更新の内容を検証する
+func (p KnowledgeBulkPatch) Validate() error {
+	if p.IsEmpty() {
+		return &ValidationError{Field: "patch", Message: "project_id、department_id、question_group、statusのいずれかを指定してください"}
+	}
+	if p.Status != nil && !IsValidStatus(*p.Status) {
+		return &ValidationError{Field: "status", Message: "無効なステータスです: " + *p.Status}
+	}
+	return nil
+}
+
+// Apply はアイテムに一括更新の内容を反映し、版を1つ進める
+// ステータスを変更した場合は遷移の記録を返す。遷移できない場合はエラーを返す
+func (p KnowledgeBulkPatch) Apply(k *KnowledgeItem, actor string) (*StatusTransition, error) {
+	if p.ProjectID != nil && *p.ProjectID != k.ProjectID {
+		k.ProjectID = *p.ProjectID
+		// 取込元のファイルは案件に属するため、他の案件に移動した場合は紐づけを外す
+		k.FileID = nil
+	}
+	if p.DepartmentID != nil {
+		departmentID := *p.DepartmentID
+		k.DepartmentID = &departmentID
+	}
+	if p.QuestionGroup != nil {
+		k.QuestionGroup = strings.TrimSpace(*p.QuestionGroup)
+	}
+
+	var transition *StatusTransition
+	if p.Status != nil && *p.Status != k.currentStatus() {
+		var err error
+		switch *p.Status {
+		case StatusApproved:
+			transition, err = k.Approve(actor)
+		default:
+			transition, err = k.TransitionTo(*p.Status, actor, p.Reason)
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// ステータスの遷移では版が進むため、遷移しない場合のみここで進める
+		k.UpdatedBy = actor
+		k.Version++
+		k.UpdatedAt = time.Now()
+	}
+
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return transition, nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "testing"
+
+func TestKnowledgeBulkPatch_Apply(t *testing.T) {
+	t.Run("部門と質問グループを変更すると版を1つ進める", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: StatusDraft, Version: 3}
+		group := " アクセス管理 "
+		transition, err := KnowledgeBulkPatch{DepartmentID: intPtr(2), QuestionGroup: &group}.Apply(item, "山田")
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if transition != nil {
+			t.Errorf("Apply() transition = %+v, want nil", transition)
+		}
+		if *item.DepartmentID != 2 || item.QuestionGroup != "アクセス管理" || item.Version != 4 || item.UpdatedBy != "山田" {
+			t.Errorf("Apply() item = %+v", item)
+		}
+	})
+
+	t.Run("ステータスの変更は遷移として記録する", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: StatusDraft, Version: 3}
+		status := StatusInReview
+		transition, err := KnowledgeBulkPatch{Status: &status}.Apply(item, "山田")
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if transition == nil || transition.FromStatus != StatusDraft || transition.ToStatus != StatusInReview {
+			t.Errorf("Apply() transition = %+v", transition)
+		}
+		if item.Version != 4 {
+			t.Errorf("Apply() Version = %d, want 4", item.Version)
+		}
+	})
+
+	t.Run("同じステータスへの変更は遷移しない", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: StatusPublished, Version: 3}
+		status := StatusPublished
+		transition, err := KnowledgeBulkPatch{Status: &status}.Apply(item, "山田")
+		if err != nil || transition != nil {
+			t.Errorf("Apply() = %+v, %v, want nil, nil", transition, err)
+		}
+	})
+
+	t.Run("差し戻しには理由が必要", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: StatusInReview, Version: 3}
+		status := StatusRejected
+		if _, err := (KnowledgeBulkPatch{Status: &status}).Apply(item, "山田"); err == nil {
+			t.Error("Apply() error = nil, want error without reason")
+		}
+	})
+
+	t.Run("他の案件へ移動すると取込元ファイルの紐づけを外す", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, FileID: intPtr(7), Question: "質問", Status: StatusDraft, Version: 1}
+		if _, err := (KnowledgeBulkPatch{ProjectID: intPtr(2)}).Apply(item, "山田"); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if item.ProjectID != 2 || item.FileID != nil {
+			t.Errorf("Apply() ProjectID = %d, FileID = %v", item.ProjectID, item.FileID)
+		}
+	})
+}
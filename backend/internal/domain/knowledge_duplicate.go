@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 重複検出の既定値
+const (
+	DefaultDuplicateThreshold = 0.8
+)
+
+// 重複の解消方法
+const (
+	// DuplicateResolveMerge は残すアイテムに重複の内容を取り込み、派生元として記録してからゴミ箱に移す
+	DuplicateResolveMerge = "merge"
+	// DuplicateResolveDelete は重複を派生記録を残さずにゴミ箱に移す
+	DuplicateResolveDelete = "delete"
+)
+
+// DuplicatePair は重複と判定された2つのアイテムの組
+type DuplicatePair struct {
+	ItemID  int
+	OtherID int
+	// Exact は正規化した質問が完全に一致するかどうか
+	Exact bool
+	// Score は正規化した質問のsimilarity()（完全一致の場合は1）
+	Score float64
+}
+
+// DuplicateGroup は互いに重複するアイテムのまとまり
+type DuplicateGroup struct {
+	Items []*KnowledgeItem `json:"items"`
+	// Exact はグループ内のすべての組が正規化した質問の完全一致かどうか
+	Exact bool `json:"exact"`
+	// MinScore はグループを構成する組の類似度の最小値
+	MinScore float64 `json:"min_score"`
+}
+
+// DuplicateResolution は重複の解消内容
+type DuplicateResolution struct {
+	// KeepID は残すアイテムのID
+	KeepID int
+	// DuplicateIDs はゴミ箱に移す重複のID
+	DuplicateIDs []int
+	Action       string
+}
+
+// KnowledgeDuplicateRepository は重複検出リポジトリのインターフェース
+type KnowledgeDuplicateRepository interface {
+	// FindDuplicatePairs は案件内で正規化した質問が一致するか、類似度が閾値以上のアイテムの組を取得する
+	FindDuplicatePairs(projectID int, threshold float64) ([]*DuplicatePair, error)
+	// Resolve は重複をゴミ箱に移し、mergeの場合は残すアイテムの更新と派生記録の作成を同じトランザクションで行う
+	// アイテムの版が読み込み時から変わっている場合はErrVersionConflictを返す
+	Resolve(action string, keep *KnowledgeItem, expectedVersion int, duplicates []*KnowledgeItem, actor string) error
+}
+
+// Validate は解消内容を検証する
+func (r *DuplicateResolution) Validate() error {
+	if r.Action != DuplicateResolveMerge && r.Action != DuplicateResolveDelete {
+		return &ValidationError{Field: "action", Message: "actionにはmergeまたはdeleteを指定してください"}
+	}
+	if len(r.DuplicateIDs) == 0 {
+		return &ValidationError{Field: "duplicate_ids", Message: "duplicate_idsは1件以上指定してください"}
+	}
+
+	seen := map[int]bool{r.KeepID: true}
+	for _, id := range r.DuplicateIDs {
+		if seen[id] {
+			return &ValidationError{Field: "duplicate_ids", Message: fmt.Sprintf("IDが重複しているか、keep_idと同じです: %d", id)}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// ClusterDuplicates は重複の組を連結し、互いに重複するアイテムのグループにまとめる
+// グループはアイテムID順に並べ、グループ同士は先頭のアイテムIDの順に並べる
+func ClusterDuplicates(items []*KnowledgeItem, pairs []*DuplicatePair) []*DuplicateGroup {
+	byID := make(map[int]*KnowledgeItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	parent := map[int]int{}
+	var find func(id int) int
+	find = func(id int) int {
+		if p, ok := parent[id]; ok && p != id {
+			parent[id] = find(p)
+			return parent[id]
+		}
+		parent[id] = id
+		return id
+	}
+
+	for _, pair := range pairs {
+		if byID[pair.ItemID] == nil || byID[pair.OtherID] == nil {
+			continue
+		}
+		a, b := find(pair.ItemID), find(pair.OtherID)
+		if a != b {
+			if b < a {
+				a, b = b, a
+			}
+			parent[b] = a
+		}
+	}
+
+	groups := map[int]*DuplicateGroup{}
+	for _, pair := range pairs {
+		if byID[pair.ItemID] == nil || byID[pair.OtherID] == nil {
+			continue
+		}
+		root := find(pair.ItemID)
+		group, ok := groups[root]
+		if !ok {
+			group = &DuplicateGroup{Exact: true, MinScore: pair.Score}
+			groups[root] = group
+		}
+		group.Exact = group.Exact && pair.Exact
+		if pair.Score < group.MinScore {
+			group.MinScore = pair.Score
+		}
+	}
+
+	for id := range parent {
+		if group, ok := groups[find(id)]; ok {
+			group.Items = append(group.Items, byID[id])
+		}
+	}
+
+	result := make([]*DuplicateGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Slice(group.Items, func(i, j int) bool { return group.Items[i].ID < group.Items[j].ID })
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Items[0].ID < result[j].Items[0].ID })
+
+	return result
+}
+
+// AbsorbDuplicates は重複の内容を残すアイテムに取り込む
+// 回答・部門・質問グループが未設定の場合のみ、重複のうち最初に値を持つものから引き継ぐ
+// 回答はレビューを経る必要があるため、残すアイテムが回答を編集できるステータス（draft/rejected）の場合のみ引き継ぐ
+func (k *KnowledgeItem) AbsorbDuplicates(duplicates []*KnowledgeItem, actor string) {
+	for _, d := range duplicates {
+		if k.NeedsAnswer() && !d.NeedsAnswer() && k.IsContentEditable() {
+			k.Answer = d.Answer
+		}
+		if k.DepartmentID == nil && d.DepartmentID != nil {
+			k.DepartmentID = d.DepartmentID
+		}
+		if k.QuestionGroup == "" {
+			k.QuestionGroup = d.QuestionGroup
+		}
+	}
+
+	k.UpdatedBy = actor
+	k.Version++
+	k.UpdatedAt = time.Now()
+}
@@ -0,0 +1,92 @@
+package domain
+
+import "testing"
+
+func TestClusterDuplicates(t *testing.T) {
+	items := []*KnowledgeItem{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	pairs := []*DuplicatePair{
+		{ItemID: 3, OtherID: 5, Score: 0.85},
+		{ItemID: 1, OtherID: 2, Exact: true, Score: 1},
+		{ItemID: 2, OtherID: 5, Score: 0.9},
+	}
+
+	groups := ClusterDuplicates(items, pairs)
+	if len(groups) != 1 {
+		t.Fatalf("len(groups) = %d, want 1", len(groups))
+	}
+
+	ids := []int{}
+	for _, item := range groups[0].Items {
+		ids = append(ids, item.ID)
+	}
+	if len(ids) != 4 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 || ids[3] != 5 {
+		t.Errorf("group ids = %v, want [1 2 3 5]", ids)
+	}
+	if groups[0].Exact {
+		t.Error("Exact = true, want false")
+	}
+	if groups[0].MinScore != 0.85 {
+		t.Errorf("MinScore = %v, want 0.85", groups[0].MinScore)
+	}
+}
+
+func TestClusterDuplicates_SeparateGroups(t *testing.T) {
+	items := []*KnowledgeItem{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	pairs := []*DuplicatePair{
+		{ItemID: 3, OtherID: 4, Exact: true, Score: 1},
+		{ItemID: 1, OtherID: 2, Exact: true, Score: 1},
+	}
+
+	groups := ClusterDuplicates(items, pairs)
+	if len(groups) != 2 {
+		t.Fatalf("len(groups) = %d, want 2", len(groups))
+	}
+	if groups[0].Items[0].ID != 1 || groups[1].Items[0].ID != 3 {
+		t.Errorf("groups are not ordered by first item ID")
+	}
+	if !groups[0].Exact || !groups[1].Exact {
+		t.Error("Exact = false, want true")
+	}
+}
+
+func TestAbsorbDuplicates(t *testing.T) {
+	deptID := 2
+	keep := &KnowledgeItem{ID: 1, Question: "質問", Version: 3}
+	duplicates := []*KnowledgeItem{
+		{ID: 2, Question: "質問", QuestionGroup: "認証"},
+		{ID: 3, Question: "質問", Answer: "回答", DepartmentID: &deptID},
+	}
+
+	keep.AbsorbDuplicates(duplicates, "山田太郎")
+
+	if keep.Answer != "回答" {
+		t.Errorf("Answer = %q, want %q", keep.Answer, "回答")
+	}
+	if keep.DepartmentID == nil || *keep.DepartmentID != deptID {
+		t.Errorf("DepartmentID = %v, want %d", keep.DepartmentID, deptID)
+	}
+	if keep.QuestionGroup != "認証" {
+		t.Errorf("QuestionGroup = %q, want %q", keep.QuestionGroup, "認証")
+	}
+	if keep.Version != 4 || keep.UpdatedBy != "山田太郎" {
+		t.Errorf("Version = %d, UpdatedBy = %q", keep.Version, keep.UpdatedBy)
+	}
+}
+
+func TestAbsorbDuplicates_NotEditable(t *testing.T) {
+	deptID := 2
+	keep := &KnowledgeItem{ID: 1, Question: "質問", Status: StatusApproved, Version: 3}
+	duplicates := []*KnowledgeItem{
+		{ID: 3, Question: "質問", Answer: "回答", DepartmentID: &deptID},
+	}
+
+	keep.AbsorbDuplicates(duplicates, "山田太郎")
+
+	// 承認済みのアイテムにはレビューを経ない回答を引き継がない
+	if keep.Answer != "" {
+		t.Errorf("Answer = %q, want empty", keep.Answer)
+	}
+	if keep.DepartmentID == nil || *keep.DepartmentID != deptID {
+		t.Errorf("DepartmentID = %v, want %d", keep.DepartmentID, deptID)
+	}
+}
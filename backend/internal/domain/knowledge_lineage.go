@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 派生の種類
+const (
+	LineageSplit = "split"
+	LineageMerge = "merge"
+)
+
+// KnowledgeLineage は分割・統合で作成されたナレッジアイテムの派生元（derived_from）
+// 派生元は分割・統合時にゴミ箱に移され、保持期間を過ぎると完全に削除されるため、元の位置と内容を記録しておく
+type KnowledgeLineage struct {
+	ID              int `json:"id"`
+	KnowledgeItemID int `json:"knowledge_item_id"`
+	// SourceItemID は派生元のID（派生元が完全に削除された場合はnil）
+	SourceItemID    *int      `json:"source_item_id"`
+	Operation       string    `json:"operation"`
+	SourceSheetName string    `json:"source_sheet_name"`
+	SourceRange     string    `json:"source_range"`
+	SourceQuestion  string    `json:"source_question"`
+	SourceAnswer    string    `json:"source_answer"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// KnowledgeLineageRepository は派生記録リポジトリのインターフェース
+type KnowledgeLineageRepository interface {
+	// Derive は派生元のゴミ箱への移動、派生先の作成、派生記録の作成を同じトランザクションで行う
+	// 派生元の版が読み込み時から変わっている場合はErrVersionConflictを返す
+	Derive(operation string, sources []*KnowledgeItem, derived []*KnowledgeItem, actor string) error
+	GetByKnowledgeID(knowledgeID int) ([]*KnowledgeLineage, error)
+}
+
+// SplitSegment は分割後の1件分の質問と回答
+type SplitSegment struct {
+	Question     string `json:"question"`
+	Answer       string `json:"answer"`
+	DepartmentID *int   `json:"department_id"`
+}
+
+// NewKnowledgeLineage は派生元の内容を記録した派生記録を生成する
+func NewKnowledgeLineage(operation string, source *KnowledgeItem, actor string) *KnowledgeLineage {
+	sourceID := source.ID
+	return &KnowledgeLineage{
+		SourceItemID:    &sourceID,
+		Operation:       operation,
+		SourceSheetName: source.SheetName,
+		SourceRange:     source.SourceRange,
+		SourceQuestion:  source.Question,
+		SourceAnswer:    source.Answer,
+		CreatedBy:       actor,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// SplitKnowledgeItem は1つのセルに含まれる複数の質問を、セグメントごとのナレッジアイテムに分割する
+// 分割後のアイテムは元のシート名・範囲・ファイル・質問グループを引き継ぐ
+func SplitKnowledgeItem(source *KnowledgeItem, segments []SplitSegment, actor string) ([]*KnowledgeItem, error) {
+	if len(segments) < 2 {
+		return nil, &ValidationError{Field: "segments", Message: "分割には2つ以上のセグメントが必要です"}
+	}
+
+	items := make([]*KnowledgeItem, 0, len(segments))
+	for i, segment := range segments {
+		departmentID := segment.DepartmentID
+		if departmentID == nil {
+			departmentID = source.DepartmentID
+		}
+
+		item := NewKnowledgeItem(
+			source.ProjectID,
+			source.FileID,
+			source.SheetName,
+			source.SourceRange,
+			strings.TrimSpace(segment.Question),
+			segment.Answer,
+			departmentID,
+			actor,
+		)
+		item.QuestionGroup = source.QuestionGroup
+
+		if err := item.Validate(); err != nil {
+			return nil, &ValidationError{Field: "segments", Message: fmt.Sprintf("セグメント%d: %s", i+1, err.Error())}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// MergeKnowledgeItems は同じ案件の複数のナレッジアイテムを1つに統合する
+// questionとanswerが空の場合は元の内容を順に連結する
+func MergeKnowledgeItems(sources []*KnowledgeItem, question string, answer string, actor string) (*KnowledgeItem, error) {
+	if len(sources) < 2 {
+		return nil, &ValidationError{Field: "ids", Message: "統合には2つ以上のナレッジアイテムが必要です"}
+	}
+
+	first := sources[0]
+	questions := make([]string, 0, len(sources))
+	answers := make([]string, 0, len(sources))
+	ranges := make([]string, 0, len(sources))
+	sameSheet, sameFile, sameDepartment := true, true, true
+	for _, source := range sources {
+		if source.ProjectID != first.ProjectID {
+			return nil, &ValidationError{Field: "ids", Message: "異なる案件のナレッジアイテムは統合できません"}
+		}
+
+		questions = append(questions, source.Question)
+		if source.Answer != "" && !containsString(answers, source.Answer) {
+			answers = append(answers, source.Answer)
+		}
+		if source.SourceRange != "" && !containsString(ranges, source.SourceRange) {
+			ranges = append(ranges, source.SourceRange)
+		}
+
+		sameSheet = sameSheet && source.SheetName == first.SheetName
+		sameFile = sameFile && equalIntPtr(source.FileID, first.FileID)
+		sameDepartment = sameDepartment && equalIntPtr(source.DepartmentID, first.DepartmentID)
+	}
+
+	if question == "" {
+		question = strings.Join(questions, "\n")
+	}
+	if answer == "" {
+		answer = strings.Join(answers, "\n\n")
+	}
+
+	var fileID, departmentID *int
+	if sameFile {
+		fileID = first.FileID
+	}
+	if sameDepartment {
+		departmentID = first.DepartmentID
+	}
+
+	// 同じシート内の統合であれば範囲を列挙し、それ以外は先頭の位置を残す（全件の位置は派生記録に残る）
+	sourceRange := first.SourceRange
+	if joined := strings.Join(ranges, ","); sameSheet && len(joined) <= 100 {
+		sourceRange = joined
+	}
+
+	merged := NewKnowledgeItem(first.ProjectID, fileID, first.SheetName, sourceRange, question, answer, departmentID, actor)
+	merged.QuestionGroup = first.QuestionGroup
+
+	if err := merged.Validate(); err != nil {
+		return nil, &ValidationError{Field: "ids", Message: err.Error()}
+	}
+
+	return merged, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
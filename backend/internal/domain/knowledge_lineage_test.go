@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestSplitKnowledgeItem(t *testing.T) {
+	fileID := 3
+	deptID := 2
+	otherDeptID := 5
+	source := &KnowledgeItem{
+		ID:            10,
+		ProjectID:     1,
+		FileID:        &fileID,
+		SheetName:     "Sheet1",
+		SourceRange:   "B12",
+		Question:      "パスワードポリシーはありますか？また、多要素認証を使用していますか？",
+		DepartmentID:  &deptID,
+		QuestionGroup: "認証",
+		Status:        StatusPublished,
+		Version:       4,
+	}
+
+	items, err := SplitKnowledgeItem(source, []SplitSegment{
+		{Question: "パスワードポリシーはありますか？", Answer: "はい"},
+		{Question: " 多要素認証を使用していますか？ ", Answer: "はい", DepartmentID: &otherDeptID},
+	}, "山田太郎")
+	if err != nil {
+		t.Fatalf("SplitKnowledgeItem() error = %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(items))
+	}
+	for _, item := range items {
+		// 分割後も元の位置を引き継ぐ
+		if item.SheetName != "Sheet1" || item.SourceRange != "B12" || item.FileID != &fileID {
+			t.Errorf("位置が引き継がれていません: %+v", item)
+		}
+		if item.Status != StatusDraft || item.Version != 1 || item.QuestionGroup != "認証" {
+			t.Errorf("Status/Version/QuestionGroup = %s/%d/%s", item.Status, item.Version, item.QuestionGroup)
+		}
+	}
+	if items[1].Question != "多要素認証を使用していますか？" {
+		t.Errorf("Question = %q", items[1].Question)
+	}
+	if *items[0].DepartmentID != 2 || *items[1].DepartmentID != 5 {
+		t.Errorf("DepartmentID = %d/%d, want 2/5", *items[0].DepartmentID, *items[1].DepartmentID)
+	}
+
+	if _, err := SplitKnowledgeItem(source, []SplitSegment{{Question: "質問"}}, "山田太郎"); err == nil {
+		t.Error("1セグメントでの分割はエラーになるべきです")
+	}
+	if _, err := SplitKnowledgeItem(source, []SplitSegment{{Question: "質問"}, {Question: " "}}, "山田太郎"); err == nil {
+		t.Error("空の質問を含む分割はエラーになるべきです")
+	}
+}
+
+func TestMergeKnowledgeItems(t *testing.T) {
+	deptID := 2
+	sources := []*KnowledgeItem{
+		{ID: 1, ProjectID: 1, SheetName: "Sheet1", SourceRange: "B12", Question: "暗号化していますか？", Answer: "はい", DepartmentID: &deptID},
+		{ID: 2, ProjectID: 1, SheetName: "Sheet1", SourceRange: "B13", Question: "（保存時）", Answer: "はい", DepartmentID: &deptID},
+	}
+
+	merged, err := MergeKnowledgeItems(sources, "", "", "山田太郎")
+	if err != nil {
+		t.Fatalf("MergeKnowledgeItems() error = %v", err)
+	}
+
+	if merged.Question != "暗号化していますか？\n（保存時）" {
+		t.Errorf("Question = %q", merged.Question)
+	}
+	// 同じ回答は重複させない
+	if merged.Answer != "はい" {
+		t.Errorf("Answer = %q, want はい", merged.Answer)
+	}
+	if merged.SheetName != "Sheet1" || merged.SourceRange != "B12,B13" {
+		t.Errorf("SheetName/SourceRange = %s/%s, want Sheet1/B12,B13", merged.SheetName, merged.SourceRange)
+	}
+	if merged.DepartmentID == nil || *merged.DepartmentID != 2 {
+		t.Errorf("DepartmentID = %v, want 2", merged.DepartmentID)
+	}
+
+	// 質問・回答を指定した場合はそちらを使う
+	merged, err = MergeKnowledgeItems(sources, "保存データを暗号化していますか？", "AES-256で暗号化しています", "山田太郎")
+	if err != nil {
+		t.Fatalf("MergeKnowledgeItems() error = %v", err)
+	}
+	if merged.Question != "保存データを暗号化していますか？" || merged.Answer != "AES-256で暗号化しています" {
+		t.Errorf("Question/Answer = %q/%q", merged.Question, merged.Answer)
+	}
+
+	sources[1].ProjectID = 2
+	if _, err := MergeKnowledgeItems(sources, "", "", "山田太郎"); err == nil {
+		t.Error("異なる案件の統合はエラーになるべきです")
+	}
+}
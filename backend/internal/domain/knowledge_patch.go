@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Optional は部分更新で値を消せる項目
+// JSONで省略した場合はSetがfalse（変更しない）、nullを指定した場合はSetがtrueでValueがnil（値を消す）になる
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON は項目が指定されたことを記録して値を読み込む
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = &value
+	return nil
+}
+
+// KnowledgePatch はナレッジアイテムの部分更新で変更する項目（nil・未指定の項目は変更しない）
+// 案件・取込元ファイル・作成者、担当の割り当ては変更できない
+type KnowledgePatch struct {
+	SheetName   *string
+	SourceRange *string
+	Question    *string
+	Answer      *string
+	// Answers は回答バリエーションの置き換え（IDのない回答は追加、含まれない回答は削除）
+	Answers       []*KnowledgeAnswer
+	DepartmentID  Optional[int]
+	QuestionGroup *string
+	ReviewDueAt   Optional[time.Time]
+	ValidUntil    Optional[time.Time]
+	// ReviewIntervalDays はnullを指定すると既定の間隔に戻す
+	ReviewIntervalDays Optional[int]
+	// Status は現在のステータスと同じ場合のみ受け付ける（変更はワークフローで行う）
+	Status    *string
+	UpdatedBy string
+}
+
+// IsEmpty は変更する項目がないかどうかを返す
+func (p KnowledgePatch) IsEmpty() bool {
+	return p.SheetName == nil && p.SourceRange == nil && p.Question == nil && p.Answer == nil &&
+		p.Answers == nil && !p.DepartmentID.Set && p.QuestionGroup == nil &&
+		!p.ReviewDueAt.Set && !p.ValidUntil.Set && !p.ReviewIntervalDays.Set && p.Status == nil
+}
+
+// changesContent は質問・回答を変更するかどうかを返す
+func (p KnowledgePatch) changesContent(k *KnowledgeItem) bool {
+	return (p.Question != nil && *p.Question != k.Question) ||
+		(p.Answer != nil && *p.Answer != k.Answer) ||
+		p.Answers != nil
+}
+
+// IsContentEditable は質問・回答を編集できるステータス（draft/rejected）かどうかを返す
+// レビュー中・承認済み・公開済みの内容をレビューを経ずに変更できないようにする
+func (k *KnowledgeItem) IsContentEditable() bool {
+	status := k.currentStatus()
+	return status == StatusDraft || status == StatusRejected
+}
+
+// ApplyPatch はアイテムに指定された項目だけを反映する
+// 質問・回答の変更はdraft/rejectedのアイテムに限る。反映後の検証は呼び出し側でValidateを呼んで行う
+func (k *KnowledgeItem) ApplyPatch(p KnowledgePatch) error {
+	if p.IsEmpty() {
+		return &ValidationError{Field: "patch", Message: "更新する項目を指定してください"}
+	}
+	if p.Status != nil && *p.Status != k.Status {
+		return &ValidationError{Field: "status", Message: "ステータスはワークフローのエンドポイントで変更してください"}
+	}
+	if p.changesContent(k) && !k.IsContentEditable() {
+		return &ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("ステータスが%sのアイテムの質問・回答は変更できません。下書きに戻してから編集してください", k.currentStatus()),
+		}
+	}
+
+	if p.SheetName != nil {
+		k.SheetName = *p.SheetName
+	}
+	if p.SourceRange != nil {
+		k.SourceRange = *p.SourceRange
+	}
+	if p.Question != nil {
+		k.UpdateQuestion(*p.Question)
+	}
+	if p.Answers != nil {
+		// 主回答はバリエーションから合成する
+		if err := k.SetAnswers(p.Answers); err != nil {
+			return err
+		}
+	} else if p.Answer != nil {
+		k.UpdateAnswer(*p.Answer)
+	}
+	if p.DepartmentID.Set {
+		k.DepartmentID = p.DepartmentID.Value
+	}
+	if p.QuestionGroup != nil {
+		k.QuestionGroup = *p.QuestionGroup
+	}
+	if p.ReviewDueAt.Set {
+		k.ReviewDueAt = p.ReviewDueAt.Value
+	}
+	if p.ValidUntil.Set {
+		k.ValidUntil = p.ValidUntil.Value
+	}
+	if p.ReviewIntervalDays.Set {
+		k.ReviewIntervalDays = p.ReviewIntervalDays.Value
+	}
+	if p.UpdatedBy != "" {
+		k.UpdatedBy = p.UpdatedBy
+	}
+	k.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantSet   bool
+		wantValue *int
+	}{
+		{name: "省略した場合は変更しない", body: `{}`, wantSet: false},
+		{name: "nullの場合は値を消す", body: `{"department_id":null}`, wantSet: true},
+		{name: "値を指定した場合は変更する", body: `{"department_id":3}`, wantSet: true, wantValue: intPtr(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req struct {
+				DepartmentID Optional[int] `json:"department_id"`
+			}
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if req.DepartmentID.Set != tt.wantSet {
+				t.Errorf("Set = %v, want %v", req.DepartmentID.Set, tt.wantSet)
+			}
+			if (req.DepartmentID.Value == nil) != (tt.wantValue == nil) ||
+				(tt.wantValue != nil && *req.DepartmentID.Value != *tt.wantValue) {
+				t.Errorf("Value = %v, want %v", req.DepartmentID.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestKnowledgeItem_ApplyPatch(t *testing.T) {
+	t.Run("指定した項目だけを変更する", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, DepartmentID: intPtr(2), Question: "質問", Answer: "回答", QuestionGroup: "アクセス管理", Status: StatusDraft}
+		answer := "新しい回答"
+		if err := item.ApplyPatch(KnowledgePatch{Answer: &answer, UpdatedBy: "山田"}); err != nil {
+			t.Fatalf("ApplyPatch() error = %v", err)
+		}
+		if item.Answer != "新しい回答" || item.Question != "質問" || item.QuestionGroup != "アクセス管理" || *item.DepartmentID != 2 || item.UpdatedBy != "山田" {
+			t.Errorf("ApplyPatch() item = %+v", item)
+		}
+	})
+
+	t.Run("ステータスは変更できない", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: StatusDraft}
+		status := StatusPublished
+		if err := item.ApplyPatch(KnowledgePatch{Status: &status}); err == nil {
+			t.Error("ApplyPatch() error = nil, want error")
+		}
+	})
+
+	t.Run("承認済みのアイテムの回答は変更できない", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Answer: "回答", Status: StatusApproved}
+		answer := "新しい回答"
+		err := item.ApplyPatch(KnowledgePatch{Answer: &answer})
+		if _, ok := err.(*ValidationError); !ok {
+			t.Fatalf("ApplyPatch() error = %v, want ValidationError", err)
+		}
+		if item.Answer != "回答" {
+			t.Errorf("Answer = %q, want unchanged", item.Answer)
+		}
+	})
+
+	t.Run("公開済みのアイテムでも質問・回答以外は変更できる", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Answer: "回答", Status: StatusPublished}
+		group := "暗号化"
+		answer := "回答"
+		if err := item.ApplyPatch(KnowledgePatch{QuestionGroup: &group, Answer: &answer}); err != nil {
+			t.Fatalf("ApplyPatch() error = %v", err)
+		}
+		if item.QuestionGroup != "暗号化" {
+			t.Errorf("QuestionGroup = %q, want 暗号化", item.QuestionGroup)
+		}
+	})
+
+	t.Run("変更する項目がない場合はエラー", func(t *testing.T) {
+		item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: StatusDraft}
+		if err := item.ApplyPatch(KnowledgePatch{UpdatedBy: "山田"}); err == nil {
+			t.Error("ApplyPatch() error = nil, want error")
+		}
+	})
+}
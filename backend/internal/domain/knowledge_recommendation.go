@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 推薦候補のステータス
+const (
+	RecommendationPending   = "pending"
+	RecommendationAccepted  = "accepted"
+	RecommendationDismissed = "dismissed"
+)
+
+// 推薦の既定値
+const (
+	DefaultRecommendationLimit     = 3
+	DefaultRecommendationThreshold = 0.3
+	// RecommendationBatchSize は類似ナレッジを1回のクエリでまとめて検索する未回答のアイテム数
+	RecommendationBatchSize = 100
+)
+
+// KnowledgeRecommendation は未回答のナレッジアイテムに対する回答の推薦候補
+type KnowledgeRecommendation struct {
+	ID              int        `json:"id"`
+	KnowledgeItemID int        `json:"knowledge_item_id"`
+	SourceItemID    int        `json:"source_item_id"`
+	Rank            int        `json:"rank"`
+	Score           float64    `json:"score"`
+	Status          string     `json:"status"`
+	AcceptedBy      string     `json:"accepted_by,omitempty"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	// 以下は推薦元アイテムの内容（取得時に結合する）
+	SourceProjectID int    `json:"source_project_id"`
+	SourceQuestion  string `json:"source_question"`
+	SourceAnswer    string `json:"source_answer"`
+	// SourceValidUntil は推薦元の回答の有効期限
+	SourceValidUntil *time.Time `json:"source_valid_until,omitempty"`
+	// RenderedAnswer は推薦元の回答のプレースホルダーを推薦先の案件の値で置き換えたプレビュー
+	RenderedAnswer string `json:"rendered_answer"`
+}
+
+// KnowledgeRecommendationRepository は推薦候補リポジトリのインターフェース
+type KnowledgeRecommendationRepository interface {
+	// ReplacePending はアイテムの未処理の候補を新しい候補で置き換える
+	ReplacePending(knowledgeItemID int, recommendations []*KnowledgeRecommendation) error
+	GetByID(id int) (*KnowledgeRecommendation, error)
+	GetByProjectID(projectID int) ([]*KnowledgeRecommendation, error)
+	// Accept は回答をコピーしたアイテムの更新と候補のステータス更新を同じトランザクションで行う
+	Accept(recommendation *KnowledgeRecommendation, item *KnowledgeItem, expectedVersion int) error
+}
+
+// NeedsAnswer は回答が未入力で推薦の対象となるかどうかを返す
+func (k *KnowledgeItem) NeedsAnswer() bool {
+	return len(k.Answers) == 0 && strings.TrimSpace(k.Answer) == ""
+}
+
+// AcceptRecommendation は推薦元アイテムの回答をコピーし、コピー元を記録する
+// コピーした回答はレビューを経る必要があるため、回答を編集できるステータス（draft/rejected）のアイテムに限る
+func (k *KnowledgeItem) AcceptRecommendation(source *KnowledgeItem, actor string) error {
+	if !k.IsContentEditable() {
+		return &ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("ステータスが%sのアイテムには推薦候補を採用できません。下書きに戻してから採用してください", k.currentStatus()),
+		}
+	}
+
+	sourceID := source.ID
+	k.Answer = source.Answer
+	k.AnswerSourceID = &sourceID
+	if k.DepartmentID == nil {
+		k.DepartmentID = source.DepartmentID
+	}
+	k.UpdatedBy = actor
+	k.Version++
+	k.UpdatedAt = time.Now()
+	return nil
+}
+
+// Accept は推薦候補を採用済みにする
+func (r *KnowledgeRecommendation) Accept(actor string) error {
+	if r.Status != RecommendationPending {
+		return &ValidationError{Field: "status", Message: "この推薦候補は既に処理済みです"}
+	}
+
+	now := time.Now()
+	r.Status = RecommendationAccepted
+	r.AcceptedBy = actor
+	r.AcceptedAt = &now
+	return nil
+}
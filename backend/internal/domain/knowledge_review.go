@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReviewSchedulerActor は定期レビューのスケジューラーによる遷移の実行者
+const ReviewSchedulerActor = "system"
+
+// reviewDateLayout は遷移理由に記録する期限の表示形式
+const reviewDateLayout = "2006-01-02"
+
+// 定期レビューの間隔（日数）
+const (
+	DefaultReviewIntervalDays = 365
+	MaxReviewIntervalDays     = 3650
+)
+
+// KnowledgeReviewRepository は定期レビューの対象アイテムを取得するリポジトリのインターフェース
+type KnowledgeReviewRepository interface {
+	// GetExpiring はapproved・publishedのうち、レビュー期限または有効期限がnow以前のアイテムを取得する
+	GetExpiring(now time.Time) ([]*KnowledgeItem, error)
+	// GetDueForReview はneeds_reviewのアイテムと、レビュー期限または有効期限がbefore以前のアイテム（archivedを除く）を期限の早い順に取得する
+	// departmentIDがnilでない場合はその部門のアイテムに限定する
+	GetDueForReview(before time.Time, departmentID *int) ([]*KnowledgeItem, error)
+}
+
+// DueForReviewGroup は部門ごとにまとめた再レビュー対象のアイテム
+type DueForReviewGroup struct {
+	// DepartmentID は部門未設定のアイテムのグループではnil
+	DepartmentID   *int             `json:"department_id"`
+	DepartmentName string           `json:"department_name"`
+	Count          int              `json:"count"`
+	Items          []*KnowledgeItem `json:"items"`
+}
+
+// IsExpired は回答の有効期限がnow時点で過ぎているかどうかを返す
+func (k *KnowledgeItem) IsExpired(now time.Time) bool {
+	return k.ValidUntil != nil && !k.ValidUntil.After(now)
+}
+
+// IsReviewDue はレビュー期限または有効期限がnow時点で過ぎているかどうかを返す
+func (k *KnowledgeItem) IsReviewDue(now time.Time) bool {
+	return (k.ReviewDueAt != nil && !k.ReviewDueAt.After(now)) || k.IsExpired(now)
+}
+
+// MarkNeedsReview は期限を過ぎたapproved・publishedのアイテムを再レビューが必要な状態にする
+func (k *KnowledgeItem) MarkNeedsReview(actor string, now time.Time) (*StatusTransition, error) {
+	if !k.IsReviewDue(now) {
+		return nil, &ValidationError{Field: "review_due_at", Message: "レビュー期限・有効期限を過ぎていません"}
+	}
+
+	var reason string
+	if k.IsExpired(now) {
+		reason = fmt.Sprintf("有効期限（%s）を過ぎました", k.ValidUntil.Format(reviewDateLayout))
+	} else {
+		reason = fmt.Sprintf("レビュー期限（%s）を過ぎました", k.ReviewDueAt.Format(reviewDateLayout))
+	}
+
+	return k.TransitionTo(StatusNeedsReview, actor, reason)
+}
+
+// completeReview は再レビューの承認時に、過ぎたレビュー期限を次回の期限に進める
+// レビュー期限がなくレビュー間隔が設定されている場合も次回の期限を設定する
+func (k *KnowledgeItem) completeReview(now time.Time) error {
+	if k.IsExpired(now) {
+		return &ValidationError{Field: "valid_until", Message: "有効期限を過ぎているため承認できません。valid_untilを更新してください"}
+	}
+	if (k.ReviewDueAt != nil && !k.ReviewDueAt.After(now)) || (k.ReviewDueAt == nil && k.ReviewIntervalDays != nil) {
+		k.ReviewDueAt = k.nextReviewDueAt(now)
+	}
+	return nil
+}
+
+// nextReviewDueAt は承認日からレビュー間隔後の次回のレビュー期限を返す（有効期限より後にはしない）
+func (k *KnowledgeItem) nextReviewDueAt(now time.Time) *time.Time {
+	days := DefaultReviewIntervalDays
+	if k.ReviewIntervalDays != nil {
+		days = *k.ReviewIntervalDays
+	}
+
+	next := now.AddDate(0, 0, days)
+	if k.ValidUntil != nil && next.After(*k.ValidUntil) {
+		next = *k.ValidUntil
+	}
+	return &next
+}
+
+// GroupDueForReview は再レビュー対象のアイテムを部門ごとにまとめる
+// グループはdepartmentsの並び順とし、部門未設定のアイテムは最後にまとめる。グループ内はitemsの並びを保つ
+func GroupDueForReview(items []*KnowledgeItem, departments []*Department) []*DueForReviewGroup {
+	known := make(map[int]bool, len(departments))
+	for _, d := range departments {
+		known[d.ID] = true
+	}
+
+	// 部門一覧にない部門のアイテムは部門未設定として扱う
+	byDepartment := map[int][]*KnowledgeItem{}
+	var unassigned []*KnowledgeItem
+	for _, item := range items {
+		if item.DepartmentID == nil || !known[*item.DepartmentID] {
+			unassigned = append(unassigned, item)
+			continue
+		}
+		byDepartment[*item.DepartmentID] = append(byDepartment[*item.DepartmentID], item)
+	}
+
+	groups := []*DueForReviewGroup{}
+	for _, d := range departments {
+		deptItems, ok := byDepartment[d.ID]
+		if !ok {
+			continue
+		}
+		id := d.ID
+		groups = append(groups, &DueForReviewGroup{
+			DepartmentID:   &id,
+			DepartmentName: d.Name,
+			Count:          len(deptItems),
+			Items:          deptItems,
+		})
+	}
+	if len(unassigned) > 0 {
+		groups = append(groups, &DueForReviewGroup{
+			DepartmentName: UnassignedDepartmentName,
+			Count:          len(unassigned),
+			Items:          unassigned,
+		})
+	}
+
+	return groups
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKnowledgeItem_MarkNeedsReview(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, 0, -1)
+	future := now.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name       string
+		item       *KnowledgeItem
+		wantErr    bool
+		wantReason string
+	}{
+		{
+			name:       "レビュー期限切れ",
+			item:       &KnowledgeItem{ID: 1, Status: StatusPublished, ReviewDueAt: &past, ValidUntil: &future},
+			wantReason: "レビュー期限（2026-09-30）を過ぎました",
+		},
+		{
+			name:       "有効期限切れはレビュー期限より優先して記録する",
+			item:       &KnowledgeItem{ID: 2, Status: StatusApproved, ReviewDueAt: &past, ValidUntil: &now},
+			wantReason: "有効期限（2026-10-01）を過ぎました",
+		},
+		{
+			name:    "期限前",
+			item:    &KnowledgeItem{ID: 3, Status: StatusPublished, ReviewDueAt: &future},
+			wantErr: true,
+		},
+		{
+			name:    "期限なし",
+			item:    &KnowledgeItem{ID: 4, Status: StatusPublished},
+			wantErr: true,
+		},
+		{
+			name:    "下書きは対象外",
+			item:    &KnowledgeItem{ID: 5, Status: StatusDraft, ReviewDueAt: &past},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := tt.item.Status
+			transition, err := tt.item.MarkNeedsReview(ReviewSchedulerActor, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("MarkNeedsReview() error = nil, want error")
+				}
+				if tt.item.Status != from {
+					t.Errorf("Status = %s, want %s", tt.item.Status, from)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MarkNeedsReview() error = %v", err)
+			}
+			if tt.item.Status != StatusNeedsReview {
+				t.Errorf("Status = %s, want needs_review", tt.item.Status)
+			}
+			if transition.FromStatus != from || transition.Actor != ReviewSchedulerActor || transition.Reason != tt.wantReason {
+				t.Errorf("transition = %+v", transition)
+			}
+		})
+	}
+}
+
+func TestKnowledgeItem_ReReview(t *testing.T) {
+	past := time.Now().AddDate(0, 0, -1)
+	future := time.Now().AddDate(2, 0, 0)
+	item := &KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: StatusNeedsReview, ReviewDueAt: &past, ValidUntil: &past}
+
+	if _, err := item.Submit("山田太郎"); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// 有効期限を過ぎたままでは承認できない
+	if _, err := item.Approve("佐藤花子"); err == nil {
+		t.Fatal("Approve() error = nil, want error")
+	}
+	if item.Status != StatusInReview {
+		t.Errorf("Status = %s, want in_review", item.Status)
+	}
+
+	// 有効期限を更新すれば承認でき、過ぎたレビュー期限は既定の間隔後に進む
+	item.ValidUntil = &future
+	before := time.Now()
+	if _, err := item.Approve("佐藤花子"); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if item.ReviewDueAt == nil || item.ReviewDueAt.Before(before.AddDate(0, 0, DefaultReviewIntervalDays)) ||
+		item.ReviewDueAt.After(time.Now().AddDate(0, 0, DefaultReviewIntervalDays)) {
+		t.Errorf("ReviewDueAt = %v, want %d days later", item.ReviewDueAt, DefaultReviewIntervalDays)
+	}
+	if item.ValidUntil != &future {
+		t.Errorf("ValidUntil = %v, want %v", item.ValidUntil, future)
+	}
+}
+
+func TestKnowledgeItem_CompleteReview_NextDue(t *testing.T) {
+	now := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, 0, -1)
+	interval := 90
+
+	tests := []struct {
+		name string
+		item *KnowledgeItem
+		want *time.Time
+	}{
+		{
+			name: "レビュー間隔後に次回の期限を設定する",
+			item: &KnowledgeItem{ReviewDueAt: &past, ReviewIntervalDays: &interval},
+			want: timePtr(now.AddDate(0, 0, 90)),
+		},
+		{
+			name: "有効期限より後にはしない",
+			item: &KnowledgeItem{ReviewDueAt: &past, ReviewIntervalDays: &interval, ValidUntil: timePtr(now.AddDate(0, 0, 30))},
+			want: timePtr(now.AddDate(0, 0, 30)),
+		},
+		{
+			name: "レビュー期限がなくても間隔があれば設定する",
+			item: &KnowledgeItem{ReviewIntervalDays: &interval},
+			want: timePtr(now.AddDate(0, 0, 90)),
+		},
+		{
+			name: "レビュー期限も間隔もなければ設定しない",
+			item: &KnowledgeItem{},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.item.completeReview(now); err != nil {
+				t.Fatalf("completeReview() error = %v", err)
+			}
+			got := tt.item.ReviewDueAt
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("ReviewDueAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKnowledgeItem_Validate_ReviewDueAfterValidUntil(t *testing.T) {
+	due := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	item := &KnowledgeItem{ProjectID: 1, Question: "質問", ReviewDueAt: &due, ValidUntil: &until}
+
+	if err := item.Validate(); err == nil {
+		t.Fatal("Validate() error = nil, want error")
+	}
+
+	item.ReviewDueAt = &until
+	if err := item.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestGroupDueForReview(t *testing.T) {
+	departments := []*Department{
+		{ID: 1, Name: "情報システム"},
+		{ID: 2, Name: "法務"},
+		{ID: 3, Name: "人事"},
+	}
+	items := []*KnowledgeItem{
+		{ID: 10, DepartmentID: intPtr(2)},
+		{ID: 11},
+		{ID: 12, DepartmentID: intPtr(1)},
+		{ID: 13, DepartmentID: intPtr(99)},
+		{ID: 14, DepartmentID: intPtr(2)},
+	}
+
+	groups := GroupDueForReview(items, departments)
+
+	if len(groups) != 3 {
+		t.Fatalf("len(groups) = %d, want 3", len(groups))
+	}
+
+	want := []struct {
+		name string
+		ids  []int
+	}{
+		{name: "情報システム", ids: []int{12}},
+		{name: "法務", ids: []int{10, 14}},
+		{name: "未設定", ids: []int{11, 13}},
+	}
+	for i, w := range want {
+		g := groups[i]
+		if g.DepartmentName != w.name || g.Count != len(w.ids) {
+			t.Errorf("groups[%d] = %s (%d), want %s (%d)", i, g.DepartmentName, g.Count, w.name, len(w.ids))
+			continue
+		}
+		for j, id := range w.ids {
+			if g.Items[j].ID != id {
+				t.Errorf("groups[%d].Items[%d].ID = %d, want %d", i, j, g.Items[j].ID, id)
+			}
+		}
+	}
+	if groups[2].DepartmentID != nil {
+		t.Errorf("未設定のDepartmentID = %v, want nil", groups[2].DepartmentID)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
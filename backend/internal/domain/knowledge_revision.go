@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KnowledgeRevision はナレッジアイテムの変更履歴（各時点のスナップショット）
+type KnowledgeRevision struct {
+	ID              int       `json:"id"`
+	KnowledgeItemID int       `json:"knowledge_item_id"`
+	Revision        int       `json:"revision"`
+	Version         int       `json:"version"`
+	Question        string    `json:"question"`
+	Answer          string    `json:"answer"`
+	DepartmentID    *int      `json:"department_id,omitempty"`
+	Status          string    `json:"status"`
+	EditedBy        string    `json:"edited_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// KnowledgeRevisionRepository はナレッジ履歴リポジトリのインターフェース
+// 履歴の記録はKnowledgeRepositoryの作成・更新と同じトランザクションで行われる
+type KnowledgeRevisionRepository interface {
+	GetByKnowledgeID(knowledgeID int) ([]*KnowledgeRevision, error)
+	GetByRevision(knowledgeID int, revision int) (*KnowledgeRevision, error)
+}
+
+// 差分の行操作
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine は差分の1行
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// FieldDiff は項目ごとの差分
+type FieldDiff struct {
+	Field   string     `json:"field"`
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	Changed bool       `json:"changed"`
+	Lines   []DiffLine `json:"lines,omitempty"`
+}
+
+// RevisionDiff は2つの履歴間の差分
+type RevisionDiff struct {
+	KnowledgeItemID int         `json:"knowledge_item_id"`
+	FromRevision    int         `json:"from_revision"`
+	ToRevision      int         `json:"to_revision"`
+	Fields          []FieldDiff `json:"fields"`
+}
+
+// DiffRevisions は2つの履歴の差分を計算する（質問・回答は行単位の差分も含む）
+func DiffRevisions(from, to *KnowledgeRevision) *RevisionDiff {
+	return &RevisionDiff{
+		KnowledgeItemID: to.KnowledgeItemID,
+		FromRevision:    from.Revision,
+		ToRevision:      to.Revision,
+		Fields: []FieldDiff{
+			textFieldDiff("question", from.Question, to.Question),
+			textFieldDiff("answer", from.Answer, to.Answer),
+			valueFieldDiff("department_id", formatOptionalInt(from.DepartmentID), formatOptionalInt(to.DepartmentID)),
+			valueFieldDiff("status", from.Status, to.Status),
+		},
+	}
+}
+
+// RestorePatch は履歴の質問・回答・部門に戻す部分更新を返す
+// 通常の編集と同じ規則（編集できるステータス、回答バリエーション、プレースホルダー）で検証するため、部分更新として適用する
+// ステータスはワークフローでのみ変更するため復元しない
+func (rev *KnowledgeRevision) RestorePatch(editedBy string) KnowledgePatch {
+	question := rev.Question
+	answer := rev.Answer
+	return KnowledgePatch{
+		Question:     &question,
+		Answer:       &answer,
+		DepartmentID: Optional[int]{Set: true, Value: rev.DepartmentID},
+		UpdatedBy:    editedBy,
+	}
+}
+
+func valueFieldDiff(field, from, to string) FieldDiff {
+	return FieldDiff{Field: field, From: from, To: to, Changed: from != to}
+}
+
+func textFieldDiff(field, from, to string) FieldDiff {
+	diff := valueFieldDiff(field, from, to)
+	if diff.Changed {
+		diff.Lines = diffLines(strings.Split(from, "\n"), strings.Split(to, "\n"))
+	}
+	return diff
+}
+
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// diffLines は最長共通部分列に基づいて行単位の差分を求める
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] はa[i:]とb[j:]の最長共通部分列の長さ
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := []DiffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: DiffInsert, Text: b[j]})
+	}
+
+	return lines
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	deptID := 2
+	from := &KnowledgeRevision{
+		KnowledgeItemID: 1,
+		Revision:        1,
+		Question:        "質問",
+		Answer:          "1行目\n2行目\n3行目",
+		Status:          "draft",
+	}
+	to := &KnowledgeRevision{
+		KnowledgeItemID: 1,
+		Revision:        3,
+		Question:        "質問",
+		Answer:          "1行目\n2行目（修正）\n3行目",
+		DepartmentID:    &deptID,
+		Status:          "draft",
+	}
+
+	diff := DiffRevisions(from, to)
+
+	if diff.FromRevision != 1 || diff.ToRevision != 3 {
+		t.Errorf("revisions = %d..%d, want 1..3", diff.FromRevision, diff.ToRevision)
+	}
+
+	changed := map[string]bool{}
+	for _, f := range diff.Fields {
+		changed[f.Field] = f.Changed
+	}
+	want := map[string]bool{"question": false, "answer": true, "department_id": true, "status": false}
+	for field, w := range want {
+		if changed[field] != w {
+			t.Errorf("%s changed = %v, want %v", field, changed[field], w)
+		}
+	}
+
+	answer := diff.Fields[1]
+	wantLines := []DiffLine{
+		{Op: DiffEqual, Text: "1行目"},
+		{Op: DiffDelete, Text: "2行目"},
+		{Op: DiffInsert, Text: "2行目（修正）"},
+		{Op: DiffEqual, Text: "3行目"},
+	}
+	if len(answer.Lines) != len(wantLines) {
+		t.Fatalf("Lines = %v, want %v", answer.Lines, wantLines)
+	}
+	for i := range wantLines {
+		if answer.Lines[i] != wantLines[i] {
+			t.Errorf("Lines[%d] = %v, want %v", i, answer.Lines[i], wantLines[i])
+		}
+	}
+
+	// 変更のない項目には行差分を含めない
+	if diff.Fields[0].Lines != nil {
+		t.Errorf("question Lines = %v, want nil", diff.Fields[0].Lines)
+	}
+}
+
+func TestKnowledgeRevision_RestorePatch(t *testing.T) {
+	rev := &KnowledgeRevision{
+		Revision: 1,
+		Version:  1,
+		Question: "古い質問",
+		Answer:   "古い回答",
+		Status:   "draft",
+	}
+
+	t.Run("下書きのアイテムは履歴の内容に戻る", func(t *testing.T) {
+		departmentID := 2
+		item := &KnowledgeItem{ProjectID: 1, Question: "新しい質問", Answer: "新しい回答", DepartmentID: &departmentID, Status: "draft", Version: 3}
+
+		if err := item.ApplyPatch(rev.RestorePatch("佐藤花子")); err != nil {
+			t.Fatalf("ApplyPatch() error = %v", err)
+		}
+		if item.Question != "古い質問" || item.Answer != "古い回答" {
+			t.Errorf("Question/Answer = %s/%s, want 古い質問/古い回答", item.Question, item.Answer)
+		}
+		if item.DepartmentID != nil {
+			t.Errorf("DepartmentID = %v, want nil", *item.DepartmentID)
+		}
+		// ステータスは復元しない
+		if item.Status != "draft" {
+			t.Errorf("Status = %s, want draft", item.Status)
+		}
+		if item.UpdatedBy != "佐藤花子" {
+			t.Errorf("UpdatedBy = %s, want 佐藤花子", item.UpdatedBy)
+		}
+	})
+
+	t.Run("公開済みのアイテムはレビューを経ずに戻せない", func(t *testing.T) {
+		item := &KnowledgeItem{ProjectID: 1, Question: "新しい質問", Answer: "新しい回答", Status: "published", Version: 3}
+
+		err := item.ApplyPatch(rev.RestorePatch("佐藤花子"))
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("ApplyPatch() error = %v, want *ValidationError", err)
+		}
+		if item.Question != "新しい質問" {
+			t.Errorf("Question = %s, want unchanged", item.Question)
+		}
+	})
+}
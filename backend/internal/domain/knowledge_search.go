@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ファセットの種類
+const (
+	FacetDepartment    = "department"
+	FacetStatus        = "status"
+	FacetQuestionGroup = "question_group"
+	FacetProject       = "project"
+	FacetYear          = "year"
+)
+
+// KnowledgeSearchFilter はナレッジ検索の絞り込み条件
+// 同じ項目の複数の値はOR、異なる項目はANDで結合する
+type KnowledgeSearchFilter struct {
+	ProjectIDs     []int
+	DepartmentIDs  []int
+	Statuses       []string
+	QuestionGroups []string
+	CreatedBy      []string
+	Assignees      []string
+	TaskStates     []string
+	// ControlIDs は直接または質問グループを通じて統制項目に紐づくアイテムに絞り込む
+	ControlIDs []int
+	// TagIDs はタグまたはその子孫のタグが紐づくアイテムに絞り込む
+	TagIDs []int
+	// CreatedFrom 以上、CreatedTo 未満の作成日時で絞り込む
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// UpdatedFrom 以上、UpdatedTo 未満の更新日時で絞り込む
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	// UnansweredOnly は回答が未入力のアイテムのみに絞り込む
+	UnansweredOnly bool
+}
+
+// Validate は絞り込み条件を検証する
+func (f *KnowledgeSearchFilter) Validate() error {
+	for _, status := range f.Statuses {
+		if !IsValidStatus(status) {
+			return &ValidationError{Field: "status", Message: fmt.Sprintf("無効なステータスです: %s", status)}
+		}
+	}
+	for _, state := range f.TaskStates {
+		if !IsValidTaskState(state) {
+			return &ValidationError{Field: "task_state", Message: fmt.Sprintf("無効なタスクの状態です: %s", state)}
+		}
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return &ValidationError{Field: "created_from", Message: "created_fromはcreated_toより前の日時を指定してください"}
+	}
+	if f.UpdatedFrom != nil && f.UpdatedTo != nil && !f.UpdatedFrom.Before(*f.UpdatedTo) {
+		return &ValidationError{Field: "updated_from", Message: "updated_fromはupdated_toより前の日時を指定してください"}
+	}
+	return nil
+}
+
+// FacetCount はファセットの値ごとの件数
+type FacetCount struct {
+	Value string `json:"value"`
+	// Label は部門名・顧客名など値の表示名
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// KnowledgeFacets はナレッジ検索のファセット集計
+// 各ファセットの件数は、そのファセット自身の絞り込みを除いた条件で集計する
+type KnowledgeFacets struct {
+	Department    []FacetCount `json:"department"`
+	Status        []FacetCount `json:"status"`
+	QuestionGroup []FacetCount `json:"question_group"`
+	Project       []FacetCount `json:"project"`
+	Year          []FacetCount `json:"year"`
+}
+
+// KnowledgeSearchResult はファセット集計付きのナレッジ検索結果
+type KnowledgeSearchResult struct {
+	*Page[*KnowledgeItem]
+	Facets *KnowledgeFacets `json:"facets"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// 類似検索の既定値
+const (
+	DefaultSimilarityThreshold = 0.3
+	DefaultSimilarLimit        = 20
+	MaxSimilarLimit            = 100
+)
+
+// ScoredKnowledgeItem は類似度スコア付きのナレッジアイテム
+type ScoredKnowledgeItem struct {
+	*KnowledgeItem
+	// Score はsimilarity()とword_similarity()の大きい方（0〜1）
+	Score float64 `json:"score"`
+}
+
+// SimilarSearchOptions は類似質問検索の条件
+type SimilarSearchOptions struct {
+	// Threshold はこの値以上のスコアのアイテムのみを返す
+	Threshold float64
+	// ExcludeProjectID が0以外の場合、その案件のアイテムを除外する
+	ExcludeProjectID int
+	// Status が空でない場合、そのステータスのアイテムに限定する
+	Status string
+	// PreferValidAt がnilでない場合、その時点で有効期限を過ぎたアイテムはスコアに関わらず有効なアイテムの後に並べる
+	PreferValidAt *time.Time
+	Limit         int
+}
+
+// Normalize は未指定の値に既定値を設定し、範囲外の値を検証する
+func (o *SimilarSearchOptions) Normalize() error {
+	if o.Threshold == 0 {
+		o.Threshold = DefaultSimilarityThreshold
+	}
+	if o.Threshold < 0 || o.Threshold > 1 {
+		return &ValidationError{Field: "threshold", Message: "thresholdは0より大きく1以下である必要があります"}
+	}
+
+	if o.Limit == 0 {
+		o.Limit = DefaultSimilarLimit
+	}
+	if o.Limit < 0 || o.Limit > MaxSimilarLimit {
+		return &ValidationError{Field: "limit", Message: "limitは1以上100以下である必要があります"}
+	}
+
+	if o.Status != "" && !IsValidStatus(o.Status) {
+		return &ValidationError{Field: "status", Message: "無効なステータスです: " + o.Status}
+	}
+
+	return nil
+}
@@ -119,7 +119,7 @@ func TestKnowledgeItem_Validate(t *testing.T) {
 				Status:    "invalid",
 			},
 			wantErr: true,
-			errMsg:  "ステータスはdraft, published, archivedのいずれかである必要があります",
+			errMsg:  "ステータスはdraft, in_review, approved, rejected, published, needs_review, archivedのいずれかである必要があります",
 		},
 	}
 
@@ -178,14 +178,37 @@ func TestKnowledgeItem_Publish(t *testing.T) {
 	item := &KnowledgeItem{
 		ProjectID: 1,
 		Question:  "質問",
-		Status:    "draft",
+		Status:    "approved",
+		Version:   1,
 	}
 
-	item.Publish()
+	transition, err := item.Publish("佐藤花子")
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
 
 	if item.Status != "published" {
 		t.Errorf("Status = %s, want published", item.Status)
 	}
+	if transition.FromStatus != "approved" || transition.ToStatus != "published" {
+		t.Errorf("transition = %s -> %s, want approved -> published", transition.FromStatus, transition.ToStatus)
+	}
+}
+
+func TestKnowledgeItem_Publish_NotApproved(t *testing.T) {
+	item := &KnowledgeItem{
+		ProjectID: 1,
+		Question:  "質問",
+		Status:    "draft",
+	}
+
+	// 承認前の公開はできない
+	if _, err := item.Publish("佐藤花子"); err == nil {
+		t.Error("Publish() error = nil, want error")
+	}
+	if item.Status != "draft" {
+		t.Errorf("Status = %s, want draft", item.Status)
+	}
 }
 
 func TestKnowledgeItem_Archive(t *testing.T) {
@@ -195,7 +218,9 @@ func TestKnowledgeItem_Archive(t *testing.T) {
 		Status:    "published",
 	}
 
-	item.Archive()
+	if _, err := item.Archive("佐藤花子"); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
 
 	if item.Status != "archived" {
 		t.Errorf("Status = %s, want archived", item.Status)
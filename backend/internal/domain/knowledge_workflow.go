@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ナレッジアイテムのステータス
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+	// StatusNeedsReview はレビュー期限・有効期限を過ぎて再レビューが必要な状態
+	StatusNeedsReview = "needs_review"
+)
+
+// knowledgeStatuses は有効なステータス（表示順）
+var knowledgeStatuses = []string{
+	StatusDraft,
+	StatusInReview,
+	StatusApproved,
+	StatusRejected,
+	StatusPublished,
+	StatusNeedsReview,
+	StatusArchived,
+}
+
+// statusTransitions は遷移元ステータスごとの遷移可能なステータス
+//
+//	draft → in_review → approved → published → archived
+//	            └→ rejected → in_review / draft
+//
+// 差し戻し（in_review/approved/published → draft）とアーカイブからの復帰（archived → draft）も許可する
+// approved/publishedは期限を過ぎるとneeds_reviewになり、再レビュー（in_review）を経て承認し直す
+var statusTransitions = map[string][]string{
+	StatusDraft:       {StatusInReview, StatusArchived},
+	StatusInReview:    {StatusApproved, StatusRejected, StatusDraft},
+	StatusApproved:    {StatusPublished, StatusNeedsReview, StatusDraft},
+	StatusRejected:    {StatusInReview, StatusDraft},
+	StatusPublished:   {StatusArchived, StatusNeedsReview, StatusDraft},
+	StatusNeedsReview: {StatusInReview, StatusArchived, StatusDraft},
+	StatusArchived:    {StatusDraft},
+}
+
+// StatusTransition はステータス遷移の記録
+type StatusTransition struct {
+	ID              int       `json:"id"`
+	KnowledgeItemID int       `json:"knowledge_item_id"`
+	FromStatus      string    `json:"from_status"`
+	ToStatus        string    `json:"to_status"`
+	Actor           string    `json:"actor"`
+	Reason          string    `json:"reason,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// KnowledgeWorkflowRepository はステータス遷移リポジトリのインターフェース
+type KnowledgeWorkflowRepository interface {
+	// Transition はナレッジアイテムの更新と遷移の記録を同じトランザクションで行う
+	Transition(item *KnowledgeItem, expectedVersion int, transition *StatusTransition) error
+	GetByKnowledgeID(knowledgeID int) ([]*StatusTransition, error)
+}
+
+// IsValidStatus は有効なステータスかどうかを返す
+func IsValidStatus(status string) bool {
+	for _, s := range knowledgeStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo は現在のステータスから指定されたステータスへ遷移できるかどうかを返す
+func (k *KnowledgeItem) CanTransitionTo(status string) bool {
+	for _, s := range statusTransitions[k.currentStatus()] {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo はステータスを遷移させ、遷移の記録を返す
+func (k *KnowledgeItem) TransitionTo(status string, actor string, reason string) (*StatusTransition, error) {
+	if !IsValidStatus(status) {
+		return nil, &ValidationError{Field: "status", Message: fmt.Sprintf("無効なステータスです: %s", status)}
+	}
+
+	from := k.currentStatus()
+	if !k.CanTransitionTo(status) {
+		return nil, &ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("ステータスを%sから%sに変更することはできません", from, status),
+		}
+	}
+
+	reason = strings.TrimSpace(reason)
+	if status == StatusRejected {
+		if reason == "" {
+			return nil, &ValidationError{Field: "reason", Message: "差し戻し理由は必須です"}
+		}
+		k.RejectionReason = reason
+	} else {
+		k.RejectionReason = ""
+	}
+
+	k.Status = status
+	k.UpdatedBy = actor
+	k.Version++
+	k.UpdatedAt = time.Now()
+
+	return &StatusTransition{
+		KnowledgeItemID: k.ID,
+		FromStatus:      from,
+		ToStatus:        status,
+		Actor:           actor,
+		Reason:          reason,
+		CreatedAt:       k.UpdatedAt,
+	}, nil
+}
+
+// Submit はレビュー依頼を行う（draft/rejected → in_review）
+func (k *KnowledgeItem) Submit(actor string) (*StatusTransition, error) {
+	return k.TransitionTo(StatusInReview, actor, "")
+}
+
+// Approve はレビューを承認する（in_review → approved）
+// 有効期限を過ぎたアイテムは承認できず、承認によりレビュー期限はレビュー間隔後の次回の期限に進む
+func (k *KnowledgeItem) Approve(actor string) (*StatusTransition, error) {
+	if k.CanTransitionTo(StatusApproved) {
+		if err := k.completeReview(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return k.TransitionTo(StatusApproved, actor, "")
+}
+
+// Reject は理由を付けてレビューを差し戻す（in_review → rejected）
+func (k *KnowledgeItem) Reject(actor string, reason string) (*StatusTransition, error) {
+	return k.TransitionTo(StatusRejected, actor, reason)
+}
+
+// currentStatus はステータス未設定の場合にdraftとみなす
+func (k *KnowledgeItem) currentStatus() string {
+	if k.Status == "" {
+		return StatusDraft
+	}
+	return k.Status
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestKnowledgeItem_ReviewWorkflow(t *testing.T) {
+	item := NewKnowledgeItem(1, nil, "シート1", "A1", "質問", "回答", nil, "山田太郎")
+	item.ID = 10
+
+	steps := []struct {
+		name string
+		do   func() (*StatusTransition, error)
+		want string
+	}{
+		{name: "レビュー依頼", do: func() (*StatusTransition, error) { return item.Submit("山田太郎") }, want: StatusInReview},
+		{name: "差し戻し", do: func() (*StatusTransition, error) { return item.Reject("佐藤花子", "根拠資料を追記してください") }, want: StatusRejected},
+		{name: "再レビュー依頼", do: func() (*StatusTransition, error) { return item.Submit("山田太郎") }, want: StatusInReview},
+		{name: "承認", do: func() (*StatusTransition, error) { return item.Approve("佐藤花子") }, want: StatusApproved},
+		{name: "公開", do: func() (*StatusTransition, error) { return item.Publish("佐藤花子") }, want: StatusPublished},
+		{name: "アーカイブ", do: func() (*StatusTransition, error) { return item.Archive("佐藤花子") }, want: StatusArchived},
+	}
+
+	for _, step := range steps {
+		from := item.Status
+		transition, err := step.do()
+		if err != nil {
+			t.Fatalf("%s: error = %v", step.name, err)
+		}
+		if item.Status != step.want {
+			t.Errorf("%s: Status = %s, want %s", step.name, item.Status, step.want)
+		}
+		if transition.KnowledgeItemID != 10 || transition.FromStatus != from || transition.ToStatus != step.want {
+			t.Errorf("%s: transition = %+v", step.name, transition)
+		}
+	}
+
+	// 遷移ごとに版が進む
+	if item.Version != 7 {
+		t.Errorf("Version = %d, want 7", item.Version)
+	}
+}
+
+func TestKnowledgeItem_Reject(t *testing.T) {
+	item := &KnowledgeItem{ProjectID: 1, Question: "質問", Status: StatusInReview}
+
+	// 理由なしの差し戻しはできない
+	if _, err := item.Reject("佐藤花子", "  "); err == nil {
+		t.Fatal("Reject() error = nil, want error")
+	}
+	if item.Status != StatusInReview {
+		t.Errorf("Status = %s, want in_review", item.Status)
+	}
+
+	transition, err := item.Reject("佐藤花子", "回答が古い")
+	if err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if item.RejectionReason != "回答が古い" || transition.Reason != "回答が古い" {
+		t.Errorf("RejectionReason = %s, want 回答が古い", item.RejectionReason)
+	}
+	if transition.Actor != "佐藤花子" {
+		t.Errorf("Actor = %s, want 佐藤花子", transition.Actor)
+	}
+
+	// 再依頼で差し戻し理由はクリアされる
+	if _, err := item.Submit("山田太郎"); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if item.RejectionReason != "" {
+		t.Errorf("RejectionReason = %s, want empty", item.RejectionReason)
+	}
+}
+
+func TestKnowledgeItem_TransitionTo_NotAllowed(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+	}{
+		{from: StatusDraft, to: StatusApproved},
+		{from: StatusDraft, to: StatusPublished},
+		{from: StatusInReview, to: StatusPublished},
+		{from: StatusRejected, to: StatusApproved},
+		{from: StatusArchived, to: StatusPublished},
+		{from: StatusDraft, to: "reviewed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			item := &KnowledgeItem{ProjectID: 1, Question: "質問", Status: tt.from, Version: 1}
+			if _, err := item.TransitionTo(tt.to, "佐藤花子", "理由"); err == nil {
+				t.Errorf("TransitionTo(%s) error = nil, want error", tt.to)
+			}
+			if item.Status != tt.from || item.Version != 1 {
+				t.Errorf("item changed: Status = %s, Version = %d", item.Status, item.Version)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ページングの既定値
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// 並び順
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// 一覧ごとに並び替えを許可する項目（先頭が既定）
+var (
+	ProjectSortFields   = []string{"created_at", "updated_at", "customer_name", "owner", "status", "id"}
+	FileSortFields      = []string{"uploaded_at", "file_name", "file_size", "id"}
+	KnowledgeSortFields = []string{"created_at", "updated_at", "id", "question", "sheet_name", "source_range", "status", "department_id"}
+)
+
+// PageRequest は一覧取得のページング・並び替え条件
+// リポジトリではLimitが0の場合に全件を返す
+type PageRequest struct {
+	Limit  int
+	Offset int
+	Sort   string
+	Order  string
+}
+
+// Page はページングされた一覧の結果
+type Page[T any] struct {
+	Items  []T    `json:"items"`
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Sort   string `json:"sort"`
+	Order  string `json:"order"`
+}
+
+// NewPage はページングの条件と結果から一覧の結果を生成する
+func NewPage[T any](items []T, total int, req PageRequest) *Page[T] {
+	return &Page[T]{
+		Items:  items,
+		Total:  total,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+		Sort:   req.Sort,
+		Order:  req.Order,
+	}
+}
+
+// Normalize は未指定の値に既定値を設定し、並び替え項目が許可されたものか検証する
+func (p *PageRequest) Normalize(sortFields []string) error {
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return &ValidationError{Field: "limit", Message: fmt.Sprintf("limitは1以上%d以下である必要があります", MaxPageLimit)}
+	}
+	if p.Offset < 0 {
+		return &ValidationError{Field: "offset", Message: "offsetは0以上である必要があります"}
+	}
+
+	if p.Sort == "" {
+		p.Sort = sortFields[0]
+	}
+	if !IsAllowedSortField(p.Sort, sortFields) {
+		return &ValidationError{Field: "sort", Message: fmt.Sprintf("sortは%sのいずれかである必要があります", strings.Join(sortFields, ", "))}
+	}
+
+	p.Order = strings.ToLower(p.Order)
+	if p.Order == "" {
+		p.Order = SortDesc
+	}
+	if p.Order != SortAsc && p.Order != SortDesc {
+		return &ValidationError{Field: "order", Message: "orderはasc, descのいずれかである必要があります"}
+	}
+
+	return nil
+}
+
+// IsAllowedSortField は並び替え項目が許可リストに含まれるかどうかを返す
+func IsAllowedSortField(sort string, sortFields []string) bool {
+	for _, f := range sortFields {
+		if f == sort {
+			return true
+		}
+	}
+	return false
+}
@@ -4,21 +4,27 @@ import "time"
 
 // Project は案件を表すドメインエンティティ
 type Project struct {
-	ID          int       `json:"id"`
-	CustomerName string    `json:"customer_name"`
-	Description string    `json:"description"`
-	Owner       string    `json:"owner"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int    `json:"id"`
+	CustomerName string `json:"customer_name"`
+	Description  string `json:"description"`
+	Owner        string `json:"owner"`
+	Status       string `json:"status"`
+	// Variables は回答のプレースホルダー（{{name}}）に埋め込む案件ごとの値
+	Variables map[string]string `json:"variables"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
 }
 
 // ProjectRepository は案件データアクセスのインターフェース
 type ProjectRepository interface {
 	Create(project *Project) error
 	GetByID(id int) (*Project, error)
-	GetAll() ([]*Project, error)
+	// GetAll は案件の一覧と総件数を取得する
+	GetAll(page PageRequest) ([]*Project, int, error)
 	Update(project *Project) error
+	// UpdateVariables は案件変数を置き換える
+	UpdateVariables(id int, variables map[string]string) error
+	// Delete は案件を案件のファイル・ナレッジアイテムとともにゴミ箱に移す
 	Delete(id int) error
 }
 
@@ -29,6 +35,7 @@ func NewProject(customerName, description, owner string) *Project {
 		Description:  description,
 		Owner:        owner,
 		Status:       "active",
+		Variables:    map[string]string{},
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -39,7 +46,39 @@ func (p *Project) Validate() error {
 	if p.CustomerName == "" {
 		return &ValidationError{Field: "customer_name", Message: "顧客名は必須です"}
 	}
-	return nil
+	return p.ValidateVariables()
+}
+
+// ProjectPatch は案件の部分更新で変更する項目（nilの項目は変更しない）
+// 案件変数は専用のエンドポイントで置き換える
+type ProjectPatch struct {
+	CustomerName *string
+	Description  *string
+	Owner        *string
+	Status       *string
+}
+
+// ApplyPatch は案件に指定された項目だけを反映して検証する
+func (p *Project) ApplyPatch(patch ProjectPatch) error {
+	if patch.CustomerName == nil && patch.Description == nil && patch.Owner == nil && patch.Status == nil {
+		return &ValidationError{Field: "patch", Message: "更新する項目を指定してください"}
+	}
+
+	if patch.CustomerName != nil {
+		p.CustomerName = *patch.CustomerName
+	}
+	if patch.Description != nil {
+		p.Description = *patch.Description
+	}
+	if patch.Owner != nil {
+		p.Owner = *patch.Owner
+	}
+	if patch.Status != nil {
+		p.Status = *patch.Status
+	}
+	p.UpdatedAt = time.Now()
+
+	return p.Validate()
 }
 
 // ValidationError はバリデーションエラーを表す
@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TagPathSeparator はタグの階層パスの区切り
+const TagPathSeparator = " > "
+
+// Tag はナレッジを分類する階層構造のタグ
+type Tag struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ParentID    *int   `json:"parent_id,omitempty"`
+	Description string `json:"description"`
+	// Path はルートからの階層パス（例: アクセス制御 > 認証）、Depth はルートを0とする深さ（NewTagTreeで設定する）
+	Path  string `json:"path"`
+	Depth int    `json:"depth"`
+	// ItemCount は直接紐づくナレッジアイテム数（一覧取得時に集計する）
+	ItemCount int       `json:"item_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TagRepository はタグリポジトリのインターフェース
+type TagRepository interface {
+	Create(tag *Tag) error
+	GetByID(id int) (*Tag, error)
+	GetAll() ([]*Tag, error)
+	Update(tag *Tag) error
+	Delete(id int) error
+	// CountTags は指定されたIDのうち存在するタグの数を返す
+	CountTags(ids []int) (int, error)
+	GetByKnowledgeID(knowledgeID int) ([]*Tag, error)
+	ReplaceKnowledgeTags(knowledgeID int, tagIDs []int, actor string) error
+	// BulkTag は複数のアイテムにタグを一括で追加・削除し、追加・削除した紐づけの数を返す
+	BulkTag(knowledgeIDs []int, addTagIDs []int, removeTagIDs []int, actor string) (added int, removed int, err error)
+	// GetQuestionGroups はナレッジアイテムに入力されている質問グループの値を重複なく取得する
+	GetQuestionGroups() ([]string, error)
+	// MigrateQuestionGroups は質問グループの値ごとに移行先のタグを（なければ作成して）解決し、該当するアイテムに紐づける
+	// 途中で失敗した場合に一部だけ移行された状態が残らないよう、1トランザクションで実行する
+	MigrateQuestionGroups(paths []*QuestionGroupTagPath, actor string) (*QuestionGroupMigrationResult, error)
+}
+
+// QuestionGroupTagPath は質問グループの値と移行先のタグの階層
+type QuestionGroupTagPath struct {
+	QuestionGroup string
+	// Names はルートから順のタグ名
+	Names []string
+}
+
+// QuestionGroupMigration は質問グループの値ごとの移行先
+type QuestionGroupMigration struct {
+	QuestionGroup string `json:"question_group"`
+	TagID         int    `json:"tag_id"`
+	Path          string `json:"path"`
+}
+
+// QuestionGroupMigrationResult は質問グループからタグへの移行結果
+type QuestionGroupMigrationResult struct {
+	TagsCreated int                      `json:"tags_created"`
+	ItemsTagged int                      `json:"items_tagged"`
+	Groups      []QuestionGroupMigration `json:"groups"`
+}
+
+// NewTag は新しいタグを生成する
+func NewTag(name string, parentID *int, description string) *Tag {
+	now := time.Now()
+	return &Tag{
+		Name:        strings.TrimSpace(name),
+		ParentID:    parentID,
+		Description: description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate はタグの内容を検証する
+func (t *Tag) Validate() error {
+	name := strings.TrimSpace(t.Name)
+	if name == "" {
+		return &ValidationError{Field: "name", Message: "タグ名は必須です"}
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return &ValidationError{Field: "name", Message: "タグ名は100文字以内で指定してください"}
+	}
+	if strings.Contains(name, strings.TrimSpace(TagPathSeparator)) {
+		return &ValidationError{Field: "name", Message: "タグ名に「>」は使用できません"}
+	}
+	return nil
+}
+
+// NewQuestionGroupTagPath は質問グループの値から移行先のタグの階層を組み立てる
+// 表記ゆれ（全角・半角、空白）は正規化し、「>」で区切られた値は階層として扱う
+// タグ名にできない値はエラーを返し、タグ名が残らない値はnilを返す
+func NewQuestionGroupTagPath(questionGroup string) (*QuestionGroupTagPath, error) {
+	names := []string{}
+	for _, name := range strings.Split(NormalizeForSearch(questionGroup), ">") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := NewTag(name, nil, "").Validate(); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return &QuestionGroupTagPath{QuestionGroup: questionGroup, Names: names}, nil
+}
+
+// TagTree はタグの親子関係を辿るための木構造
+type TagTree struct {
+	byID     map[int]*Tag
+	children map[int][]*Tag
+	roots    []*Tag
+}
+
+// NewTagTree はタグの一覧から木構造を組み立て、各タグのPathとDepthを設定する
+// 親が見つからないタグはルートとして扱う
+func NewTagTree(tags []*Tag) *TagTree {
+	tree := &TagTree{
+		byID:     make(map[int]*Tag, len(tags)),
+		children: make(map[int][]*Tag),
+	}
+	for _, t := range tags {
+		tree.byID[t.ID] = t
+	}
+	for _, t := range tags {
+		if t.ParentID != nil && tree.byID[*t.ParentID] != nil {
+			tree.children[*t.ParentID] = append(tree.children[*t.ParentID], t)
+		} else {
+			tree.roots = append(tree.roots, t)
+		}
+	}
+
+	sortTagsByName(tree.roots)
+	for _, children := range tree.children {
+		sortTagsByName(children)
+	}
+
+	var walk func(tags []*Tag, path string, depth int)
+	walk = func(tags []*Tag, path string, depth int) {
+		for _, t := range tags {
+			t.Path = t.Name
+			if path != "" {
+				t.Path = path + TagPathSeparator + t.Name
+			}
+			t.Depth = depth
+			walk(tree.children[t.ID], t.Path, depth+1)
+		}
+	}
+	walk(tree.roots, "", 0)
+
+	return tree
+}
+
+// Get はIDでタグを取得する
+func (tr *TagTree) Get(id int) *Tag {
+	return tr.byID[id]
+}
+
+// Flatten はタグを親の直後に子が並ぶ深さ優先の順で返す
+func (tr *TagTree) Flatten() []*Tag {
+	result := make([]*Tag, 0, len(tr.byID))
+	var walk func(tags []*Tag)
+	walk = func(tags []*Tag) {
+		for _, t := range tags {
+			result = append(result, t)
+			walk(tr.children[t.ID])
+		}
+	}
+	walk(tr.roots)
+	return result
+}
+
+// HasChildren はタグに子タグがあるかを返す
+func (tr *TagTree) HasChildren(id int) bool {
+	return len(tr.children[id]) > 0
+}
+
+// Descendants はタグ自身とそのすべての子孫のIDを返す
+func (tr *TagTree) Descendants(id int) []int {
+	ids := []int{}
+	var walk func(id int)
+	walk = func(id int) {
+		ids = append(ids, id)
+		for _, c := range tr.children[id] {
+			walk(c.ID)
+		}
+	}
+	if tr.byID[id] != nil {
+		walk(id)
+	}
+	return ids
+}
+
+// FindChild は親の下にある同名のタグを返す（parentIDがnilの場合はルートから探す）
+func (tr *TagTree) FindChild(parentID *int, name string) *Tag {
+	siblings := tr.roots
+	if parentID != nil {
+		siblings = tr.children[*parentID]
+	}
+	name = strings.TrimSpace(name)
+	for _, t := range siblings {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// ValidatePlacement はタグを親の下に配置できるかを検証する
+// 親は存在するタグで、自分自身や子孫であってはならず、同じ親の下に同名のタグがあってはならない
+func (tr *TagTree) ValidatePlacement(tag *Tag) error {
+	if tag.ParentID != nil {
+		if tr.byID[*tag.ParentID] == nil {
+			return &ValidationError{Field: "parent_id", Message: "親タグが存在しません"}
+		}
+		if tag.ID != 0 {
+			for _, id := range tr.Descendants(tag.ID) {
+				if id == *tag.ParentID {
+					return &ValidationError{Field: "parent_id", Message: "自分自身または子孫のタグを親にはできません"}
+				}
+			}
+		}
+	}
+
+	if sibling := tr.FindChild(tag.ParentID, tag.Name); sibling != nil && sibling.ID != tag.ID {
+		return &ValidationError{Field: "name", Message: "同じ親の下に同名のタグがあります"}
+	}
+	return nil
+}
+
+// sortTagsByName はタグを名前順に並べる
+func sortTagsByName(tags []*Tag) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+}
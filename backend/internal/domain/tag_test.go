@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func sampleTags() []*Tag {
+	return []*Tag{
+		{ID: 3, Name: "認証", ParentID: intPtr(1)},
+		{ID: 1, Name: "アクセス制御"},
+		{ID: 4, Name: "多要素認証", ParentID: intPtr(3)},
+		{ID: 2, Name: "暗号化"},
+		{ID: 5, Name: "権限管理", ParentID: intPtr(1)},
+	}
+}
+
+func TestNewTagTree_PathsAndOrder(t *testing.T) {
+	tree := NewTagTree(sampleTags())
+
+	if got := tree.Get(4).Path; got != "アクセス制御 > 認証 > 多要素認証" {
+		t.Errorf("Path = %q, want アクセス制御 > 認証 > 多要素認証", got)
+	}
+	if got := tree.Get(4).Depth; got != 2 {
+		t.Errorf("Depth = %d, want 2", got)
+	}
+
+	ids := []int{}
+	for _, tag := range tree.Flatten() {
+		ids = append(ids, tag.ID)
+	}
+	want := []int{1, 5, 3, 4, 2}
+	if len(ids) != len(want) {
+		t.Fatalf("Flatten ids = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("Flatten ids = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestTagTree_Descendants(t *testing.T) {
+	tree := NewTagTree(sampleTags())
+
+	ids := tree.Descendants(1)
+	if len(ids) != 4 {
+		t.Errorf("Descendants(1) = %v, want 4 ids", ids)
+	}
+	if len(tree.Descendants(99)) != 0 {
+		t.Error("Descendants of unknown tag should be empty")
+	}
+	if !tree.HasChildren(3) || tree.HasChildren(4) {
+		t.Error("HasChildren returned unexpected result")
+	}
+}
+
+func TestTagTree_ValidatePlacement(t *testing.T) {
+	tree := NewTagTree(sampleTags())
+
+	tests := []struct {
+		name    string
+		tag     *Tag
+		wantErr bool
+	}{
+		{"new root", &Tag{Name: "ログ管理"}, false},
+		{"new child", &Tag{Name: "パスワード", ParentID: intPtr(3)}, false},
+		{"same name under other parent", &Tag{Name: "認証", ParentID: intPtr(2)}, false},
+		{"rename keeps own name", &Tag{ID: 3, Name: "認証", ParentID: intPtr(1)}, false},
+		{"duplicate sibling", &Tag{Name: "認証", ParentID: intPtr(1)}, true},
+		{"unknown parent", &Tag{Name: "x", ParentID: intPtr(99)}, true},
+		{"self as parent", &Tag{ID: 3, Name: "認証", ParentID: intPtr(3)}, true},
+		{"descendant as parent", &Tag{ID: 1, Name: "アクセス制御", ParentID: intPtr(4)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tree.ValidatePlacement(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePlacement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if err != nil && !errors.As(err, &validationErr) {
+				t.Errorf("error = %T, want *ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestTag_Validate(t *testing.T) {
+	if err := NewTag("  ", nil, "").Validate(); err == nil {
+		t.Error("empty name should be invalid")
+	}
+	if err := NewTag("アクセス制御 > 認証", nil, "").Validate(); err == nil {
+		t.Error("name containing separator should be invalid")
+	}
+	if err := NewTag(" 認証 ", nil, "").Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestNewQuestionGroupTagPath(t *testing.T) {
+	path, err := NewQuestionGroupTagPath("ｱｸｾｽ制御 ＞ 認証 ")
+	if err != nil {
+		t.Fatalf("NewQuestionGroupTagPath() error = %v", err)
+	}
+	if want := []string{"アクセス制御", "認証"}; !reflect.DeepEqual(path.Names, want) {
+		t.Errorf("Names = %v, want %v", path.Names, want)
+	}
+	if path.QuestionGroup != "ｱｸｾｽ制御 ＞ 認証 " {
+		t.Errorf("QuestionGroup = %q, want the original value", path.QuestionGroup)
+	}
+
+	if path, err := NewQuestionGroupTagPath(" > "); err != nil || path != nil {
+		t.Errorf("NewQuestionGroupTagPath() = %v, %v, want nil, nil", path, err)
+	}
+	if _, err := NewQuestionGroupTagPath(strings.Repeat("あ", 101)); err == nil {
+		t.Error("too long name should be invalid")
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// horizontalSpacePattern は行内の連続する空白（タブ・全角空白を含む）
+	horizontalSpacePattern = regexp.MustCompile(`[\t\v\f\p{Zs}]+`)
+	// circledNumberPattern は行頭の丸数字。NFKCで通常の数字になり本文と区別できなくなるため先に除去する
+	circledNumberPattern = regexp.MustCompile(`(?m)^[\t\p{Zs}]*[\x{2460}-\x{2473}\x{2776}-\x{277F}]`)
+	// bulletPattern は行頭の箇条書き記号
+	bulletPattern = regexp.MustCompile(`^[・･•●○◎■□◆◇▪▫▶▷►‣◦*\-]+\s*`)
+	// numberingPattern は行頭の番号付け（(1)、1)、1.、1、など）
+	// 「1.5GB」のような小数を誤って除去しないよう、区切りの直後が数字の場合は対象外とする
+	numberingPattern = regexp.MustCompile(`^(?:\(\d+\)|\d+[.)、])(\s*)(\D|$)`)
+)
+
+// NormalizeText はチェックシートから取り込んだテキストを正規化する
+//   - 行頭の丸数字を除去する
+//   - NFKC正規化（全角英数字・半角カナなどの表記ゆれを統一）
+//   - 改行コードをLFに統一し、セル内改行は維持する
+//   - 行内の連続空白を1つに畳み、行頭・行末の空白を除去する
+//   - 行頭の箇条書き記号・番号付けを除去する
+//   - 連続する空行を1つに畳む
+func NormalizeText(text string) string {
+	text = circledNumberPattern.ReplaceAllString(text, "")
+	text = norm.NFKC.String(text)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	lines := strings.Split(text, "\n")
+	result := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = normalizeLine(line)
+		if line == "" {
+			// 先頭の空行と連続する空行は除く
+			if !blank && len(result) > 0 {
+				result = append(result, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		result = append(result, line)
+	}
+
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
+
+// NormalizeForSearch は検索用にテキストを正規化する
+// NormalizeTextの結果の改行を空白に置き換え、セルや行を跨ぐ語句でも一致するようにする
+func NormalizeForSearch(text string) string {
+	return strings.Join(strings.Fields(NormalizeText(text)), " ")
+}
+
+// normalizeLine は1行分の空白・箇条書き記号・番号付けを整える
+func normalizeLine(line string) string {
+	line = strings.TrimSpace(horizontalSpacePattern.ReplaceAllString(line, " "))
+	line = bulletPattern.ReplaceAllString(line, "")
+	line = numberingPattern.ReplaceAllString(line, "$2")
+	return strings.TrimSpace(line)
+}
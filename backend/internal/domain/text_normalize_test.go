@@ -0,0 +1,58 @@
+package domain
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "全角英数字と半角カナ",
+			input: "ＰＡＳＳＷＯＲＤは１２文字以上ｶﾞ必要",
+			want:  "PASSWORDは12文字以上ガ必要",
+		},
+		{
+			name:  "連続空白と全角空白",
+			input: "  多要素認証　　を\t使用  ",
+			want:  "多要素認証 を 使用",
+		},
+		{
+			name:  "セル内改行は維持し連続する空行は畳む",
+			input: "1行目\r\n\r\n\r\n2行目\n",
+			want:  "1行目\n\n2行目",
+		},
+		{
+			name:  "箇条書き記号",
+			input: "・パスワード\n● 多要素認証\n－ アクセス制御",
+			want:  "パスワード\n多要素認証\nアクセス制御",
+		},
+		{
+			name:  "番号付け",
+			input: "（１）パスワード\n2) 多要素認証\n３．ログ管理\n①暗号化",
+			want:  "パスワード\n多要素認証\nログ管理\n暗号化",
+		},
+		{
+			name:  "小数は番号付けとして扱わない",
+			input: "1.5GBまで保存できる",
+			want:  "1.5GBまで保存できる",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeText(tt.input); got != tt.want {
+				t.Errorf("NormalizeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeForSearch(t *testing.T) {
+	got := NormalizeForSearch("・パスワードの\r\n　最小文字数は？\n\n")
+	want := "パスワードの 最小文字数は?"
+	if got != want {
+		t.Errorf("NormalizeForSearch() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// ゴミ箱のデータの種類
+const (
+	TrashProject   = "project"
+	TrashFile      = "file"
+	TrashKnowledge = "knowledge"
+)
+
+// DefaultTrashRetentionDays はゴミ箱のデータを完全に削除するまでの既定の保持日数
+const DefaultTrashRetentionDays = 30
+
+// TrashItem はゴミ箱にある案件・ファイル・ナレッジアイテム
+type TrashItem struct {
+	Type      string `json:"type"`
+	ID        int    `json:"id"`
+	ProjectID int    `json:"project_id"`
+	// Name は案件の顧客名、ファイル名、ナレッジアイテムの質問
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// PurgeAt は保持期間が過ぎて完全に削除される日時
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// PurgeResult は完全に削除したデータの件数
+type PurgeResult struct {
+	Projects  int `json:"projects"`
+	Files     int `json:"files"`
+	Knowledge int `json:"knowledge"`
+	// Evidence は完全に削除したナレッジからしか参照されていなかったエビデンスの件数
+	Evidence int `json:"evidence"`
+	// FilePaths は完全に削除したファイル・エビデンスの保存先（物理ファイルの削除に使う）
+	FilePaths []string `json:"-"`
+}
+
+// TrashRepository はゴミ箱のリポジトリのインターフェース
+type TrashRepository interface {
+	// List はゴミ箱のデータを削除日時の新しい順に取得する
+	// 案件と一緒に削除されたファイル・ナレッジアイテムは案件の復元で戻すため含めない
+	List() ([]*TrashItem, error)
+	// RestoreProject は案件と、案件と一緒に削除されたファイル・ナレッジアイテムを復元する
+	RestoreProject(id int) error
+	// RestoreFile はファイルを復元する。案件がゴミ箱にある場合や同名のファイルがある場合はValidationErrorを返す
+	RestoreFile(id int) error
+	// RestoreKnowledge はナレッジアイテムを復元する。案件がゴミ箱にある場合はValidationErrorを返す
+	RestoreKnowledge(id int) error
+	// Purge はbefore以前に削除されたデータと、それらからしか参照されていないエビデンスを完全に削除する
+	Purge(before time.Time) (*PurgeResult, error)
+}
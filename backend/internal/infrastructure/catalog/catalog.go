@@ -0,0 +1,9 @@
+// Package catalog は同梱する統制フレームワークのカタログを提供する
+package catalog
+
+import _ "embed"
+
+// DefaultControlCatalog は起動時に読み込む統制フレームワークのカタログ（JSON）
+//
+//go:embed control_catalog.json
+var DefaultControlCatalog []byte
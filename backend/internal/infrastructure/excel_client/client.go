@@ -146,3 +146,29 @@ func (c *ExcelClient) GetSheetPreview(
 
 	return &result, nil
 }
+
+// ReadSheetRows はシートのセルの値を行ごとに読み取る（sheetNameがnilの場合は最初のシート）
+// 戻り値の添字は0始まりで、1行目・A列が[0][0]になる。空のセルは空文字になる
+func (c *ExcelClient) ReadSheetRows(filePath string, sheetName *string) ([][]string, error) {
+	preview, err := c.GetSheetPreview(filePath, sheetName, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, preview.RowCount)
+	for i := range rows {
+		rows[i] = make([]string, preview.ColumnCount)
+	}
+	for _, cell := range preview.Cells {
+		if cell.FormattedValue == nil {
+			continue
+		}
+		r, col := cell.Row-1, cell.Column-1
+		if r < 0 || r >= len(rows) || col < 0 || col >= len(rows[r]) {
+			continue
+		}
+		rows[r][col] = *cell.FormattedValue
+	}
+
+	return rows, nil
+}
@@ -1,6 +1,8 @@
 package excel_client
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -88,3 +90,30 @@ func TestExcelClient_GetSheetPreview_NonExistentSheet(t *testing.T) {
 	_, err := client.GetSheetPreview(filePath, &sheetName, nil, nil, nil, nil)
 	assert.Error(t, err, "存在しないシート名の場合はエラーになるべき")
 }
+
+func TestExcelClient_ReadSheetRows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/excel/preview", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"sheet_name": "Sheet1",
+			"row_count": 2,
+			"column_count": 2,
+			"cells": [
+				{"row": 1, "column": 1, "value": "question", "formatted_value": "question", "is_merged": false},
+				{"row": 1, "column": 2, "value": "answer", "formatted_value": "answer", "is_merged": false},
+				{"row": 2, "column": 1, "value": "暗号化していますか？", "formatted_value": "暗号化していますか？", "is_merged": false},
+				{"row": 2, "column": 2, "value": null, "formatted_value": null, "is_merged": false}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewExcelClient(server.URL)
+	rows, err := client.ReadSheetRows("/app/uploads/import.xlsx", nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"question", "answer"},
+		{"暗号化していますか？", ""},
+	}, rows)
+}
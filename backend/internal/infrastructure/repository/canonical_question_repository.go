@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// canonicalQuestionColumns は標準質問と紐づくアイテムの集計を取得するカラム
+const canonicalQuestionColumns = `
+	c.id, c.question, c.master_answer, c.department_id,
+	(SELECT COUNT(*) FROM knowledge_items k WHERE k.canonical_question_id = c.id AND k.deleted_at IS NULL),
+	(SELECT COUNT(*) FROM knowledge_items k WHERE k.canonical_question_id = c.id AND k.deleted_at IS NULL AND k.answer_drifted),
+	COALESCE(c.created_by, ''), COALESCE(c.updated_by, ''), c.created_at, c.updated_at`
+
+// CanonicalQuestionRepositoryImpl はCanonicalQuestionRepositoryの実装
+type CanonicalQuestionRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewCanonicalQuestionRepository は新しいCanonicalQuestionRepositoryを生成する
+func NewCanonicalQuestionRepository(db *sql.DB) domain.CanonicalQuestionRepository {
+	return &CanonicalQuestionRepositoryImpl{db: db}
+}
+
+// Create は標準質問を作成する
+func (r *CanonicalQuestionRepositoryImpl) Create(cq *domain.CanonicalQuestion) error {
+	query := `
+		INSERT INTO canonical_questions (
+			question, master_answer, normalized_master_answer, department_id,
+			created_by, updated_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	return r.db.QueryRow(
+		query,
+		cq.Question,
+		cq.MasterAnswer,
+		domain.NormalizeForSearch(cq.MasterAnswer),
+		cq.DepartmentID,
+		cq.CreatedBy,
+		cq.UpdatedBy,
+		cq.CreatedAt,
+		cq.UpdatedAt,
+	).Scan(&cq.ID)
+}
+
+// GetByID は指定されたIDの標準質問を取得する
+func (r *CanonicalQuestionRepositoryImpl) GetByID(id int) (*domain.CanonicalQuestion, error) {
+	query := `SELECT ` + canonicalQuestionColumns + ` FROM canonical_questions c WHERE c.id = $1`
+	return scanCanonicalQuestion(r.db.QueryRow(query, id))
+}
+
+// GetAll は標準質問をID順に取得する
+func (r *CanonicalQuestionRepositoryImpl) GetAll(departmentID int) ([]*domain.CanonicalQuestion, error) {
+	query := `SELECT ` + canonicalQuestionColumns + `
+		FROM canonical_questions c
+		WHERE ($1 = 0 OR c.department_id = $1)
+		ORDER BY c.id ASC
+	`
+
+	rows, err := r.db.Query(query, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []*domain.CanonicalQuestion{}
+	for rows.Next() {
+		cq, err := scanCanonicalQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, cq)
+	}
+
+	return questions, rows.Err()
+}
+
+// Update は標準質問を更新し、紐づくアイテムの乖離フラグを新しい標準回答で判定し直す
+func (r *CanonicalQuestionRepositoryImpl) Update(cq *domain.CanonicalQuestion) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		normalized := domain.NormalizeForSearch(cq.MasterAnswer)
+
+		result, err := tx.Exec(
+			`UPDATE canonical_questions
+			SET question = $1, master_answer = $2, normalized_master_answer = $3, department_id = $4,
+			    updated_by = $5, updated_at = $6
+			WHERE id = $7`,
+			cq.Question,
+			cq.MasterAnswer,
+			normalized,
+			cq.DepartmentID,
+			cq.UpdatedBy,
+			cq.UpdatedAt,
+			cq.ID,
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		_, err = tx.Exec(
+			`UPDATE knowledge_items
+			SET answer_drifted = ($1 <> '' AND normalized_answer <> $1)
+			WHERE canonical_question_id = $2`,
+			normalized,
+			cq.ID,
+		)
+		if err != nil {
+			return err
+		}
+
+		return tx.QueryRow(
+			`SELECT COUNT(*), COUNT(*) FILTER (WHERE answer_drifted) FROM knowledge_items WHERE canonical_question_id = $1 AND deleted_at IS NULL`,
+			cq.ID,
+		).Scan(&cq.LinkedCount, &cq.DriftedCount)
+	})
+}
+
+// Delete は標準質問を削除する。紐づくアイテムの紐づけと乖離フラグは解除される
+func (r *CanonicalQuestionRepositoryImpl) Delete(id int) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE knowledge_items SET answer_drifted = false WHERE canonical_question_id = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM canonical_questions WHERE id = $1`, id)
+		return err
+	})
+}
+
+// GetVariants は標準質問に紐づくナレッジアイテムを案件・ID順に取得する
+func (r *CanonicalQuestionRepositoryImpl) GetVariants(id int) ([]*domain.KnowledgeItem, error) {
+	query := `SELECT ` + knowledgeColumns + `
+		FROM knowledge_items
+		WHERE canonical_question_id = $1 AND deleted_at IS NULL
+		ORDER BY project_id ASC, id ASC
+	`
+	return queryKnowledgeItems(r.db, query, id)
+}
+
+// scanCanonicalQuestion はcanonicalQuestionColumnsの並びで1行を読み取る
+func scanCanonicalQuestion(s rowScanner) (*domain.CanonicalQuestion, error) {
+	cq := &domain.CanonicalQuestion{}
+	err := s.Scan(
+		&cq.ID,
+		&cq.Question,
+		&cq.MasterAnswer,
+		&cq.DepartmentID,
+		&cq.LinkedCount,
+		&cq.DriftedCount,
+		&cq.CreatedBy,
+		&cq.UpdatedBy,
+		&cq.CreatedAt,
+		&cq.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return cq, nil
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// commentColumns はコメントを取得するカラム（scanCommentと同じ並び）
+const commentColumns = `
+	c.id, c.knowledge_item_id, c.author, c.body, c.updated_at > c.created_at, c.created_at, c.updated_at`
+
+// CommentRepositoryImpl はCommentRepositoryの実装
+type CommentRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewCommentRepository は新しいCommentRepositoryを生成する
+func NewCommentRepository(db *sql.DB) domain.CommentRepository {
+	return &CommentRepositoryImpl{db: db}
+}
+
+// Create はコメントとメンションを登録し、スレッドを未解決にする
+func (r *CommentRepositoryImpl) Create(comment *domain.KnowledgeComment) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`INSERT INTO knowledge_comments (knowledge_item_id, author, body, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id`,
+			comment.KnowledgeItemID,
+			comment.Author,
+			comment.Body,
+			comment.CreatedAt,
+			comment.UpdatedAt,
+		).Scan(&comment.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := insertMentions(tx, comment); err != nil {
+			return err
+		}
+
+		// 解決済みのスレッドに新しいコメントがあれば再び未解決として扱う
+		_, err = tx.Exec(
+			`INSERT INTO knowledge_comment_threads (knowledge_item_id, resolved)
+			VALUES ($1, false)
+			ON CONFLICT (knowledge_item_id) DO UPDATE SET resolved = false, resolved_by = NULL, resolved_at = NULL`,
+			comment.KnowledgeItemID,
+		)
+		return err
+	})
+}
+
+// GetByID は指定されたIDのコメントをメンションとともに取得する
+func (r *CommentRepositoryImpl) GetByID(id int) (*domain.KnowledgeComment, error) {
+	query := `SELECT ` + commentColumns + ` FROM knowledge_comments c WHERE c.id = $1`
+	comment, err := scanComment(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadMentions(r.db, []*domain.KnowledgeComment{comment}); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Update は本文を更新し、メンションを置き換える
+func (r *CommentRepositoryImpl) Update(comment *domain.KnowledgeComment) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(
+			`UPDATE knowledge_comments SET body = $1, updated_at = $2 WHERE id = $3`,
+			comment.Body,
+			comment.UpdatedAt,
+			comment.ID,
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if _, err := tx.Exec(`DELETE FROM knowledge_comment_mentions WHERE comment_id = $1`, comment.ID); err != nil {
+			return err
+		}
+		return insertMentions(tx, comment)
+	})
+}
+
+// Delete はコメントを削除する。メンションも削除される
+func (r *CommentRepositoryImpl) Delete(id int) error {
+	_, err := r.db.Exec(`DELETE FROM knowledge_comments WHERE id = $1`, id)
+	return err
+}
+
+// GetThread はアイテムのスレッドをコメントの投稿順に取得する
+func (r *CommentRepositoryImpl) GetThread(knowledgeID int) (*domain.CommentThread, error) {
+	thread := &domain.CommentThread{KnowledgeItemID: knowledgeID, Comments: []*domain.KnowledgeComment{}}
+
+	var resolvedBy sql.NullString
+	err := r.db.QueryRow(
+		`SELECT resolved, resolved_by, resolved_at FROM knowledge_comment_threads WHERE knowledge_item_id = $1`,
+		knowledgeID,
+	).Scan(&thread.Resolved, &resolvedBy, &thread.ResolvedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	thread.ResolvedBy = resolvedBy.String
+
+	query := `SELECT ` + commentColumns + `
+		FROM knowledge_comments c
+		WHERE c.knowledge_item_id = $1
+		ORDER BY c.created_at ASC, c.id ASC
+	`
+	rows, err := r.db.Query(query, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		thread.Comments = append(thread.Comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadMentions(r.db, thread.Comments); err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// SetResolved はスレッドの解決状態を更新する
+func (r *CommentRepositoryImpl) SetResolved(knowledgeID int, resolved bool, actor string, at time.Time) error {
+	var resolvedBy sql.NullString
+	var resolvedAt *time.Time
+	if resolved {
+		resolvedBy = sql.NullString{String: actor, Valid: true}
+		resolvedAt = &at
+	}
+
+	_, err := r.db.Exec(
+		`INSERT INTO knowledge_comment_threads (knowledge_item_id, resolved, resolved_by, resolved_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (knowledge_item_id) DO UPDATE
+		SET resolved = EXCLUDED.resolved, resolved_by = EXCLUDED.resolved_by, resolved_at = EXCLUDED.resolved_at`,
+		knowledgeID,
+		resolved,
+		resolvedBy,
+		resolvedAt,
+	)
+	return err
+}
+
+// openThreadScanner はスレッドの概要のカラムに続く最新のコメントのカラムを読み取るためのrowScanner
+type openThreadScanner struct {
+	rowScanner
+	thread *domain.OpenThread
+}
+
+func (s openThreadScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append([]interface{}{
+		&s.thread.KnowledgeItemID,
+		&s.thread.Question,
+		&s.thread.Status,
+		&s.thread.DepartmentID,
+		&s.thread.CommentCount,
+	}, dest...)...)
+}
+
+// GetOpenThreads は案件内でコメントがあり未解決のスレッドをアイテムのID順に取得する
+func (r *CommentRepositoryImpl) GetOpenThreads(projectID int, filter domain.OpenThreadFilter) ([]*domain.OpenThread, error) {
+	query := `WITH counts AS (
+			SELECT knowledge_item_id, COUNT(*) AS comment_count, MAX(id) AS last_comment_id
+			FROM knowledge_comments
+			GROUP BY knowledge_item_id
+		)
+		SELECT k.id, k.question, COALESCE(k.status, 'draft'), k.department_id, cc.comment_count, ` + commentColumns + `
+		FROM knowledge_items k
+		JOIN counts cc ON cc.knowledge_item_id = k.id
+		JOIN knowledge_comments c ON c.id = cc.last_comment_id
+		LEFT JOIN knowledge_comment_threads t ON t.knowledge_item_id = k.id
+		WHERE k.project_id = $1 AND k.deleted_at IS NULL
+		  AND NOT COALESCE(t.resolved, false)
+		  AND ($2::text = '' OR EXISTS (
+			SELECT 1 FROM knowledge_comments mc
+			JOIN knowledge_comment_mentions m ON m.comment_id = mc.id
+			WHERE mc.knowledge_item_id = k.id AND m.user_name = $2::text
+		  ))
+		  AND ($3::int IS NULL OR EXISTS (
+			SELECT 1 FROM knowledge_comments mc
+			JOIN knowledge_comment_mentions m ON m.comment_id = mc.id
+			WHERE mc.knowledge_item_id = k.id AND m.department_id = $3::int
+		  ))
+		ORDER BY k.id ASC
+	`
+
+	rows, err := r.db.Query(query, projectID, filter.MentionedUser, filter.MentionedDepartmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	threads := []*domain.OpenThread{}
+	comments := []*domain.KnowledgeComment{}
+	for rows.Next() {
+		thread := &domain.OpenThread{}
+		comment, err := scanComment(openThreadScanner{rowScanner: rows, thread: thread})
+		if err != nil {
+			return nil, err
+		}
+		thread.LastComment = comment
+		threads = append(threads, thread)
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadMentions(r.db, comments); err != nil {
+		return nil, err
+	}
+	return threads, nil
+}
+
+// insertMentions はコメントのメンションを登録する
+func insertMentions(q querier, comment *domain.KnowledgeComment) error {
+	for _, m := range comment.Mentions {
+		var userName sql.NullString
+		if m.Type == domain.MentionUser {
+			userName = sql.NullString{String: m.UserName, Valid: true}
+		}
+		if _, err := q.Exec(
+			`INSERT INTO knowledge_comment_mentions (comment_id, user_name, department_id) VALUES ($1, $2, $3)`,
+			comment.ID,
+			userName,
+			m.DepartmentID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMentions はコメントのメンションを登録順にまとめて読み込む
+func loadMentions(q querier, comments []*domain.KnowledgeComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*domain.KnowledgeComment, len(comments))
+	ids := make([]int, 0, len(comments))
+	for _, c := range comments {
+		c.Mentions = []*domain.CommentMention{}
+		byID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+
+	rows, err := q.Query(
+		`SELECT m.comment_id, m.user_name, m.department_id, d.name
+		FROM knowledge_comment_mentions m
+		LEFT JOIN departments d ON d.id = m.department_id
+		WHERE m.comment_id = ANY($1)
+		ORDER BY m.comment_id ASC, m.id ASC`,
+		pq.Array(int64s(ids)),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID int
+		var userName, departmentName sql.NullString
+		mention := &domain.CommentMention{}
+		if err := rows.Scan(&commentID, &userName, &mention.DepartmentID, &departmentName); err != nil {
+			return err
+		}
+		if mention.DepartmentID != nil {
+			mention.Type = domain.MentionDepartment
+			mention.DepartmentName = departmentName.String
+		} else {
+			mention.Type = domain.MentionUser
+			mention.UserName = userName.String
+		}
+		if c, ok := byID[commentID]; ok {
+			c.Mentions = append(c.Mentions, mention)
+		}
+	}
+
+	return rows.Err()
+}
+
+// scanComment はcommentColumnsの並びで1行を読み取る
+func scanComment(s rowScanner) (*domain.KnowledgeComment, error) {
+	comment := &domain.KnowledgeComment{Mentions: []*domain.CommentMention{}}
+	err := s.Scan(
+		&comment.ID,
+		&comment.KnowledgeItemID,
+		&comment.Author,
+		&comment.Body,
+		&comment.Edited,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
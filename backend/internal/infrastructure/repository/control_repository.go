@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// controlColumns は統制項目とフレームワークのコードを結合して取得するカラム（scanControlと同じ並び）
+const controlColumns = `
+	c.id, c.framework_id, f.code, c.control_id, c.title, c.category, c.description, c.display_order`
+
+// ControlRepositoryImpl はControlRepositoryの実装
+type ControlRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewControlRepository は新しいControlRepositoryを生成する
+func NewControlRepository(db *sql.DB) domain.ControlRepository {
+	return &ControlRepositoryImpl{db: db}
+}
+
+// UpsertCatalog はフレームワークと統制項目をコード・統制番号で作成または更新する
+// カタログにない既存の統制項目は紐づけを保つため削除しない
+func (r *ControlRepositoryImpl) UpsertCatalog(frameworks []*domain.ControlFramework) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		for _, f := range frameworks {
+			err := tx.QueryRow(
+				`INSERT INTO control_frameworks (code, name, version)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, version = EXCLUDED.version
+				RETURNING id, created_at`,
+				f.Code,
+				f.Name,
+				f.Version,
+			).Scan(&f.ID, &f.CreatedAt)
+			if err != nil {
+				return err
+			}
+
+			for i, c := range f.Controls {
+				c.FrameworkID = f.ID
+				c.FrameworkCode = f.Code
+				c.DisplayOrder = i + 1
+				err := tx.QueryRow(
+					`INSERT INTO controls (framework_id, control_id, title, category, description, display_order)
+					VALUES ($1, $2, $3, $4, $5, $6)
+					ON CONFLICT (framework_id, control_id) DO UPDATE
+					SET title = EXCLUDED.title, category = EXCLUDED.category,
+					    description = EXCLUDED.description, display_order = EXCLUDED.display_order
+					RETURNING id`,
+					c.FrameworkID,
+					c.ControlID,
+					c.Title,
+					c.Category,
+					c.Description,
+					c.DisplayOrder,
+				).Scan(&c.ID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetFrameworks はすべてのフレームワークをコード順に取得する
+func (r *ControlRepositoryImpl) GetFrameworks() ([]*domain.ControlFramework, error) {
+	rows, err := r.db.Query(`SELECT id, code, name, version, created_at FROM control_frameworks ORDER BY code ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	frameworks := []*domain.ControlFramework{}
+	for rows.Next() {
+		f := &domain.ControlFramework{}
+		if err := rows.Scan(&f.ID, &f.Code, &f.Name, &f.Version, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		frameworks = append(frameworks, f)
+	}
+
+	return frameworks, rows.Err()
+}
+
+// GetFrameworkByCode はコードでフレームワークを取得する
+func (r *ControlRepositoryImpl) GetFrameworkByCode(code string) (*domain.ControlFramework, error) {
+	f := &domain.ControlFramework{}
+	err := r.db.QueryRow(
+		`SELECT id, code, name, version, created_at FROM control_frameworks WHERE code = $1`,
+		code,
+	).Scan(&f.ID, &f.Code, &f.Name, &f.Version, &f.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// GetControls はフレームワークの統制項目を表示順に取得する
+func (r *ControlRepositoryImpl) GetControls(frameworkID int) ([]*domain.Control, error) {
+	query := `SELECT ` + controlColumns + `
+		FROM controls c
+		JOIN control_frameworks f ON f.id = c.framework_id
+		WHERE c.framework_id = $1
+		ORDER BY c.display_order ASC, c.id ASC
+	`
+	return r.queryControls(query, frameworkID)
+}
+
+// CountControls は指定されたIDのうち存在する統制項目の数を返す
+func (r *ControlRepositoryImpl) CountControls(ids []int) (int, error) {
+	return countRows(r.db, `SELECT COUNT(*) FROM controls WHERE id = ANY($1)`, pq.Array(int64s(ids)))
+}
+
+// GetByKnowledgeID はアイテムに直接または質問グループを通じて紐づく統制項目を取得する
+func (r *ControlRepositoryImpl) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeControl, error) {
+	query := `SELECT ` + controlColumns + `, e.source
+		FROM knowledge_effective_controls e
+		JOIN controls c ON c.id = e.control_id
+		JOIN control_frameworks f ON f.id = c.framework_id
+		WHERE e.knowledge_item_id = $1
+		ORDER BY f.code ASC, c.display_order ASC, e.source ASC
+	`
+
+	rows, err := r.db.Query(query, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	controls := []*domain.KnowledgeControl{}
+	for rows.Next() {
+		kc := &domain.KnowledgeControl{}
+		control, err := scanControl(sourceScanner{rows, &kc.Source})
+		if err != nil {
+			return nil, err
+		}
+		kc.Control = control
+		controls = append(controls, kc)
+	}
+
+	return controls, rows.Err()
+}
+
+// ReplaceKnowledgeControls はアイテムに直接紐づける統制項目を置き換える
+func (r *ControlRepositoryImpl) ReplaceKnowledgeControls(knowledgeID int, controlIDs []int, actor string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM knowledge_item_controls WHERE knowledge_item_id = $1`, knowledgeID); err != nil {
+			return err
+		}
+
+		for _, id := range controlIDs {
+			_, err := tx.Exec(
+				`INSERT INTO knowledge_item_controls (knowledge_item_id, control_id, created_by) VALUES ($1, $2, $3)`,
+				knowledgeID,
+				id,
+				actor,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByQuestionGroup は質問グループに紐づく統制項目を取得する
+func (r *ControlRepositoryImpl) GetByQuestionGroup(questionGroup string) ([]*domain.Control, error) {
+	query := `SELECT ` + controlColumns + `
+		FROM question_group_controls g
+		JOIN controls c ON c.id = g.control_id
+		JOIN control_frameworks f ON f.id = c.framework_id
+		WHERE g.question_group = $1
+		ORDER BY f.code ASC, c.display_order ASC
+	`
+	return r.queryControls(query, questionGroup)
+}
+
+// ReplaceQuestionGroupControls は質問グループに紐づける統制項目を置き換える
+func (r *ControlRepositoryImpl) ReplaceQuestionGroupControls(questionGroup string, controlIDs []int, actor string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM question_group_controls WHERE question_group = $1`, questionGroup); err != nil {
+			return err
+		}
+
+		for _, id := range controlIDs {
+			_, err := tx.Exec(
+				`INSERT INTO question_group_controls (question_group, control_id, created_by) VALUES ($1, $2, $3)`,
+				questionGroup,
+				id,
+				actor,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetCoverage はフレームワークの統制項目ごとに紐づくアイテム数と承認済み・公開済みのアイテム数を集計する
+// prefixは統制番号の区切り（.）単位で一致させる（A.1はA.1とA.1.xに一致し、A.10には一致しない）
+func (r *ControlRepositoryImpl) GetCoverage(frameworkID int, prefix string, projectID int) ([]*domain.ControlCoverage, error) {
+	query := `SELECT ` + controlColumns + `,
+			COUNT(DISTINCT k.id),
+			COUNT(DISTINCT k.id) FILTER (WHERE k.status IN ($4, $5))
+		FROM controls c
+		JOIN control_frameworks f ON f.id = c.framework_id
+		LEFT JOIN knowledge_effective_controls e ON e.control_id = c.id
+		LEFT JOIN knowledge_items k ON k.id = e.knowledge_item_id AND k.deleted_at IS NULL AND ($3 = 0 OR k.project_id = $3)
+		WHERE c.framework_id = $1 AND ($2 = '' OR c.control_id = $2 OR c.control_id LIKE $6)
+		GROUP BY c.id, f.code
+		ORDER BY c.display_order ASC, c.id ASC
+	`
+
+	rows, err := r.db.Query(query, frameworkID, prefix, projectID, domain.StatusApproved, domain.StatusPublished, escapeLike(prefix)+".%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coverage := []*domain.ControlCoverage{}
+	for rows.Next() {
+		cc := &domain.ControlCoverage{}
+		control, err := scanControl(countsScanner{rows, &cc.ItemCount, &cc.ApprovedCount})
+		if err != nil {
+			return nil, err
+		}
+		cc.Control = control
+		coverage = append(coverage, cc)
+	}
+
+	return coverage, rows.Err()
+}
+
+// queryControls はクエリを実行して統制項目の一覧を読み取る
+func (r *ControlRepositoryImpl) queryControls(query string, args ...interface{}) ([]*domain.Control, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	controls := []*domain.Control{}
+	for rows.Next() {
+		control, err := scanControl(rows)
+		if err != nil {
+			return nil, err
+		}
+		controls = append(controls, control)
+	}
+
+	return controls, rows.Err()
+}
+
+// sourceScanner は統制項目のカラムに続く紐づけ元の列を読み取るためのrowScanner
+type sourceScanner struct {
+	rowScanner
+	source *string
+}
+
+func (s sourceScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.source)...)
+}
+
+// countsScanner は統制項目のカラムに続く件数の列を読み取るためのrowScanner
+type countsScanner struct {
+	rowScanner
+	itemCount     *int
+	approvedCount *int
+}
+
+func (s countsScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.itemCount, s.approvedCount)...)
+}
+
+// scanControl はcontrolColumnsの並びで1行を読み取る
+func scanControl(s rowScanner) (*domain.Control, error) {
+	c := &domain.Control{}
+	err := s.Scan(
+		&c.ID,
+		&c.FrameworkID,
+		&c.FrameworkCode,
+		&c.ControlID,
+		&c.Title,
+		&c.Category,
+		&c.Description,
+		&c.DisplayOrder,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
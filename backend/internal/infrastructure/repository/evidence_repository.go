@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// evidenceColumns はエビデンスと紐づくアイテム数（ゴミ箱のアイテムを除く）を取得するカラム（scanEvidenceFileと同じ並び）
+const evidenceColumns = `
+	e.id, e.file_name, e.file_path, COALESCE(e.file_size, 0), e.content_type, e.description,
+	e.expires_at, COALESCE(e.uploaded_by, ''), e.uploaded_at,
+	(SELECT COUNT(*) FROM knowledge_item_evidence ke
+		JOIN knowledge_items k ON k.id = ke.knowledge_item_id
+		WHERE ke.evidence_file_id = e.id AND k.deleted_at IS NULL)`
+
+// EvidenceRepositoryImpl はEvidenceRepositoryの実装
+type EvidenceRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewEvidenceRepository は新しいEvidenceRepositoryを生成する
+func NewEvidenceRepository(db *sql.DB) domain.EvidenceRepository {
+	return &EvidenceRepositoryImpl{db: db}
+}
+
+// CreateAndLink はエビデンスを登録し、ナレッジアイテムに紐づける
+// 紐づけに失敗した場合に紐づけのないエビデンスが残らないよう、同じトランザクションで実行する
+func (r *EvidenceRepositoryImpl) CreateAndLink(file *domain.EvidenceFile, knowledgeID int, actor string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`INSERT INTO evidence_files (file_name, file_path, file_size, content_type, description, expires_at, uploaded_by, uploaded_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			file.FileName,
+			file.FilePath,
+			file.FileSize,
+			file.ContentType,
+			file.Description,
+			file.ExpiresAt,
+			file.UploadedBy,
+			file.UploadedAt,
+		).Scan(&file.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO knowledge_item_evidence (knowledge_item_id, evidence_file_id, linked_by) VALUES ($1, $2, $3)`,
+			knowledgeID,
+			file.ID,
+			actor,
+		)
+		return err
+	})
+}
+
+// GetByID は指定されたIDのエビデンスを取得する
+func (r *EvidenceRepositoryImpl) GetByID(id int) (*domain.EvidenceFile, error) {
+	query := `SELECT ` + evidenceColumns + ` FROM evidence_files e WHERE e.id = $1`
+	return scanEvidenceFile(r.db.QueryRow(query, id))
+}
+
+// GetAll はすべてのエビデンスを有効期限の早い順（期限なしは最後）に取得する
+func (r *EvidenceRepositoryImpl) GetAll() ([]*domain.EvidenceFile, error) {
+	query := `SELECT ` + evidenceColumns + `
+		FROM evidence_files e
+		ORDER BY e.expires_at ASC NULLS LAST, e.id ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []*domain.EvidenceFile{}
+	for rows.Next() {
+		file, err := scanEvidenceFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+// Update は説明と有効期限を更新する
+func (r *EvidenceRepositoryImpl) Update(file *domain.EvidenceFile) error {
+	result, err := r.db.Exec(
+		`UPDATE evidence_files SET description = $1, expires_at = $2 WHERE id = $3`,
+		file.Description,
+		file.ExpiresAt,
+		file.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Link はナレッジアイテムにエビデンスを紐づける
+func (r *EvidenceRepositoryImpl) Link(knowledgeID int, evidenceID int, actor string) error {
+	_, err := r.db.Exec(
+		`INSERT INTO knowledge_item_evidence (knowledge_item_id, evidence_file_id, linked_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (knowledge_item_id, evidence_file_id) DO NOTHING`,
+		knowledgeID,
+		evidenceID,
+		actor,
+	)
+	return err
+}
+
+// Unlink は紐づけを解除し、どのアイテムからも参照されなくなったエビデンスを同じトランザクションで削除する
+// ゴミ箱のアイテムは復元できるため、ゴミ箱のアイテムとの紐づけが残っている場合は削除しない
+// 解除と削除の間に紐づけが追加されても消さないよう、削除時に紐づけがないことを確認する
+func (r *EvidenceRepositoryImpl) Unlink(knowledgeID int, evidenceID int) (bool, error) {
+	var deleted bool
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(
+			`DELETE FROM knowledge_item_evidence WHERE knowledge_item_id = $1 AND evidence_file_id = $2`,
+			knowledgeID,
+			evidenceID,
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		result, err = tx.Exec(
+			`DELETE FROM evidence_files e
+			WHERE e.id = $1 AND NOT EXISTS (SELECT 1 FROM knowledge_item_evidence ke WHERE ke.evidence_file_id = e.id)`,
+			evidenceID,
+		)
+		if err != nil {
+			return err
+		}
+		affected, err = result.RowsAffected()
+		deleted = affected > 0
+		return err
+	})
+	return deleted, err
+}
+
+// linkedEvidenceScanner はエビデンスのカラムに続く紐づけのカラムを読み取るためのrowScanner
+type linkedEvidenceScanner struct {
+	rowScanner
+	link *domain.KnowledgeEvidence
+}
+
+func (s linkedEvidenceScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, &s.link.KnowledgeItemID, &s.link.LinkedBy, &s.link.LinkedAt)...)
+}
+
+// GetByKnowledgeID はアイテムに紐づくエビデンスを紐づけた順に取得する
+func (r *EvidenceRepositoryImpl) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeEvidence, error) {
+	query := `SELECT ` + evidenceColumns + `, ke.knowledge_item_id, COALESCE(ke.linked_by, ''), ke.linked_at
+		FROM evidence_files e
+		JOIN knowledge_item_evidence ke ON ke.evidence_file_id = e.id
+		WHERE ke.knowledge_item_id = $1
+		ORDER BY ke.linked_at ASC, e.id ASC
+	`
+
+	rows, err := r.db.Query(query, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []*domain.KnowledgeEvidence{}
+	for rows.Next() {
+		link := &domain.KnowledgeEvidence{}
+		file, err := scanEvidenceFile(linkedEvidenceScanner{rowScanner: rows, link: link})
+		if err != nil {
+			return nil, err
+		}
+		link.EvidenceFile = file
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// GetByKnowledgeIDs は複数のアイテムに紐づくエビデンスをアイテムIDごとに取得する
+func (r *EvidenceRepositoryImpl) GetByKnowledgeIDs(knowledgeIDs []int) (map[int][]*domain.EvidenceFile, error) {
+	result := map[int][]*domain.EvidenceFile{}
+	if len(knowledgeIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT ` + evidenceColumns + `, ke.knowledge_item_id, COALESCE(ke.linked_by, ''), ke.linked_at
+		FROM evidence_files e
+		JOIN knowledge_item_evidence ke ON ke.evidence_file_id = e.id
+		WHERE ke.knowledge_item_id = ANY($1)
+		ORDER BY ke.knowledge_item_id ASC, ke.linked_at ASC, e.id ASC
+	`
+
+	rows, err := r.db.Query(query, pq.Array(int64s(knowledgeIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		link := &domain.KnowledgeEvidence{}
+		file, err := scanEvidenceFile(linkedEvidenceScanner{rowScanner: rows, link: link})
+		if err != nil {
+			return nil, err
+		}
+		result[link.KnowledgeItemID] = append(result[link.KnowledgeItemID], file)
+	}
+
+	return result, rows.Err()
+}
+
+// scanEvidenceFile はevidenceColumnsの並びで1行を読み取る
+func scanEvidenceFile(s rowScanner) (*domain.EvidenceFile, error) {
+	file := &domain.EvidenceFile{}
+	err := s.Scan(
+		&file.ID,
+		&file.FileName,
+		&file.FilePath,
+		&file.FileSize,
+		&file.ContentType,
+		&file.Description,
+		&file.ExpiresAt,
+		&file.UploadedBy,
+		&file.UploadedAt,
+		&file.ItemCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
@@ -38,16 +38,25 @@ func (r *FileRepositoryImpl) Create(file *domain.UploadedFile) error {
 	return err
 }
 
-// GetByID は指定されたIDのファイルを取得する
+// GetByID は指定されたIDのファイルを取得する（ゴミ箱のファイルは含めない）
 func (r *FileRepositoryImpl) GetByID(id int) (*domain.UploadedFile, error) {
+	return r.getFile(`WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+// GetByIDIncludingTrash は指定されたIDのファイルをゴミ箱のファイルも含めて取得する
+func (r *FileRepositoryImpl) GetByIDIncludingTrash(id int) (*domain.UploadedFile, error) {
+	return r.getFile(`WHERE id = $1`, id)
+}
+
+// getFile は条件に一致するファイルを1件取得する
+func (r *FileRepositoryImpl) getFile(where string, args ...interface{}) (*domain.UploadedFile, error) {
 	query := `
 		SELECT id, project_id, file_name, file_path, file_size, uploaded_by, uploaded_at
 		FROM uploaded_files
-		WHERE id = $1
-	`
+	` + where
 
 	file := &domain.UploadedFile{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, args...).Scan(
 		&file.ID,
 		&file.ProjectID,
 		&file.FileName,
@@ -64,18 +73,23 @@ func (r *FileRepositoryImpl) GetByID(id int) (*domain.UploadedFile, error) {
 	return file, nil
 }
 
-// GetByProjectID は指定された案件のすべてのファイルを取得する
-func (r *FileRepositoryImpl) GetByProjectID(projectID int) ([]*domain.UploadedFile, error) {
+// GetByProjectID は指定された案件のファイル一覧と総件数を取得する
+func (r *FileRepositoryImpl) GetByProjectID(projectID int, page domain.PageRequest) ([]*domain.UploadedFile, int, error) {
+	total, err := countRows(r.db, `SELECT COUNT(*) FROM uploaded_files WHERE project_id = $1 AND deleted_at IS NULL`, projectID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	clause, pageArgs := pageClause(page, domain.FileSortFields, 2)
 	query := `
 		SELECT id, project_id, file_name, file_path, file_size, uploaded_by, uploaded_at
 		FROM uploaded_files
-		WHERE project_id = $1
-		ORDER BY uploaded_at DESC
-	`
+		WHERE project_id = $1 AND deleted_at IS NULL
+	` + clause
 
-	rows, err := r.db.Query(query, projectID)
+	rows, err := r.db.Query(query, append([]interface{}{projectID}, pageArgs...)...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -92,17 +106,26 @@ func (r *FileRepositoryImpl) GetByProjectID(projectID int) ([]*domain.UploadedFi
 			&file.UploadedAt,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		files = append(files, file)
 	}
 
-	return files, nil
+	return files, total, nil
 }
 
-// Delete はファイルを削除する
+// Delete はファイルをゴミ箱に移す（物理ファイルは完全に削除するまで残す）
 func (r *FileRepositoryImpl) Delete(id int) error {
-	query := `DELETE FROM uploaded_files WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := r.db.Exec(`UPDATE uploaded_files SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
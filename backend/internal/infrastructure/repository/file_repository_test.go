@@ -80,9 +80,17 @@ func TestFileRepository_GetByProjectID(t *testing.T) {
 	require.NoError(t, fileRepo.Create(file2))
 
 	// プロジェクトIDでファイルを取得
-	files, err := fileRepo.GetByProjectID(project.ID)
+	files, total, err := fileRepo.GetByProjectID(project.ID, domain.PageRequest{})
 	assert.NoError(t, err)
 	assert.Len(t, files, 2)
+	assert.Equal(t, 2, total)
+
+	// ページング
+	files, total, err = fileRepo.GetByProjectID(project.ID, domain.PageRequest{Limit: 1, Sort: "file_name", Order: "asc"})
+	assert.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "test1.xlsx", files[0].FileName)
+	assert.Equal(t, 2, total)
 }
 
 func TestFileRepository_Delete(t *testing.T) {
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+const knowledgeAnswerColumns = `
+	id, knowledge_item_id, label, answer, department_id, display_order, status,
+	created_by, created_at, updated_at
+`
+
+// KnowledgeAnswerRepositoryImpl はKnowledgeAnswerRepositoryの実装
+type KnowledgeAnswerRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeAnswerRepository は新しいKnowledgeAnswerRepositoryを生成する
+func NewKnowledgeAnswerRepository(db *sql.DB) domain.KnowledgeAnswerRepository {
+	return &KnowledgeAnswerRepositoryImpl{db: db}
+}
+
+// insertKnowledgeAnswer は回答バリエーションを1件作成する
+func insertKnowledgeAnswer(q querier, answer *domain.KnowledgeAnswer) error {
+	query := `
+		INSERT INTO knowledge_answers (
+			knowledge_item_id, label, answer, department_id, display_order, status, created_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	return q.QueryRow(
+		query,
+		answer.KnowledgeItemID,
+		answer.Label,
+		answer.Answer,
+		answer.DepartmentID,
+		answer.DisplayOrder,
+		answer.Status,
+		answer.CreatedBy,
+		time.Now(),
+		time.Now(),
+	).Scan(&answer.ID, &answer.CreatedAt, &answer.UpdatedAt)
+}
+
+// updateKnowledgeAnswer は既存の回答バリエーションを更新する
+func updateKnowledgeAnswer(q querier, answer *domain.KnowledgeAnswer) error {
+	query := `
+		UPDATE knowledge_answers
+		SET label = $1, answer = $2, department_id = $3, display_order = $4, status = $5, updated_at = $6
+		WHERE id = $7 AND knowledge_item_id = $8
+		RETURNING created_by, created_at, updated_at
+	`
+
+	err := q.QueryRow(
+		query,
+		answer.Label,
+		answer.Answer,
+		answer.DepartmentID,
+		answer.DisplayOrder,
+		answer.Status,
+		time.Now(),
+		answer.ID,
+		answer.KnowledgeItemID,
+	).Scan(&answer.CreatedBy, &answer.CreatedAt, &answer.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return &domain.ValidationError{
+			Field:   "answers",
+			Message: fmt.Sprintf("回答バリエーションが見つかりません: %d", answer.ID),
+		}
+	}
+
+	return err
+}
+
+// Replace はナレッジアイテムを更新し、回答バリエーションをitem.Answersの内容に置き換える
+// IDを持つ回答は更新、持たない回答は作成し、一覧に含まれない回答は削除する
+func (r *KnowledgeAnswerRepositoryImpl) Replace(item *domain.KnowledgeItem, expectedVersion int) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if err := updateKnowledgeItem(tx, item, expectedVersion); err != nil {
+			return err
+		}
+
+		keep := []int64{}
+		for _, answer := range item.Answers {
+			if answer.ID > 0 {
+				keep = append(keep, int64(answer.ID))
+			}
+		}
+
+		_, err := tx.Exec(
+			`DELETE FROM knowledge_answers WHERE knowledge_item_id = $1 AND NOT (id = ANY($2))`,
+			item.ID,
+			pq.Array(keep),
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, answer := range item.Answers {
+			answer.KnowledgeItemID = item.ID
+			if answer.ID > 0 {
+				err = updateKnowledgeAnswer(tx, answer)
+			} else {
+				err = insertKnowledgeAnswer(tx, answer)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByKnowledgeID は指定されたナレッジアイテムの回答バリエーションを表示順に取得する
+func (r *KnowledgeAnswerRepositoryImpl) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeAnswer, error) {
+	query := `SELECT ` + knowledgeAnswerColumns + `
+		FROM knowledge_answers
+		WHERE knowledge_item_id = $1
+		ORDER BY display_order ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []*domain.KnowledgeAnswer{}
+	for rows.Next() {
+		answer, err := scanKnowledgeAnswer(rows)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, answer)
+	}
+
+	return answers, rows.Err()
+}
+
+// GetByKnowledgeIDs は複数のナレッジアイテムの回答バリエーションをまとめて取得する
+func (r *KnowledgeAnswerRepositoryImpl) GetByKnowledgeIDs(knowledgeIDs []int) (map[int][]*domain.KnowledgeAnswer, error) {
+	result := make(map[int][]*domain.KnowledgeAnswer)
+	if len(knowledgeIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT ` + knowledgeAnswerColumns + `
+		FROM knowledge_answers
+		WHERE knowledge_item_id = ANY($1)
+		ORDER BY knowledge_item_id ASC, display_order ASC, id ASC
+	`
+
+	ids := make([]int64, len(knowledgeIDs))
+	for i, id := range knowledgeIDs {
+		ids[i] = int64(id)
+	}
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		answer, err := scanKnowledgeAnswer(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[answer.KnowledgeItemID] = append(result[answer.KnowledgeItemID], answer)
+	}
+
+	return result, rows.Err()
+}
+
+// scanKnowledgeAnswer はknowledgeAnswerColumnsの順で1行を読み取る
+func scanKnowledgeAnswer(s rowScanner) (*domain.KnowledgeAnswer, error) {
+	answer := &domain.KnowledgeAnswer{}
+	err := s.Scan(
+		&answer.ID,
+		&answer.KnowledgeItemID,
+		&answer.Label,
+		&answer.Answer,
+		&answer.DepartmentID,
+		&answer.DisplayOrder,
+		&answer.Status,
+		&answer.CreatedBy,
+		&answer.CreatedAt,
+		&answer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
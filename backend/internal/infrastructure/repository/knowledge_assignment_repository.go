@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// KnowledgeAssignmentRepositoryImpl はKnowledgeAssignmentRepositoryの実装
+type KnowledgeAssignmentRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeAssignmentRepository は新しいKnowledgeAssignmentRepositoryを生成する
+func NewKnowledgeAssignmentRepository(db *sql.DB) domain.KnowledgeAssignmentRepository {
+	return &KnowledgeAssignmentRepositoryImpl{db: db}
+}
+
+// BulkAssign は案件内の対象アイテムに割り当てを反映し、更新後のアイテムをID順に返す
+func (r *KnowledgeAssignmentRepositoryImpl) BulkAssign(projectID int, target domain.AssignmentTarget, change domain.AssignmentChange, actor string) ([]*domain.KnowledgeItem, error) {
+	args := []interface{}{projectID}
+	var set []string
+	addSet := func(column string, arg interface{}) {
+		args = append(args, arg)
+		set = append(set, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if change.Assignee != nil {
+		addSet("assignee", *change.Assignee)
+	}
+	if change.AssignmentDueAt != nil {
+		addSet("assignment_due_at", *change.AssignmentDueAt)
+	}
+	// 部門は内容の一部のため、変更する場合は版を進める
+	if change.DepartmentID != nil {
+		addSet("department_id", *change.DepartmentID)
+		addSet("updated_by", actor)
+		addSet("updated_at", time.Now())
+		set = append(set, "version = version + 1")
+	}
+
+	var where string
+	if len(target.KnowledgeIDs) > 0 {
+		args = append(args, pq.Array(int64s(target.KnowledgeIDs)))
+		where = fmt.Sprintf("id = ANY($%d)", len(args))
+	} else {
+		args = append(args, *target.DepartmentID)
+		where = fmt.Sprintf("department_id = $%d", len(args))
+	}
+
+	query := `UPDATE knowledge_items SET ` + strings.Join(set, ", ") + `
+		WHERE project_id = $1 AND deleted_at IS NULL AND ` + where + `
+		RETURNING ` + knowledgeColumns
+
+	var items []*domain.KnowledgeItem
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		var err error
+		items, err = queryKnowledgeItems(tx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if missing := missingKnowledgeIDs(target.KnowledgeIDs, items); len(missing) > 0 {
+			return &domain.ValidationError{Field: "knowledge_ids", Message: fmt.Sprintf("案件に存在しないナレッジIDが含まれています: %v", missing)}
+		}
+
+		if change.DepartmentID == nil {
+			return nil
+		}
+		for _, item := range items {
+			if err := insertKnowledgeRevision(tx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// missingKnowledgeIDs はidsのうちitemsに含まれないIDを返す
+func missingKnowledgeIDs(ids []int, items []*domain.KnowledgeItem) []int {
+	found := make(map[int]bool, len(items))
+	for _, item := range items {
+		found[item.ID] = true
+	}
+
+	var missing []int
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// UpdateTask はアイテムの担当者・期限・タスクの状態を置き換える
+func (r *KnowledgeAssignmentRepositoryImpl) UpdateTask(item *domain.KnowledgeItem) error {
+	result, err := r.db.Exec(
+		`UPDATE knowledge_items SET assignee = $1, assignment_due_at = $2, task_state = $3 WHERE id = $4 AND deleted_at IS NULL`,
+		item.Assignee,
+		item.AssignmentDueAt,
+		item.TaskState,
+		item.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetProgress は案件のアイテムを部門ごとに集計する（部門の表示順、部門未設定は最後）
+func (r *KnowledgeAssignmentRepositoryImpl) GetProgress(projectID int, now time.Time) ([]*domain.DepartmentProgress, error) {
+	query := `
+		SELECT k.department_id, COALESCE(d.name, ''),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE btrim(COALESCE(k.answer, '')) <> ''),
+			COUNT(*) FILTER (WHERE k.status = ANY($2)),
+			COUNT(*) FILTER (WHERE k.task_state = $3),
+			COUNT(*) FILTER (WHERE k.task_state = $4),
+			COUNT(*) FILTER (WHERE k.task_state = $5),
+			COUNT(*) FILTER (WHERE k.assignment_due_at < $6 AND k.task_state <> $5)
+		FROM knowledge_items k
+		LEFT JOIN departments d ON d.id = k.department_id
+		WHERE k.project_id = $1 AND k.deleted_at IS NULL
+		GROUP BY k.department_id, d.name, d.display_order
+		ORDER BY k.department_id IS NULL, d.display_order ASC, k.department_id ASC
+	`
+
+	rows, err := r.db.Query(
+		query,
+		projectID,
+		pq.Array([]string{domain.StatusApproved, domain.StatusPublished}),
+		domain.TaskTodo,
+		domain.TaskInProgress,
+		domain.TaskDone,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	departments := []*domain.DepartmentProgress{}
+	for rows.Next() {
+		d := &domain.DepartmentProgress{}
+		if err := rows.Scan(
+			&d.DepartmentID,
+			&d.DepartmentName,
+			&d.Total,
+			&d.Answered,
+			&d.Approved,
+			&d.Todo,
+			&d.InProgress,
+			&d.Done,
+			&d.Overdue,
+		); err != nil {
+			return nil, err
+		}
+		departments = append(departments, d)
+	}
+
+	return departments, rows.Err()
+}
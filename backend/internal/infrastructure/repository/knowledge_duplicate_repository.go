@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// KnowledgeDuplicateRepositoryImpl はKnowledgeDuplicateRepositoryの実装
+type KnowledgeDuplicateRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeDuplicateRepository は新しいKnowledgeDuplicateRepositoryを生成する
+func NewKnowledgeDuplicateRepository(db *sql.DB) domain.KnowledgeDuplicateRepository {
+	return &KnowledgeDuplicateRepositoryImpl{db: db}
+}
+
+// FindDuplicatePairs は案件内で正規化した質問が一致するか、similarity()が閾値以上のアイテムの組を取得する
+// 類似の候補は%演算子でトライグラムのインデックスから絞り込み、すべての組の類似度は計算しない
+func (r *KnowledgeDuplicateRepositoryImpl) FindDuplicatePairs(projectID int, threshold float64) ([]*domain.DuplicatePair, error) {
+	query := `
+		SELECT item_id, other_id, exact, CASE WHEN exact THEN 1 ELSE score END
+		FROM (
+			SELECT a.id AS item_id, b.id AS other_id,
+				a.normalized_question = b.normalized_question AS exact,
+				similarity(a.normalized_question, b.normalized_question) AS score
+			FROM knowledge_items a
+			JOIN knowledge_items b ON b.normalized_question % a.normalized_question
+				AND b.project_id = a.project_id AND b.id > a.id AND b.deleted_at IS NULL
+			WHERE a.project_id = $1 AND a.deleted_at IS NULL AND a.normalized_question <> ''
+			UNION
+			-- トライグラムを含まない短い質問は%演算子で一致しないため、完全一致は別に取得する
+			SELECT a.id, b.id, true, 1
+			FROM knowledge_items a
+			JOIN knowledge_items b ON b.normalized_question = a.normalized_question
+				AND b.project_id = a.project_id AND b.id > a.id AND b.deleted_at IS NULL
+			WHERE a.project_id = $1 AND a.deleted_at IS NULL AND a.normalized_question <> ''
+		) pairs
+		WHERE exact OR score >= $2
+		ORDER BY item_id ASC, other_id ASC
+	`
+
+	pairs := []*domain.DuplicatePair{}
+	err := withSimilarityThreshold(r.db, threshold, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, projectID, threshold)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			pair := &domain.DuplicatePair{}
+			if err := rows.Scan(&pair.ItemID, &pair.OtherID, &pair.Exact, &pair.Score); err != nil {
+				return err
+			}
+			pairs = append(pairs, pair)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+// Resolve は重複をゴミ箱に移す。mergeの場合は残すアイテムを更新し、ゴミ箱に移した重複を派生元として記録する
+func (r *KnowledgeDuplicateRepositoryImpl) Resolve(action string, keep *domain.KnowledgeItem, expectedVersion int, duplicates []*domain.KnowledgeItem, actor string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, d := range duplicates {
+			if err := softDeleteKnowledgeItemAtVersion(tx, d.ID, d.Version, now); err != nil {
+				return err
+			}
+		}
+
+		if action != domain.DuplicateResolveMerge {
+			return nil
+		}
+
+		if err := updateKnowledgeItem(tx, keep, expectedVersion); err != nil {
+			return err
+		}
+
+		keep.DerivedFrom = make([]*domain.KnowledgeLineage, 0, len(duplicates))
+		for _, d := range duplicates {
+			lineage := domain.NewKnowledgeLineage(domain.LineageMerge, d, actor)
+			lineage.KnowledgeItemID = keep.ID
+			if err := insertKnowledgeLineage(tx, lineage); err != nil {
+				return err
+			}
+			keep.DerivedFrom = append(keep.DerivedFrom, lineage)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// KnowledgeLineageRepositoryImpl はKnowledgeLineageRepositoryの実装
+type KnowledgeLineageRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeLineageRepository は新しいKnowledgeLineageRepositoryを生成する
+func NewKnowledgeLineageRepository(db *sql.DB) domain.KnowledgeLineageRepository {
+	return &KnowledgeLineageRepositoryImpl{db: db}
+}
+
+// Derive は派生元をゴミ箱に移して派生先を作成し、派生先ごとにすべての派生元を記録する
+func (r *KnowledgeLineageRepositoryImpl) Derive(operation string, sources []*domain.KnowledgeItem, derived []*domain.KnowledgeItem, actor string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, source := range sources {
+			if err := softDeleteKnowledgeItemAtVersion(tx, source.ID, source.Version, now); err != nil {
+				return err
+			}
+		}
+
+		for _, item := range derived {
+			if err := insertKnowledgeItem(tx, item); err != nil {
+				return err
+			}
+
+			item.DerivedFrom = make([]*domain.KnowledgeLineage, 0, len(sources))
+			for _, source := range sources {
+				lineage := domain.NewKnowledgeLineage(operation, source, actor)
+				lineage.KnowledgeItemID = item.ID
+				if err := insertKnowledgeLineage(tx, lineage); err != nil {
+					return err
+				}
+				item.DerivedFrom = append(item.DerivedFrom, lineage)
+			}
+		}
+
+		return nil
+	})
+}
+
+// insertKnowledgeLineage は派生記録を1件作成する
+func insertKnowledgeLineage(q querier, lineage *domain.KnowledgeLineage) error {
+	query := `
+		INSERT INTO knowledge_lineage (
+			knowledge_item_id, source_item_id, operation, source_sheet_name, source_range,
+			source_question, source_answer, created_by, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	return q.QueryRow(
+		query,
+		lineage.KnowledgeItemID,
+		lineage.SourceItemID,
+		lineage.Operation,
+		lineage.SourceSheetName,
+		lineage.SourceRange,
+		lineage.SourceQuestion,
+		lineage.SourceAnswer,
+		lineage.CreatedBy,
+		lineage.CreatedAt,
+	).Scan(&lineage.ID)
+}
+
+// GetByKnowledgeID は指定されたナレッジアイテムの派生元を取得する
+func (r *KnowledgeLineageRepositoryImpl) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeLineage, error) {
+	query := `
+		SELECT id, knowledge_item_id, source_item_id, operation, source_sheet_name, source_range,
+			source_question, source_answer, created_by, created_at
+		FROM knowledge_lineage
+		WHERE knowledge_item_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Query(query, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lineages := []*domain.KnowledgeLineage{}
+	for rows.Next() {
+		l := &domain.KnowledgeLineage{}
+		err := rows.Scan(
+			&l.ID,
+			&l.KnowledgeItemID,
+			&l.SourceItemID,
+			&l.Operation,
+			&l.SourceSheetName,
+			&l.SourceRange,
+			&l.SourceQuestion,
+			&l.SourceAnswer,
+			&l.CreatedBy,
+			&l.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lineages = append(lineages, l)
+	}
+
+	return lineages, nil
+}
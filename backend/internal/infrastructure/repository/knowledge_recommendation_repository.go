@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// recommendationColumns は推薦候補と推薦元アイテムを結合して取得するカラム
+const recommendationColumns = `
+	r.id, r.knowledge_item_id, r.source_item_id, r.rank, r.score, r.status,
+	COALESCE(r.accepted_by, ''), r.accepted_at, r.created_at,
+	s.project_id, s.question, COALESCE(s.answer, ''), s.valid_until`
+
+// KnowledgeRecommendationRepositoryImpl はKnowledgeRecommendationRepositoryの実装
+type KnowledgeRecommendationRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeRecommendationRepository は新しいKnowledgeRecommendationRepositoryを生成する
+func NewKnowledgeRecommendationRepository(db *sql.DB) domain.KnowledgeRecommendationRepository {
+	return &KnowledgeRecommendationRepositoryImpl{db: db}
+}
+
+// ReplacePending はアイテムの未処理の候補を削除し、新しい候補を作成する
+// 採用済み・却下済みの候補は履歴として残す
+func (r *KnowledgeRecommendationRepositoryImpl) ReplacePending(knowledgeItemID int, recommendations []*domain.KnowledgeRecommendation) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`DELETE FROM knowledge_recommendations WHERE knowledge_item_id = $1 AND status = $2`,
+			knowledgeItemID,
+			domain.RecommendationPending,
+		)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO knowledge_recommendations (knowledge_item_id, source_item_id, rank, score, status)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (knowledge_item_id, source_item_id) DO NOTHING
+			RETURNING id, created_at
+		`
+		for _, rec := range recommendations {
+			err := tx.QueryRow(
+				query,
+				knowledgeItemID,
+				rec.SourceItemID,
+				rec.Rank,
+				rec.Score,
+				rec.Status,
+			).Scan(&rec.ID, &rec.CreatedAt)
+			// 既に処理済みの同じ候補がある場合は作成しない
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByID は推薦候補を取得する
+func (r *KnowledgeRecommendationRepositoryImpl) GetByID(id int) (*domain.KnowledgeRecommendation, error) {
+	query := `SELECT ` + recommendationColumns + `
+		FROM knowledge_recommendations r
+		JOIN knowledge_items s ON s.id = r.source_item_id AND s.deleted_at IS NULL
+		WHERE r.id = $1
+	`
+
+	return scanRecommendation(r.db.QueryRow(query, id))
+}
+
+// GetByProjectID は案件内のアイテムに対する推薦候補をアイテム・順位順に取得する
+func (r *KnowledgeRecommendationRepositoryImpl) GetByProjectID(projectID int) ([]*domain.KnowledgeRecommendation, error) {
+	query := `SELECT ` + recommendationColumns + `
+		FROM knowledge_recommendations r
+		JOIN knowledge_items t ON t.id = r.knowledge_item_id AND t.deleted_at IS NULL
+		JOIN knowledge_items s ON s.id = r.source_item_id AND s.deleted_at IS NULL
+		WHERE t.project_id = $1
+		ORDER BY r.knowledge_item_id ASC, r.rank ASC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recommendations := []*domain.KnowledgeRecommendation{}
+	for rows.Next() {
+		rec, err := scanRecommendation(rows)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, rec)
+	}
+
+	return recommendations, rows.Err()
+}
+
+// Accept はアイテムを更新し、採用した候補を採用済み、同じアイテムの他の未処理の候補を却下済みにする
+func (r *KnowledgeRecommendationRepositoryImpl) Accept(recommendation *domain.KnowledgeRecommendation, item *domain.KnowledgeItem, expectedVersion int) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if err := updateKnowledgeItem(tx, item, expectedVersion); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(
+			`UPDATE knowledge_recommendations SET status = $1, accepted_by = $2, accepted_at = $3 WHERE id = $4`,
+			recommendation.Status,
+			recommendation.AcceptedBy,
+			recommendation.AcceptedAt,
+			recommendation.ID,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`UPDATE knowledge_recommendations SET status = $1 WHERE knowledge_item_id = $2 AND status = $3`,
+			domain.RecommendationDismissed,
+			recommendation.KnowledgeItemID,
+			domain.RecommendationPending,
+		)
+		return err
+	})
+}
+
+// scanRecommendation はrecommendationColumnsの並びで1行を読み取る
+func scanRecommendation(s rowScanner) (*domain.KnowledgeRecommendation, error) {
+	rec := &domain.KnowledgeRecommendation{}
+	err := s.Scan(
+		&rec.ID,
+		&rec.KnowledgeItemID,
+		&rec.SourceItemID,
+		&rec.Rank,
+		&rec.Score,
+		&rec.Status,
+		&rec.AcceptedBy,
+		&rec.AcceptedAt,
+		&rec.CreatedAt,
+		&rec.SourceProjectID,
+		&rec.SourceQuestion,
+		&rec.SourceAnswer,
+		&rec.SourceValidUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
@@ -2,12 +2,34 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/security-checksheets/backend/internal/domain"
 )
 
+// knowledgeColumns はknowledge_itemsから取得するカラム（scanKnowledgeItemと同じ並び）
+const knowledgeColumns = `
+	id, project_id, file_id, sheet_name, source_range, question, answer,
+	department_id, question_group, status, rejection_reason, answer_source_id,
+	canonical_question_id, answer_drifted, review_due_at, valid_until, review_interval_days,
+	assignee, assignment_due_at, task_state, version,
+	created_by, updated_by, created_at, updated_at`
+
+// answerDriftedExpr は回答が標準質問の標準回答と異なるかを判定するSQL式
+// %[1]d は正規化した回答、%[2]d は標準質問IDの引数番号。標準回答が未設定の場合は乖離なしとする
+const answerDriftedExpr = `COALESCE((
+	SELECT c.normalized_master_answer <> '' AND c.normalized_master_answer <> $%[1]d
+	FROM canonical_questions c WHERE c.id = $%[2]d
+), false)`
+
+// rowScanner は*sql.Rowと*sql.Rowsに共通するScanインターフェース
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 // KnowledgeRepositoryImpl はKnowledgeRepositoryの実装
 type KnowledgeRepositoryImpl struct {
 	db *sql.DB
@@ -20,16 +42,44 @@ func NewKnowledgeRepository(db *sql.DB) domain.KnowledgeRepository {
 
 // Create は新規ナレッジアイテムを作成する
 func (r *KnowledgeRepositoryImpl) Create(item *domain.KnowledgeItem) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		return insertKnowledgeItem(tx, item)
+	})
+}
+
+// CreateBatch は複数のナレッジアイテムを1トランザクションで作成する
+// bestEffortがfalseの場合は1件でも失敗すると全件ロールバックし、
+// trueの場合は失敗した項目のみを取り消して残りをコミットする。
+// 戻り値の[]errorは項目ごとのエラー（itemsと同じ並び）
+func (r *KnowledgeRepositoryImpl) CreateBatch(items []*domain.KnowledgeItem, bestEffort bool) ([]error, error) {
+	return runBatch(r.db, len(items), bestEffort, func(tx *sql.Tx, i int) error {
+		return insertKnowledgeItem(tx, items[i])
+	})
+}
+
+// insertKnowledgeItem はナレッジアイテムを1件登録し、初版の履歴を記録する
+func insertKnowledgeItem(q querier, item *domain.KnowledgeItem) error {
 	query := `
 		INSERT INTO knowledge_items (
 			project_id, file_id, sheet_name, source_range, question, answer,
-			department_id, question_group, status, version, created_by, created_at, updated_at
+			department_id, question_group, status, rejection_reason, answer_source_id, version,
+			created_by, updated_by, created_at, updated_at, normalized_question, normalized_answer,
+			canonical_question_id, answer_drifted, review_due_at, valid_until,
+			assignee, assignment_due_at, task_state, review_interval_days
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		RETURNING id, created_at, updated_at
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, ` +
+		fmt.Sprintf(answerDriftedExpr, 18, 19) + `, $20, $21, $22, $23, $24, $25)
+		RETURNING id, created_at, updated_at, answer_drifted
 	`
 
-	err := r.db.QueryRow(
+	if item.UpdatedBy == "" {
+		item.UpdatedBy = item.CreatedBy
+	}
+	if item.TaskState == "" {
+		item.TaskState = domain.TaskTodo
+	}
+
+	err := q.QueryRow(
 		query,
 		item.ProjectID,
 		item.FileID,
@@ -40,26 +90,41 @@ func (r *KnowledgeRepositoryImpl) Create(item *domain.KnowledgeItem) error {
 		item.DepartmentID,
 		item.QuestionGroup,
 		item.Status,
+		item.RejectionReason,
+		item.AnswerSourceID,
 		item.Version,
 		item.CreatedBy,
+		item.UpdatedBy,
 		time.Now(),
 		time.Now(),
-	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+		domain.NormalizeForSearch(item.Question),
+		domain.NormalizeForSearch(item.Answer),
+		item.CanonicalQuestionID,
+		item.ReviewDueAt,
+		item.ValidUntil,
+		item.Assignee,
+		item.AssignmentDueAt,
+		item.TaskState,
+		item.ReviewIntervalDays,
+	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt, &item.AnswerDrifted)
+	if err != nil {
+		return err
+	}
 
-	return err
-}
+	for _, answer := range item.Answers {
+		answer.KnowledgeItemID = item.ID
+		if err := insertKnowledgeAnswer(q, answer); err != nil {
+			return err
+		}
+	}
 
-// GetByID は指定されたIDのナレッジアイテムを取得する
-func (r *KnowledgeRepositoryImpl) GetByID(id int) (*domain.KnowledgeItem, error) {
-	query := `
-		SELECT id, project_id, file_id, sheet_name, source_range, question, answer,
-		       department_id, question_group, status, version, created_by, created_at, updated_at
-		FROM knowledge_items
-		WHERE id = $1
-	`
+	return insertKnowledgeRevision(q, item)
+}
 
+// scanKnowledgeItem はknowledgeColumnsの並びで1行を読み取る
+func scanKnowledgeItem(s rowScanner) (*domain.KnowledgeItem, error) {
 	item := &domain.KnowledgeItem{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := s.Scan(
 		&item.ID,
 		&item.ProjectID,
 		&item.FileID,
@@ -70,30 +135,31 @@ func (r *KnowledgeRepositoryImpl) GetByID(id int) (*domain.KnowledgeItem, error)
 		&item.DepartmentID,
 		&item.QuestionGroup,
 		&item.Status,
+		&item.RejectionReason,
+		&item.AnswerSourceID,
+		&item.CanonicalQuestionID,
+		&item.AnswerDrifted,
+		&item.ReviewDueAt,
+		&item.ValidUntil,
+		&item.ReviewIntervalDays,
+		&item.Assignee,
+		&item.AssignmentDueAt,
+		&item.TaskState,
 		&item.Version,
 		&item.CreatedBy,
+		&item.UpdatedBy,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
-
 	return item, nil
 }
 
-// GetByProjectID は指定された案件のすべてのナレッジアイテムを取得する
-func (r *KnowledgeRepositoryImpl) GetByProjectID(projectID int) ([]*domain.KnowledgeItem, error) {
-	query := `
-		SELECT id, project_id, file_id, sheet_name, source_range, question, answer,
-		       department_id, question_group, status, version, created_by, created_at, updated_at
-		FROM knowledge_items
-		WHERE project_id = $1
-		ORDER BY created_at DESC
-	`
-
-	rows, err := r.db.Query(query, projectID)
+// queryKnowledgeItems はクエリを実行してナレッジアイテムの一覧を読み取る
+func queryKnowledgeItems(q querier, query string, args ...interface{}) ([]*domain.KnowledgeItem, error) {
+	rows, err := q.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -101,43 +167,71 @@ func (r *KnowledgeRepositoryImpl) GetByProjectID(projectID int) ([]*domain.Knowl
 
 	items := []*domain.KnowledgeItem{}
 	for rows.Next() {
-		item := &domain.KnowledgeItem{}
-		err := rows.Scan(
-			&item.ID,
-			&item.ProjectID,
-			&item.FileID,
-			&item.SheetName,
-			&item.SourceRange,
-			&item.Question,
-			&item.Answer,
-			&item.DepartmentID,
-			&item.QuestionGroup,
-			&item.Status,
-			&item.Version,
-			&item.CreatedBy,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		)
+		item, err := scanKnowledgeItem(rows)
 		if err != nil {
 			return nil, err
 		}
 		items = append(items, item)
 	}
 
-	return items, nil
+	return items, rows.Err()
+}
+
+// GetByID は指定されたIDのナレッジアイテムを取得する（ゴミ箱のアイテムは含めない）
+func (r *KnowledgeRepositoryImpl) GetByID(id int) (*domain.KnowledgeItem, error) {
+	query := `SELECT ` + knowledgeColumns + `
+		FROM knowledge_items
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	return scanKnowledgeItem(r.db.QueryRow(query, id))
+}
+
+// GetByProjectID は指定された案件のナレッジアイテムと総件数を取得する
+func (r *KnowledgeRepositoryImpl) GetByProjectID(projectID int, page domain.PageRequest) ([]*domain.KnowledgeItem, int, error) {
+	total, err := countRows(r.db, `SELECT COUNT(*) FROM knowledge_items WHERE project_id = $1 AND deleted_at IS NULL`, projectID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	clause, pageArgs := pageClause(page, domain.KnowledgeSortFields, 2)
+	query := `SELECT ` + knowledgeColumns + `
+		FROM knowledge_items
+		WHERE project_id = $1 AND deleted_at IS NULL
+	` + clause
+
+	items, err := queryKnowledgeItems(r.db, query, append([]interface{}{projectID}, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// Update はナレッジアイテムを更新し、更新後の内容を履歴に記録する
+// DB上の版がexpectedVersionと一致しない場合はdomain.ErrVersionConflictを返す
+func (r *KnowledgeRepositoryImpl) Update(item *domain.KnowledgeItem, expectedVersion int) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		return updateKnowledgeItem(tx, item, expectedVersion)
+	})
 }
 
-// Update はナレッジアイテムを更新する
-func (r *KnowledgeRepositoryImpl) Update(item *domain.KnowledgeItem) error {
+// updateKnowledgeItem はナレッジアイテムを1件更新し、履歴を記録する
+// 担当の割り当てはKnowledgeAssignmentRepositoryで更新するため変更しない
+func updateKnowledgeItem(q querier, item *domain.KnowledgeItem, expectedVersion int) error {
 	query := `
 		UPDATE knowledge_items
 		SET project_id = $1, file_id = $2, sheet_name = $3, source_range = $4,
 		    question = $5, answer = $6, department_id = $7, question_group = $8,
-		    status = $9, version = $10, updated_at = $11
-		WHERE id = $12
+		    status = $9, rejection_reason = $10, answer_source_id = $11, version = $12,
+		    updated_by = $13, updated_at = $14, normalized_question = $15, normalized_answer = $16,
+		    canonical_question_id = $17, answer_drifted = ` + fmt.Sprintf(answerDriftedExpr, 16, 17) + `,
+		    review_due_at = $20, valid_until = $21, review_interval_days = $22
+		WHERE id = $18 AND version = $19 AND deleted_at IS NULL
+		RETURNING updated_at, answer_drifted
 	`
 
-	_, err := r.db.Exec(
+	err := q.QueryRow(
 		query,
 		item.ProjectID,
 		item.FileID,
@@ -148,93 +242,207 @@ func (r *KnowledgeRepositoryImpl) Update(item *domain.KnowledgeItem) error {
 		item.DepartmentID,
 		item.QuestionGroup,
 		item.Status,
+		item.RejectionReason,
+		item.AnswerSourceID,
 		item.Version,
+		item.UpdatedBy,
 		time.Now(),
+		domain.NormalizeForSearch(item.Question),
+		domain.NormalizeForSearch(item.Answer),
+		item.CanonicalQuestionID,
 		item.ID,
-	)
+		expectedVersion,
+		item.ReviewDueAt,
+		item.ValidUntil,
+		item.ReviewIntervalDays,
+	).Scan(&item.UpdatedAt, &item.AnswerDrifted)
+	if errors.Is(err, sql.ErrNoRows) {
+		// 行が存在するのに更新できなかった場合は版の競合
+		var exists bool
+		if err := q.QueryRow(`SELECT EXISTS(SELECT 1 FROM knowledge_items WHERE id = $1 AND deleted_at IS NULL)`, item.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrVersionConflict
+		}
+		return sql.ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	return insertKnowledgeRevision(q, item)
 }
 
-// Delete はナレッジアイテムを削除する
+// Delete はナレッジアイテムをゴミ箱に移す
 func (r *KnowledgeRepositoryImpl) Delete(id int) error {
-	query := `DELETE FROM knowledge_items WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+	return softDeleteKnowledgeItem(r.db, id, time.Now())
 }
 
-// Search はナレッジアイテムを検索する
-func (r *KnowledgeRepositoryImpl) Search(query string, filters map[string]interface{}) ([]*domain.KnowledgeItem, error) {
-	// 基本クエリ
-	sql := `
-		SELECT id, project_id, file_id, sheet_name, source_range, question, answer,
-		       department_id, question_group, status, version, created_by, created_at, updated_at
-		FROM knowledge_items
-		WHERE 1=1
-	`
-
-	args := []interface{}{}
-	argIndex := 1
-
-	// クエリ文字列による検索（質問または回答に含まれる）
-	if query != "" {
-		sql += fmt.Sprintf(" AND (question ILIKE $%d OR answer ILIKE $%d)", argIndex, argIndex+1)
-		searchTerm := "%" + query + "%"
-		args = append(args, searchTerm, searchTerm)
-		argIndex += 2
+// softDeleteKnowledgeItem はナレッジアイテムに削除日時を設定する（ゴミ箱にある場合はsql.ErrNoRows）
+func softDeleteKnowledgeItem(q querier, id int, now time.Time) error {
+	result, err := q.Exec(`UPDATE knowledge_items SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, now, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
 	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-	// フィルタ条件の追加
-	if projectID, ok := filters["project_id"].(int); ok && projectID > 0 {
-		sql += fmt.Sprintf(" AND project_id = $%d", argIndex)
-		args = append(args, projectID)
-		argIndex++
+// softDeleteKnowledgeItemAtVersion は版が一致する場合のみナレッジアイテムに削除日時を設定する
+// 既にゴミ箱にある場合や版が変わっている場合はErrVersionConflictを返す
+func softDeleteKnowledgeItemAtVersion(q querier, id int, version int, now time.Time) error {
+	result, err := q.Exec(
+		`UPDATE knowledge_items SET deleted_at = $1 WHERE id = $2 AND version = $3 AND deleted_at IS NULL`,
+		now,
+		id,
+		version,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
 	}
+	if affected == 0 {
+		return domain.ErrVersionConflict
+	}
+	return nil
+}
 
-	if departmentID, ok := filters["department_id"].(int); ok && departmentID > 0 {
-		sql += fmt.Sprintf(" AND department_id = $%d", argIndex)
-		args = append(args, departmentID)
-		argIndex++
+// UpdateBatch は複数のアイテムの更新とステータス遷移の記録を1トランザクションで行う
+func (r *KnowledgeRepositoryImpl) UpdateBatch(updates []*domain.KnowledgeBatchUpdate, bestEffort bool) ([]error, error) {
+	return runBatch(r.db, len(updates), bestEffort, func(tx *sql.Tx, i int) error {
+		u := updates[i]
+		if err := updateKnowledgeItem(tx, u.Item, u.ExpectedVersion); err != nil {
+			return err
+		}
+		if u.Transition == nil {
+			return nil
+		}
+		return insertStatusTransition(tx, u.Transition)
+	})
+}
+
+// DeleteBatch は複数のアイテムを1トランザクションでゴミ箱に移す
+func (r *KnowledgeRepositoryImpl) DeleteBatch(ids []int, bestEffort bool) ([]error, error) {
+	now := time.Now()
+	return runBatch(r.db, len(ids), bestEffort, func(tx *sql.Tx, i int) error {
+		return softDeleteKnowledgeItem(tx, ids[i], now)
+	})
+}
+
+// Search はナレッジアイテムを検索し、条件に一致する総件数とともに返す
+func (r *KnowledgeRepositoryImpl) Search(query string, filter domain.KnowledgeSearchFilter, page domain.PageRequest) ([]*domain.KnowledgeItem, int, error) {
+	where, args := knowledgeSearchWhere(query, filter, "")
+
+	total, err := countRows(r.db, `SELECT COUNT(*) FROM knowledge_items`+where, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if status, ok := filters["status"].(string); ok && status != "" {
-		sql += fmt.Sprintf(" AND status = $%d", argIndex)
-		args = append(args, status)
-		argIndex++
+	clause, pageArgs := pageClause(page, domain.KnowledgeSortFields, len(args)+1)
+	items, err := queryKnowledgeItems(r.db, `SELECT `+knowledgeColumns+` FROM knowledge_items`+where+clause, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	sql += " ORDER BY created_at DESC"
+	return items, total, nil
+}
 
-	rows, err := r.db.Query(sql, args...)
+// scoredScanner はナレッジアイテムのカラムに続くスコア列を読み取るためのrowScanner
+type scoredScanner struct {
+	rowScanner
+	score *float64
+}
+
+func (s scoredScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.score)...)
+}
+
+// FindSimilar は正規化した質問の類似度（similarityとword_similarityの大きい方）が閾値以上のアイテムを取得する
+func (r *KnowledgeRepositoryImpl) FindSimilar(text string, opts domain.SimilarSearchOptions) ([]*domain.ScoredKnowledgeItem, error) {
+	results, err := r.FindSimilarBatch([]string{text}, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return results[0], nil
+}
 
-	items := []*domain.KnowledgeItem{}
-	for rows.Next() {
-		item := &domain.KnowledgeItem{}
-		err := rows.Scan(
-			&item.ID,
-			&item.ProjectID,
-			&item.FileID,
-			&item.SheetName,
-			&item.SourceRange,
-			&item.Question,
-			&item.Answer,
-			&item.DepartmentID,
-			&item.QuestionGroup,
-			&item.Status,
-			&item.Version,
-			&item.CreatedBy,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		)
+// FindSimilarBatch はテキストごとに類似度が閾値以上のアイテムを最大opts.Limit件ずつ取得する
+// 候補は%演算子・<%演算子でトライグラムのインデックスから絞り込み、候補の中だけで順位を付ける
+func (r *KnowledgeRepositoryImpl) FindSimilarBatch(texts []string, opts domain.SimilarSearchOptions) ([][]*domain.ScoredKnowledgeItem, error) {
+	results := make([][]*domain.ScoredKnowledgeItem, len(texts))
+	for i := range results {
+		results[i] = []*domain.ScoredKnowledgeItem{}
+	}
+	if len(texts) == 0 {
+		return results, nil
+	}
+
+	normalized := make([]string, len(texts))
+	for i, text := range texts {
+		normalized[i] = domain.NormalizeForSearch(text)
+	}
+
+	query := `SELECT q.ord, ` + knowledgeColumns + `, score
+		FROM unnest($1::text[]) WITH ORDINALITY AS q(query_text, ord)
+		CROSS JOIN LATERAL (
+			SELECT *
+			FROM (
+				SELECT *, GREATEST(similarity(normalized_question, q.query_text), word_similarity(q.query_text, normalized_question)) AS score
+				FROM knowledge_items
+				WHERE (normalized_question % q.query_text OR q.query_text <% normalized_question)
+					AND deleted_at IS NULL
+					AND ($3 = 0 OR project_id <> $3)
+					AND ($4 = '' OR status = $4)
+			) scored
+			WHERE score >= $2
+			ORDER BY COALESCE(valid_until <= $6::timestamp, false) ASC, score DESC, updated_at DESC
+			LIMIT $5
+		) similar
+		ORDER BY q.ord ASC, COALESCE(valid_until <= $6::timestamp, false) ASC, score DESC, updated_at DESC
+	`
+
+	err := withSimilarityThreshold(r.db, opts.Threshold, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, pq.Array(normalized), opts.Threshold, opts.ExcludeProjectID, opts.Status, opts.Limit, opts.PreferValidAt)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		items = append(items, item)
+		defer rows.Close()
+
+		for rows.Next() {
+			var ord int
+			scored := &domain.ScoredKnowledgeItem{}
+			item, err := scanKnowledgeItem(batchScoredScanner{scoredScanner: scoredScanner{rowScanner: rows, score: &scored.Score}, ord: &ord})
+			if err != nil {
+				return err
+			}
+			scored.KnowledgeItem = item
+			results[ord-1] = append(results[ord-1], scored)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return items, nil
+	return results, nil
+}
+
+// batchScoredScanner は先頭のテキストの番号（1始まり）とスコア付きのアイテムを読み取るためのrowScanner
+type batchScoredScanner struct {
+	scoredScanner
+	ord *int
+}
+
+func (s batchScoredScanner) Scan(dest ...interface{}) error {
+	return s.scoredScanner.Scan(append([]interface{}{s.ord}, dest...)...)
 }
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// KnowledgeReviewRepositoryImpl はKnowledgeReviewRepositoryの実装
+type KnowledgeReviewRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeReviewRepository は新しいKnowledgeReviewRepositoryを生成する
+func NewKnowledgeReviewRepository(db *sql.DB) domain.KnowledgeReviewRepository {
+	return &KnowledgeReviewRepositoryImpl{db: db}
+}
+
+// GetExpiring はapproved・publishedのうち、レビュー期限または有効期限がnow以前のアイテムを取得する
+func (r *KnowledgeReviewRepositoryImpl) GetExpiring(now time.Time) ([]*domain.KnowledgeItem, error) {
+	query := `SELECT ` + knowledgeColumns + `
+		FROM knowledge_items
+		WHERE status IN ($1, $2) AND deleted_at IS NULL
+			AND (review_due_at <= $3 OR valid_until <= $3)
+		ORDER BY id ASC
+	`
+
+	return queryKnowledgeItems(r.db, query, domain.StatusApproved, domain.StatusPublished, now)
+}
+
+// GetDueForReview はneeds_reviewのアイテムと、期限がbefore以前のアイテムを期限の早い順に取得する
+func (r *KnowledgeReviewRepositoryImpl) GetDueForReview(before time.Time, departmentID *int) ([]*domain.KnowledgeItem, error) {
+	query := `SELECT ` + knowledgeColumns + `
+		FROM knowledge_items
+		WHERE (status = $1 OR (status <> $2 AND (review_due_at <= $3 OR valid_until <= $3)))
+			AND ($4::int IS NULL OR department_id = $4)
+			AND deleted_at IS NULL
+		ORDER BY LEAST(review_due_at, valid_until) ASC NULLS LAST, id ASC
+	`
+
+	return queryKnowledgeItems(r.db, query, domain.StatusNeedsReview, domain.StatusArchived, before, departmentID)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// KnowledgeRevisionRepositoryImpl はKnowledgeRevisionRepositoryの実装
+type KnowledgeRevisionRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeRevisionRepository は新しいKnowledgeRevisionRepositoryを生成する
+func NewKnowledgeRevisionRepository(db *sql.DB) domain.KnowledgeRevisionRepository {
+	return &KnowledgeRevisionRepositoryImpl{db: db}
+}
+
+// insertKnowledgeRevision はナレッジアイテムの現在の内容を新しい履歴として記録する
+func insertKnowledgeRevision(q querier, item *domain.KnowledgeItem) error {
+	query := `
+		INSERT INTO knowledge_item_revisions (
+			knowledge_item_id, revision, version, question, answer, department_id, status, edited_by
+		)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6, $7
+		FROM knowledge_item_revisions
+		WHERE knowledge_item_id = $1
+	`
+
+	_, err := q.Exec(
+		query,
+		item.ID,
+		item.Version,
+		item.Question,
+		item.Answer,
+		item.DepartmentID,
+		item.Status,
+		item.UpdatedBy,
+	)
+
+	return err
+}
+
+// GetByKnowledgeID は指定されたナレッジアイテムの履歴を新しい順に取得する
+func (r *KnowledgeRevisionRepositoryImpl) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeRevision, error) {
+	query := `
+		SELECT id, knowledge_item_id, revision, version, question, answer, department_id, status, edited_by, created_at
+		FROM knowledge_item_revisions
+		WHERE knowledge_item_id = $1
+		ORDER BY revision DESC
+	`
+
+	rows, err := r.db.Query(query, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []*domain.KnowledgeRevision{}
+	for rows.Next() {
+		rev := &domain.KnowledgeRevision{}
+		err := rows.Scan(
+			&rev.ID,
+			&rev.KnowledgeItemID,
+			&rev.Revision,
+			&rev.Version,
+			&rev.Question,
+			&rev.Answer,
+			&rev.DepartmentID,
+			&rev.Status,
+			&rev.EditedBy,
+			&rev.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, nil
+}
+
+// GetByRevision は指定された履歴番号の履歴を取得する
+func (r *KnowledgeRevisionRepositoryImpl) GetByRevision(knowledgeID int, revision int) (*domain.KnowledgeRevision, error) {
+	query := `
+		SELECT id, knowledge_item_id, revision, version, question, answer, department_id, status, edited_by, created_at
+		FROM knowledge_item_revisions
+		WHERE knowledge_item_id = $1 AND revision = $2
+	`
+
+	rev := &domain.KnowledgeRevision{}
+	err := r.db.QueryRow(query, knowledgeID, revision).Scan(
+		&rev.ID,
+		&rev.KnowledgeItemID,
+		&rev.Revision,
+		&rev.Version,
+		&rev.Question,
+		&rev.Answer,
+		&rev.DepartmentID,
+		&rev.Status,
+		&rev.EditedBy,
+		&rev.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
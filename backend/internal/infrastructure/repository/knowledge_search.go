@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// knowledgeFacetQueries はファセットごとの集計クエリ
+// %s にはknowledge_itemsに対する絞り込み条件（WHERE句）が入り、値・表示名・件数の順に返す
+var knowledgeFacetQueries = []struct {
+	facet string
+	query string
+}{
+	{domain.FacetDepartment, `
+		SELECT COALESCE(f.value::text, ''), COALESCE(d.name, ''), f.count
+		FROM (SELECT department_id AS value, COUNT(*) AS count FROM knowledge_items%s GROUP BY department_id) f
+		LEFT JOIN departments d ON d.id = f.value
+		ORDER BY f.count DESC, d.display_order ASC NULLS LAST`},
+	{domain.FacetStatus, `
+		SELECT COALESCE(status, ''), '', COUNT(*) AS count
+		FROM knowledge_items%s
+		GROUP BY status
+		ORDER BY count DESC, 1 ASC`},
+	{domain.FacetQuestionGroup, `
+		SELECT COALESCE(question_group, ''), '', COUNT(*) AS count
+		FROM knowledge_items%s
+		GROUP BY COALESCE(question_group, '')
+		ORDER BY count DESC, 1 ASC`},
+	{domain.FacetProject, `
+		SELECT f.value::text, COALESCE(p.customer_name, ''), f.count
+		FROM (SELECT project_id AS value, COUNT(*) AS count FROM knowledge_items%s GROUP BY project_id) f
+		LEFT JOIN projects p ON p.id = f.value
+		ORDER BY f.count DESC, f.value ASC`},
+	{domain.FacetYear, `
+		SELECT EXTRACT(YEAR FROM created_at)::int::text AS value, '', COUNT(*) AS count
+		FROM knowledge_items%s
+		GROUP BY value
+		ORDER BY value DESC`},
+}
+
+// knowledgeSearchWhere は検索クエリと絞り込み条件からWHERE句と引数を組み立てる
+// excludeに指定したファセットの絞り込みは含めない（ファセット集計用）
+func knowledgeSearchWhere(query string, filter domain.KnowledgeSearchFilter, exclude string) (string, []interface{}) {
+	// ゴミ箱のアイテムは検索・集計の対象外
+	where := ` WHERE deleted_at IS NULL`
+	args := []interface{}{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		where += fmt.Sprintf(" AND "+condition, len(args))
+	}
+
+	// クエリ文字列による検索（正規化した質問または回答に含まれる）
+	if query = domain.NormalizeForSearch(query); query != "" {
+		add("(normalized_question ILIKE $%[1]d OR normalized_answer ILIKE $%[1]d)", "%"+escapeLike(query)+"%")
+	}
+
+	if len(filter.ProjectIDs) > 0 && exclude != domain.FacetProject {
+		add("project_id = ANY($%d)", pq.Array(int64s(filter.ProjectIDs)))
+	}
+	if len(filter.DepartmentIDs) > 0 && exclude != domain.FacetDepartment {
+		add("department_id = ANY($%d)", pq.Array(int64s(filter.DepartmentIDs)))
+	}
+	if len(filter.Statuses) > 0 && exclude != domain.FacetStatus {
+		add("status = ANY($%d)", pq.Array(filter.Statuses))
+	}
+	if len(filter.QuestionGroups) > 0 && exclude != domain.FacetQuestionGroup {
+		add("COALESCE(question_group, '') = ANY($%d)", pq.Array(filter.QuestionGroups))
+	}
+	if len(filter.CreatedBy) > 0 {
+		add("created_by = ANY($%d)", pq.Array(filter.CreatedBy))
+	}
+	if len(filter.Assignees) > 0 {
+		add("assignee = ANY($%d)", pq.Array(filter.Assignees))
+	}
+	if len(filter.TaskStates) > 0 {
+		add("task_state = ANY($%d)", pq.Array(filter.TaskStates))
+	}
+	if len(filter.ControlIDs) > 0 {
+		add("id IN (SELECT knowledge_item_id FROM knowledge_effective_controls WHERE control_id = ANY($%d))", pq.Array(int64s(filter.ControlIDs)))
+	}
+	if len(filter.TagIDs) > 0 {
+		add(`id IN (
+			WITH RECURSIVE descendants AS (
+				SELECT id FROM tags WHERE id = ANY($%d)
+				UNION
+				SELECT t.id FROM tags t JOIN descendants d ON t.parent_id = d.id
+			)
+			SELECT knowledge_item_id FROM knowledge_item_tags WHERE tag_id IN (SELECT id FROM descendants))`, pq.Array(int64s(filter.TagIDs)))
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		add("updated_at >= $%d", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		add("updated_at < $%d", *filter.UpdatedTo)
+	}
+	if filter.UnansweredOnly {
+		where += " AND (answer IS NULL OR btrim(answer) = '')"
+	}
+
+	return where, args
+}
+
+// SearchFacets は検索条件に一致するナレッジのファセットごとの件数を集計する
+func (r *KnowledgeRepositoryImpl) SearchFacets(query string, filter domain.KnowledgeSearchFilter) (*domain.KnowledgeFacets, error) {
+	facets := &domain.KnowledgeFacets{}
+
+	for _, fq := range knowledgeFacetQueries {
+		where, args := knowledgeSearchWhere(query, filter, fq.facet)
+		counts, err := r.queryFacetCounts(fmt.Sprintf(fq.query, where), args...)
+		if err != nil {
+			return nil, fmt.Errorf("%sの集計に失敗しました: %w", fq.facet, err)
+		}
+
+		switch fq.facet {
+		case domain.FacetDepartment:
+			facets.Department = counts
+		case domain.FacetStatus:
+			facets.Status = counts
+		case domain.FacetQuestionGroup:
+			facets.QuestionGroup = counts
+		case domain.FacetProject:
+			facets.Project = counts
+		case domain.FacetYear:
+			facets.Year = counts
+		}
+	}
+
+	return facets, nil
+}
+
+// queryFacetCounts は値・表示名・件数の並びの集計クエリを実行する
+func (r *KnowledgeRepositoryImpl) queryFacetCounts(query string, args ...interface{}) ([]domain.FacetCount, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []domain.FacetCount{}
+	for rows.Next() {
+		var count domain.FacetCount
+		if err := rows.Scan(&count.Value, &count.Label, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// int64s はpq.Arrayに渡すためにintのスライスを変換する
+func int64s(values []int) []int64 {
+	result := make([]int64, len(values))
+	for i, v := range values {
+		result[i] = int64(v)
+	}
+	return result
+}
+
+// likeEscaper はLIKEのパターンで特別な意味を持つ文字をエスケープする
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike は検索語をLIKEのパターンとして文字どおりに一致させるためエスケープする
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// KnowledgeWorkflowRepositoryImpl はKnowledgeWorkflowRepositoryの実装
+type KnowledgeWorkflowRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewKnowledgeWorkflowRepository は新しいKnowledgeWorkflowRepositoryを生成する
+func NewKnowledgeWorkflowRepository(db *sql.DB) domain.KnowledgeWorkflowRepository {
+	return &KnowledgeWorkflowRepositoryImpl{db: db}
+}
+
+// Transition はナレッジアイテムを更新し、ステータス遷移を記録する
+func (r *KnowledgeWorkflowRepositoryImpl) Transition(item *domain.KnowledgeItem, expectedVersion int, transition *domain.StatusTransition) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if err := updateKnowledgeItem(tx, item, expectedVersion); err != nil {
+			return err
+		}
+		return insertStatusTransition(tx, transition)
+	})
+}
+
+// insertStatusTransition はステータス遷移を1件記録する
+func insertStatusTransition(q querier, transition *domain.StatusTransition) error {
+	query := `
+		INSERT INTO knowledge_status_transitions (knowledge_item_id, from_status, to_status, actor, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	return q.QueryRow(
+		query,
+		transition.KnowledgeItemID,
+		transition.FromStatus,
+		transition.ToStatus,
+		transition.Actor,
+		transition.Reason,
+		transition.CreatedAt,
+	).Scan(&transition.ID)
+}
+
+// GetByKnowledgeID は指定されたナレッジアイテムのステータス遷移を古い順に取得する
+func (r *KnowledgeWorkflowRepositoryImpl) GetByKnowledgeID(knowledgeID int) ([]*domain.StatusTransition, error) {
+	query := `
+		SELECT id, knowledge_item_id, from_status, to_status, actor, reason, created_at
+		FROM knowledge_status_transitions
+		WHERE knowledge_item_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []*domain.StatusTransition{}
+	for rows.Next() {
+		t := &domain.StatusTransition{}
+		err := rows.Scan(
+			&t.ID,
+			&t.KnowledgeItemID,
+			&t.FromStatus,
+			&t.ToStatus,
+			&t.Actor,
+			&t.Reason,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// pageClause はORDER BY・LIMIT・OFFSET句と引数を組み立てる
+// 並び替え項目は許可リストにあるもののみをSQLに埋め込み、それ以外は先頭の項目を使う。
+// 同じ値の行の順序を安定させるため最後にidで並べる。Limitが0の場合は全件を返す
+func pageClause(page domain.PageRequest, sortFields []string, argIndex int) (string, []interface{}) {
+	sort := page.Sort
+	if !domain.IsAllowedSortField(sort, sortFields) {
+		sort = sortFields[0]
+	}
+	order := "DESC"
+	if page.Order == domain.SortAsc {
+		order = "ASC"
+	}
+
+	clause := fmt.Sprintf(" ORDER BY %s %s", sort, order)
+	if sort != "id" {
+		clause += fmt.Sprintf(", id %s", order)
+	}
+
+	if page.Limit <= 0 {
+		return clause, nil
+	}
+
+	clause += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	return clause, []interface{}{page.Limit, page.Offset}
+}
+
+// countRows は件数を取得するクエリを実行する
+func countRows(q querier, query string, args ...interface{}) (int, error) {
+	var total int
+	if err := q.QueryRow(query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
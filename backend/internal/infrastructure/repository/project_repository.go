@@ -2,6 +2,9 @@ package repository
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/security-checksheets/backend/internal/domain"
@@ -20,8 +23,8 @@ func NewProjectRepository(db *sql.DB) domain.ProjectRepository {
 // Create は新規案件を作成する
 func (r *ProjectRepositoryImpl) Create(project *domain.Project) error {
 	query := `
-		INSERT INTO projects (customer_name, description, owner, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO projects (customer_name, description, owner, status, variables, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -31,6 +34,7 @@ func (r *ProjectRepositoryImpl) Create(project *domain.Project) error {
 		project.Description,
 		project.Owner,
 		project.Status,
+		projectVariables(project.Variables),
 		time.Now(),
 		time.Now(),
 	).Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt)
@@ -38,12 +42,12 @@ func (r *ProjectRepositoryImpl) Create(project *domain.Project) error {
 	return err
 }
 
-// GetByID は指定されたIDの案件を取得する
+// GetByID は指定されたIDの案件を取得する（ゴミ箱の案件は含めない）
 func (r *ProjectRepositoryImpl) GetByID(id int) (*domain.Project, error) {
 	query := `
-		SELECT id, customer_name, description, owner, status, created_at, updated_at
+		SELECT id, customer_name, description, owner, status, variables, created_at, updated_at
 		FROM projects
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	project := &domain.Project{}
@@ -53,6 +57,7 @@ func (r *ProjectRepositoryImpl) GetByID(id int) (*domain.Project, error) {
 		&project.Description,
 		&project.Owner,
 		&project.Status,
+		(*projectVariables)(&project.Variables),
 		&project.CreatedAt,
 		&project.UpdatedAt,
 	)
@@ -64,17 +69,23 @@ func (r *ProjectRepositoryImpl) GetByID(id int) (*domain.Project, error) {
 	return project, nil
 }
 
-// GetAll はすべての案件を取得する
-func (r *ProjectRepositoryImpl) GetAll() ([]*domain.Project, error) {
+// GetAll は案件の一覧と総件数を取得する
+func (r *ProjectRepositoryImpl) GetAll(page domain.PageRequest) ([]*domain.Project, int, error) {
+	total, err := countRows(r.db, `SELECT COUNT(*) FROM projects WHERE deleted_at IS NULL`)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	clause, args := pageClause(page, domain.ProjectSortFields, 1)
 	query := `
-		SELECT id, customer_name, description, owner, status, created_at, updated_at
+		SELECT id, customer_name, description, owner, status, variables, created_at, updated_at
 		FROM projects
-		ORDER BY created_at DESC
-	`
+		WHERE deleted_at IS NULL
+	` + clause
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -87,16 +98,17 @@ func (r *ProjectRepositoryImpl) GetAll() ([]*domain.Project, error) {
 			&project.Description,
 			&project.Owner,
 			&project.Status,
+			(*projectVariables)(&project.Variables),
 			&project.CreatedAt,
 			&project.UpdatedAt,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		projects = append(projects, project)
 	}
 
-	return projects, nil
+	return projects, total, nil
 }
 
 // Update は案件情報を更新する
@@ -104,7 +116,7 @@ func (r *ProjectRepositoryImpl) Update(project *domain.Project) error {
 	query := `
 		UPDATE projects
 		SET customer_name = $1, description = $2, owner = $3, status = $4, updated_at = $5
-		WHERE id = $6
+		WHERE id = $6 AND deleted_at IS NULL
 	`
 
 	_, err := r.db.Exec(
@@ -120,9 +132,78 @@ func (r *ProjectRepositoryImpl) Update(project *domain.Project) error {
 	return err
 }
 
-// Delete は案件を削除する
+// UpdateVariables は案件変数を置き換える
+func (r *ProjectRepositoryImpl) UpdateVariables(id int, variables map[string]string) error {
+	result, err := r.db.Exec(
+		`UPDATE projects SET variables = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`,
+		projectVariables(variables),
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Delete は案件をゴミ箱に移す
+// 案件のファイルとナレッジアイテムにも同じ削除日時を設定し、案件の復元でまとめて戻せるようにする
 func (r *ProjectRepositoryImpl) Delete(id int) error {
-	query := `DELETE FROM projects WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+	now := time.Now()
+	return withTx(r.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE projects SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, now, id)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if _, err := tx.Exec(`UPDATE uploaded_files SET deleted_at = $1 WHERE project_id = $2 AND deleted_at IS NULL`, now, id); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE knowledge_items SET deleted_at = $1 WHERE project_id = $2 AND deleted_at IS NULL`, now, id)
+		return err
+	})
+}
+
+// projectVariables は案件変数をJSONBとして読み書きするための型
+type projectVariables map[string]string
+
+// Value は案件変数をJSONに変換する
+func (v projectVariables) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(v))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan はJSONBの案件変数を読み取る
+func (v *projectVariables) Scan(src interface{}) error {
+	*v = projectVariables{}
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return fmt.Errorf("案件変数の型が不正です: %T", src)
+	}
 }
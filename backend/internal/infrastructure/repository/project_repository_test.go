@@ -82,9 +82,17 @@ func TestProjectRepository_GetAll(t *testing.T) {
 	require.NoError(t, repo.Create(project2))
 
 	// 全件取得
-	projects, err := repo.GetAll()
+	projects, total, err := repo.GetAll(domain.PageRequest{})
 	assert.NoError(t, err)
 	assert.Len(t, projects, 2)
+	assert.Equal(t, 2, total)
+
+	// ページング
+	projects, total, err = repo.GetAll(domain.PageRequest{Limit: 1, Offset: 1, Sort: "customer_name", Order: "asc"})
+	assert.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "テスト株式会社2", projects[0].CustomerName)
+	assert.Equal(t, 2, total)
 }
 
 func TestProjectRepository_Update(t *testing.T) {
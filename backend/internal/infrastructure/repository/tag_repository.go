@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// tagColumns はタグと直接紐づくアイテム数（ゴミ箱のアイテムを除く）を取得するカラム（scanTagと同じ並び）
+const tagColumns = `
+	t.id, t.name, t.parent_id, t.description,
+	(SELECT COUNT(*) FROM knowledge_item_tags kt
+		JOIN knowledge_items k ON k.id = kt.knowledge_item_id
+		WHERE kt.tag_id = t.id AND k.deleted_at IS NULL),
+	t.created_at, t.updated_at`
+
+// TagRepositoryImpl はTagRepositoryの実装
+type TagRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewTagRepository は新しいTagRepositoryを生成する
+func NewTagRepository(db *sql.DB) domain.TagRepository {
+	return &TagRepositoryImpl{db: db}
+}
+
+// Create はタグを作成する
+func (r *TagRepositoryImpl) Create(tag *domain.Tag) error {
+	return insertTag(r.db, tag)
+}
+
+// insertTag はタグを作成し、採番したIDを設定する
+func insertTag(q querier, tag *domain.Tag) error {
+	return q.QueryRow(
+		`INSERT INTO tags (name, parent_id, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		tag.Name,
+		tag.ParentID,
+		tag.Description,
+		tag.CreatedAt,
+		tag.UpdatedAt,
+	).Scan(&tag.ID)
+}
+
+// GetByID は指定されたIDのタグを取得する
+func (r *TagRepositoryImpl) GetByID(id int) (*domain.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags t WHERE t.id = $1`
+	return scanTag(r.db.QueryRow(query, id))
+}
+
+// GetAll はすべてのタグを名前順に取得する
+func (r *TagRepositoryImpl) GetAll() ([]*domain.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags t ORDER BY t.name ASC, t.id ASC`
+	return queryTags(r.db, query)
+}
+
+// Update はタグの名前・親・説明を更新する
+func (r *TagRepositoryImpl) Update(tag *domain.Tag) error {
+	result, err := r.db.Exec(
+		`UPDATE tags SET name = $1, parent_id = $2, description = $3, updated_at = $4 WHERE id = $5`,
+		tag.Name,
+		tag.ParentID,
+		tag.Description,
+		tag.UpdatedAt,
+		tag.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Delete はタグを削除する。アイテムとの紐づけも削除される
+func (r *TagRepositoryImpl) Delete(id int) error {
+	_, err := r.db.Exec(`DELETE FROM tags WHERE id = $1`, id)
+	return err
+}
+
+// CountTags は指定されたIDのうち存在するタグの数を返す
+func (r *TagRepositoryImpl) CountTags(ids []int) (int, error) {
+	return countRows(r.db, `SELECT COUNT(*) FROM tags WHERE id = ANY($1)`, pq.Array(int64s(ids)))
+}
+
+// GetByKnowledgeID はアイテムに紐づくタグを取得する
+func (r *TagRepositoryImpl) GetByKnowledgeID(knowledgeID int) ([]*domain.Tag, error) {
+	query := `SELECT ` + tagColumns + `
+		FROM tags t
+		JOIN knowledge_item_tags kt ON kt.tag_id = t.id
+		WHERE kt.knowledge_item_id = $1
+		ORDER BY t.name ASC, t.id ASC
+	`
+	return queryTags(r.db, query, knowledgeID)
+}
+
+// ReplaceKnowledgeTags はアイテムに紐づけるタグを置き換える
+func (r *TagRepositoryImpl) ReplaceKnowledgeTags(knowledgeID int, tagIDs []int, actor string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM knowledge_item_tags WHERE knowledge_item_id = $1`, knowledgeID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(
+			`INSERT INTO knowledge_item_tags (knowledge_item_id, tag_id, created_by)
+			SELECT $1, unnest($2::int[]), $3`,
+			knowledgeID,
+			pq.Array(int64s(tagIDs)),
+			actor,
+		)
+		return err
+	})
+}
+
+// BulkTag は複数のアイテムにタグを一括で追加・削除する（ゴミ箱のアイテムは変更しない）
+func (r *TagRepositoryImpl) BulkTag(knowledgeIDs []int, addTagIDs []int, removeTagIDs []int, actor string) (int, int, error) {
+	var added, removed int64
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		if len(removeTagIDs) > 0 {
+			result, err := tx.Exec(
+				`DELETE FROM knowledge_item_tags kt
+				USING knowledge_items k
+				WHERE k.id = kt.knowledge_item_id AND k.deleted_at IS NULL
+					AND kt.knowledge_item_id = ANY($1) AND kt.tag_id = ANY($2)`,
+				pq.Array(int64s(knowledgeIDs)),
+				pq.Array(int64s(removeTagIDs)),
+			)
+			if err != nil {
+				return err
+			}
+			if removed, err = result.RowsAffected(); err != nil {
+				return err
+			}
+		}
+
+		if len(addTagIDs) > 0 {
+			result, err := tx.Exec(
+				`INSERT INTO knowledge_item_tags (knowledge_item_id, tag_id, created_by)
+				SELECT k.id, t.id, $3
+				FROM knowledge_items k CROSS JOIN tags t
+				WHERE k.id = ANY($1) AND k.deleted_at IS NULL AND t.id = ANY($2)
+				ON CONFLICT DO NOTHING`,
+				pq.Array(int64s(knowledgeIDs)),
+				pq.Array(int64s(addTagIDs)),
+				actor,
+			)
+			if err != nil {
+				return err
+			}
+			if added, err = result.RowsAffected(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(added), int(removed), nil
+}
+
+// GetQuestionGroups はナレッジアイテムに入力されている質問グループの値を重複なく取得する
+func (r *TagRepositoryImpl) GetQuestionGroups() ([]string, error) {
+	rows, err := r.db.Query(
+		`SELECT DISTINCT question_group FROM knowledge_items
+		WHERE deleted_at IS NULL AND question_group IS NOT NULL AND btrim(question_group) <> ''
+		ORDER BY question_group ASC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []string{}
+	for rows.Next() {
+		var group string
+		if err := rows.Scan(&group); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+// MigrateQuestionGroups は質問グループの値ごとに移行先のタグを（なければ作成して）解決し、該当するアイテムに紐づける
+// 途中で失敗した場合に一部だけ移行された状態が残らないよう、1トランザクションで実行する
+func (r *TagRepositoryImpl) MigrateQuestionGroups(paths []*domain.QuestionGroupTagPath, actor string) (*domain.QuestionGroupMigrationResult, error) {
+	result := &domain.QuestionGroupMigrationResult{Groups: []domain.QuestionGroupMigration{}}
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		tags, err := queryTags(tx, `SELECT `+tagColumns+` FROM tags t ORDER BY t.name ASC, t.id ASC`)
+		if err != nil {
+			return err
+		}
+		tree := domain.NewTagTree(tags)
+
+		for _, path := range paths {
+			var tag *domain.Tag
+			for _, name := range path.Names {
+				var parentID *int
+				if tag != nil {
+					parentID = &tag.ID
+				}
+				child := tree.FindChild(parentID, name)
+				if child == nil {
+					child = domain.NewTag(name, parentID, "")
+					if err := insertTag(tx, child); err != nil {
+						return err
+					}
+					tags = append(tags, child)
+					tree = domain.NewTagTree(tags)
+					result.TagsCreated++
+				}
+				tag = child
+			}
+
+			tagged, err := tagQuestionGroup(tx, tag.ID, path.QuestionGroup, actor)
+			if err != nil {
+				return err
+			}
+			result.ItemsTagged += tagged
+			result.Groups = append(result.Groups, domain.QuestionGroupMigration{
+				QuestionGroup: path.QuestionGroup,
+				TagID:         tag.ID,
+				Path:          tag.Path,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// tagQuestionGroup は質問グループが一致するアイテム（ゴミ箱のアイテムを除く）にタグを紐づけ、追加した紐づけの数を返す
+func tagQuestionGroup(q querier, tagID int, questionGroup string, actor string) (int, error) {
+	result, err := q.Exec(
+		`INSERT INTO knowledge_item_tags (knowledge_item_id, tag_id, created_by)
+		SELECT id, $1, $3 FROM knowledge_items WHERE deleted_at IS NULL AND question_group = $2
+		ON CONFLICT DO NOTHING`,
+		tagID,
+		questionGroup,
+		actor,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// queryTags はクエリを実行してタグの一覧を読み取る
+func queryTags(q querier, query string, args ...interface{}) ([]*domain.Tag, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*domain.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// scanTag はtagColumnsの並びで1行を読み取る
+func scanTag(s rowScanner) (*domain.Tag, error) {
+	tag := &domain.Tag{}
+	err := s.Scan(
+		&tag.ID,
+		&tag.Name,
+		&tag.ParentID,
+		&tag.Description,
+		&tag.ItemCount,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// TrashRepositoryImpl はTrashRepositoryの実装
+type TrashRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewTrashRepository は新しいTrashRepositoryを生成する
+func NewTrashRepository(db *sql.DB) domain.TrashRepository {
+	return &TrashRepositoryImpl{db: db}
+}
+
+// List はゴミ箱のデータを削除日時の新しい順に取得する
+func (r *TrashRepositoryImpl) List() ([]*domain.TrashItem, error) {
+	query := `
+		SELECT type, id, project_id, name, deleted_at
+		FROM (
+			SELECT $1::text AS type, p.id, p.id AS project_id, p.customer_name AS name, p.deleted_at
+			FROM projects p
+			WHERE p.deleted_at IS NOT NULL
+			UNION ALL
+			SELECT $2::text, f.id, f.project_id, f.file_name, f.deleted_at
+			FROM uploaded_files f
+			JOIN projects p ON p.id = f.project_id
+			WHERE f.deleted_at IS NOT NULL AND p.deleted_at IS NULL
+			UNION ALL
+			SELECT $3::text, k.id, k.project_id, k.question, k.deleted_at
+			FROM knowledge_items k
+			JOIN projects p ON p.id = k.project_id
+			WHERE k.deleted_at IS NOT NULL AND p.deleted_at IS NULL
+		) trash
+		ORDER BY deleted_at DESC, type ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, domain.TrashProject, domain.TrashFile, domain.TrashKnowledge)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*domain.TrashItem{}
+	for rows.Next() {
+		item := &domain.TrashItem{}
+		if err := rows.Scan(&item.Type, &item.ID, &item.ProjectID, &item.Name, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// RestoreProject は案件と、案件と同じ削除日時のファイル・ナレッジアイテムを復元する
+// 案件より前に個別に削除されていたものはゴミ箱に残す
+func (r *TrashRepositoryImpl) RestoreProject(id int) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var deletedAt time.Time
+		err := tx.QueryRow(
+			`SELECT deleted_at FROM projects WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`,
+			id,
+		).Scan(&deletedAt)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE projects SET deleted_at = NULL WHERE id = $1`, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE uploaded_files SET deleted_at = NULL WHERE project_id = $1 AND deleted_at = $2`, id, deletedAt); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE knowledge_items SET deleted_at = NULL WHERE project_id = $1 AND deleted_at = $2`, id, deletedAt)
+		return err
+	})
+}
+
+// RestoreFile はゴミ箱のファイルを復元する
+func (r *TrashRepositoryImpl) RestoreFile(id int) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var projectID int
+		var fileName string
+		var projectDeleted bool
+		err := tx.QueryRow(
+			`SELECT f.project_id, f.file_name, p.deleted_at IS NOT NULL
+			FROM uploaded_files f
+			JOIN projects p ON p.id = f.project_id
+			WHERE f.id = $1 AND f.deleted_at IS NOT NULL
+			FOR UPDATE OF f`,
+			id,
+		).Scan(&projectID, &fileName, &projectDeleted)
+		if err != nil {
+			return err
+		}
+		if projectDeleted {
+			return &domain.ValidationError{Field: "project_id", Message: "案件がゴミ箱にあるため、先に案件を復元してください"}
+		}
+
+		// ファイル名は案件内で一意のため、削除後に同名のファイルがアップロードされていれば復元できない
+		var exists bool
+		err = tx.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM uploaded_files WHERE project_id = $1 AND file_name = $2 AND deleted_at IS NULL)`,
+			projectID,
+			fileName,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return &domain.ValidationError{Field: "file_name", Message: fmt.Sprintf("同じ名前のファイルがあるため復元できません: %s", fileName)}
+		}
+
+		_, err = tx.Exec(`UPDATE uploaded_files SET deleted_at = NULL WHERE id = $1`, id)
+		return err
+	})
+}
+
+// RestoreKnowledge はゴミ箱のナレッジアイテムを復元する
+func (r *TrashRepositoryImpl) RestoreKnowledge(id int) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var projectDeleted bool
+		err := tx.QueryRow(
+			`SELECT p.deleted_at IS NOT NULL
+			FROM knowledge_items k
+			JOIN projects p ON p.id = k.project_id
+			WHERE k.id = $1 AND k.deleted_at IS NOT NULL
+			FOR UPDATE OF k`,
+			id,
+		).Scan(&projectDeleted)
+		if err != nil {
+			return err
+		}
+		if projectDeleted {
+			return &domain.ValidationError{Field: "project_id", Message: "案件がゴミ箱にあるため、先に案件を復元してください"}
+		}
+
+		_, err = tx.Exec(`UPDATE knowledge_items SET deleted_at = NULL WHERE id = $1`, id)
+		return err
+	})
+}
+
+// Purge はbefore以前に削除されたデータを完全に削除し、削除したファイル・エビデンスの保存先を返す
+// 関連する回答バリエーション・履歴・コメント・エビデンスの紐づけなどは外部キーのON DELETE CASCADEで削除される
+// エビデンスは案件に属さないため、完全に削除するナレッジからしか参照されていないものだけを削除する
+func (r *TrashRepositoryImpl) Purge(before time.Time) (*domain.PurgeResult, error) {
+	result := &domain.PurgeResult{}
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		evidencePaths, err := deleteReturningPaths(tx, `
+			DELETE FROM evidence_files e
+			WHERE e.id IN (
+				SELECT ke.evidence_file_id
+				FROM knowledge_item_evidence ke
+				JOIN knowledge_items k ON k.id = ke.knowledge_item_id
+				WHERE k.deleted_at <= $1
+			)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM knowledge_item_evidence ke
+				JOIN knowledge_items k ON k.id = ke.knowledge_item_id
+				WHERE ke.evidence_file_id = e.id AND (k.deleted_at IS NULL OR k.deleted_at > $1)
+			)
+			RETURNING e.file_path`, before)
+		if err != nil {
+			return err
+		}
+		result.Evidence = len(evidencePaths)
+
+		if result.Knowledge, err = deleteTrashed(tx, `DELETE FROM knowledge_items WHERE deleted_at <= $1`, before); err != nil {
+			return err
+		}
+
+		filePaths, err := deleteReturningPaths(tx, `DELETE FROM uploaded_files WHERE deleted_at <= $1 RETURNING file_path`, before)
+		if err != nil {
+			return err
+		}
+		result.Files = len(filePaths)
+		result.FilePaths = append(filePaths, evidencePaths...)
+
+		result.Projects, err = deleteTrashed(tx, `DELETE FROM projects WHERE deleted_at <= $1`, before)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// deleteReturningPaths は保存先を返す削除クエリを実行し、削除した行の保存先を返す
+func deleteReturningPaths(tx *sql.Tx, query string, before time.Time) ([]string, error) {
+	rows, err := tx.Query(query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paths := []string{}
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, rows.Err()
+}
+
+// deleteTrashed は削除クエリを実行して削除した行数を返す
+func deleteTrashed(tx *sql.Tx, query string, before time.Time) (int, error) {
+	result, err := tx.Exec(query, before)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
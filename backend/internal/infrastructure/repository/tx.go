@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// querier は*sql.DBと*sql.Txに共通するクエリ実行インターフェース
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// withTx はトランザクション内で関数を実行し、エラーがなければコミットする
+func withTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// withSimilarityThreshold はpg_trgmの%演算子・<%演算子の閾値を設定したトランザクション内で関数を実行する
+// 閾値はトランザクションの中でのみ有効なため、コネクションプールの他の接続には影響しない
+func withSimilarityThreshold(db *sql.DB, threshold float64, fn func(tx *sql.Tx) error) error {
+	return withTx(db, func(tx *sql.Tx) error {
+		value := strconv.FormatFloat(threshold, 'f', -1, 64)
+		_, err := tx.Exec(
+			`SELECT set_config('pg_trgm.similarity_threshold', $1, true), set_config('pg_trgm.word_similarity_threshold', $1, true)`,
+			value,
+		)
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// runBatch はn件の処理を1トランザクションで実行し、項目ごとのエラーを返す
+// bestEffortがfalseの場合は1件でも失敗すると全件ロールバックし、
+// trueの場合は失敗した項目のみをセーブポイントまで戻して残りをコミットする
+func runBatch(db *sql.DB, n int, bestEffort bool, fn func(tx *sql.Tx, i int) error) ([]error, error) {
+	itemErrs := make([]error, n)
+
+	err := withTx(db, func(tx *sql.Tx) error {
+		for i := 0; i < n; i++ {
+			if !bestEffort {
+				if err := fn(tx, i); err != nil {
+					itemErrs[i] = err
+					return err
+				}
+				continue
+			}
+
+			fnErr, txErr := withSavepoint(tx, "bulk_item", func() error {
+				return fn(tx, i)
+			})
+			if txErr != nil {
+				return txErr
+			}
+			itemErrs[i] = fnErr
+		}
+		return nil
+	})
+
+	return itemErrs, err
+}
+
+// withSavepoint はセーブポイントを設定して関数を実行し、失敗した場合はセーブポイントまで戻す
+// 関数のエラーはそのまま返し、セーブポイント操作自体の失敗はtxErrとして返す
+func withSavepoint(tx *sql.Tx, name string, fn func() error) (fnErr error, txErr error) {
+	if _, err := tx.Exec("SAVEPOINT " + name); err != nil {
+		return nil, err
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + name); rbErr != nil {
+			return err, rbErr
+		}
+		return err, nil
+	}
+
+	if _, err := tx.Exec("RELEASE SAVEPOINT " + name); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// AssignmentHandler は質問ごとの担当割り当てと進捗に関するHTTPハンドラー
+type AssignmentHandler struct {
+	useCase usecase.AssignmentUseCase
+}
+
+// NewAssignmentHandler は新しいAssignmentHandlerを生成する
+func NewAssignmentHandler(useCase usecase.AssignmentUseCase) *AssignmentHandler {
+	return &AssignmentHandler{useCase: useCase}
+}
+
+// BulkAssignRequest は一括割り当てリクエスト
+type BulkAssignRequest struct {
+	// KnowledgeIDs は対象のアイテム（省略時はfrom_department_idの部門のアイテムすべて）
+	KnowledgeIDs     []int `json:"knowledge_ids"`
+	FromDepartmentID *int  `json:"from_department_id"`
+	// DepartmentID・Assignee・AssignmentDueAt は省略した項目を変更しない（assigneeは空文字で担当者を外す）
+	DepartmentID    *int       `json:"department_id"`
+	Assignee        *string    `json:"assignee"`
+	AssignmentDueAt *time.Time `json:"assignment_due_at"`
+	Actor           string     `json:"actor"`
+}
+
+// BulkAssignResponse は一括割り当ての結果
+type BulkAssignResponse struct {
+	Updated int                     `json:"updated"`
+	Items   []*domain.KnowledgeItem `json:"items"`
+}
+
+// UpdateTaskRequest はアイテムの担当の更新リクエスト
+type UpdateTaskRequest struct {
+	Assignee        string     `json:"assignee"`
+	AssignmentDueAt *time.Time `json:"assignment_due_at"`
+	// TaskState はtodo（既定）、in_progress、doneのいずれか
+	TaskState string `json:"task_state"`
+}
+
+// BulkAssign は案件内のアイテムに部門・担当者・期限を一括で割り当てる
+// @Summary 担当の一括割り当て
+// @Description 選択したアイテム（knowledge_ids）、または部門のアイテムすべて（from_department_id）に部門・担当者・期限を一括で割り当てる。部門を付与した場合は版を進める
+// @Tags assignments
+// @Accept json
+// @Produce json
+// @Param id path int true "案件ID"
+// @Param body body BulkAssignRequest true "一括割り当てリクエスト"
+// @Success 200 {object} BulkAssignResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/assignments [post]
+func (h *AssignmentHandler) BulkAssign(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	var req BulkAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	target := domain.AssignmentTarget{KnowledgeIDs: req.KnowledgeIDs, DepartmentID: req.FromDepartmentID}
+	change := domain.AssignmentChange{
+		DepartmentID:    req.DepartmentID,
+		Assignee:        req.Assignee,
+		AssignmentDueAt: req.AssignmentDueAt,
+	}
+
+	items, err := h.useCase.BulkAssign(id, target, change, req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, BulkAssignResponse{Updated: len(items), Items: items})
+}
+
+// UpdateTask はアイテムの担当者・期限・タスクの状態を更新する
+// @Summary 担当の更新
+// @Description アイテムの担当者・期限・タスクの状態を置き換える。割り当ては内容の更新と独立しており、版を進めない
+// @Tags assignments
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param body body UpdateTaskRequest true "担当の更新リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/assignment [put]
+func (h *AssignmentHandler) UpdateTask(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req UpdateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.useCase.UpdateTask(id, req.Assignee, req.AssignmentDueAt, req.TaskState)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// GetProgress は案件の回答状況を部門ごとに集計する
+// @Summary 案件の進捗
+// @Description 案件のアイテムの回答済み・未回答・承認済み（approvedまたはpublished）の件数と担当タスクの状態を、案件全体と部門ごとに集計する。部門未設定のアイテムは最後にまとめる
+// @Tags assignments
+// @Produce json
+// @Param id path int true "案件ID"
+// @Success 200 {object} domain.ProjectProgress
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/progress [get]
+func (h *AssignmentHandler) GetProgress(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	progress, err := h.useCase.GetProgress(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// CanonicalHandler は標準質問ライブラリに関するHTTPハンドラー
+type CanonicalHandler struct {
+	useCase usecase.CanonicalUseCase
+}
+
+// NewCanonicalHandler は新しいCanonicalHandlerを生成する
+func NewCanonicalHandler(useCase usecase.CanonicalUseCase) *CanonicalHandler {
+	return &CanonicalHandler{useCase: useCase}
+}
+
+// CanonicalQuestionRequest は標準質問の作成・更新リクエスト
+type CanonicalQuestionRequest struct {
+	Question     string `json:"question" binding:"required"`
+	MasterAnswer string `json:"master_answer"`
+	DepartmentID *int   `json:"department_id"`
+	Actor        string `json:"actor"`
+}
+
+// UpdateCanonicalQuestionRequest は標準質問の更新リクエスト
+// 標準回答の省略で回答が空になり乖離フラグが一斉に外れないよう、標準回答は空文字でも明示的な指定を必須にする
+type UpdateCanonicalQuestionRequest struct {
+	Question     string  `json:"question" binding:"required"`
+	MasterAnswer *string `json:"master_answer" binding:"required"`
+	DepartmentID *int    `json:"department_id"`
+	Actor        string  `json:"actor"`
+}
+
+// LinkCanonicalRequest は標準質問への紐づけリクエスト
+type LinkCanonicalRequest struct {
+	CanonicalQuestionID int    `json:"canonical_question_id" binding:"required"`
+	Actor               string `json:"actor"`
+}
+
+// UnlinkCanonicalRequest は標準質問の紐づけ解除リクエスト
+type UnlinkCanonicalRequest struct {
+	Actor string `json:"actor"`
+}
+
+// CreateCanonicalQuestion は標準質問を作成する
+// @Summary 標準質問作成
+// @Description 統制項目ごとの標準質問と承認済みの標準回答を作成する
+// @Tags canonical-questions
+// @Accept json
+// @Produce json
+// @Param body body CanonicalQuestionRequest true "標準質問"
+// @Success 201 {object} domain.CanonicalQuestion
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/canonical-questions [post]
+func (h *CanonicalHandler) CreateCanonicalQuestion(c *gin.Context) {
+	var req CanonicalQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	cq := domain.NewCanonicalQuestion(req.Question, req.MasterAnswer, req.DepartmentID, req.Actor)
+	if err := h.useCase.CreateCanonicalQuestion(cq); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cq)
+}
+
+// ListCanonicalQuestions は標準質問の一覧を取得する
+// @Summary 標準質問一覧
+// @Description 標準質問の一覧を、紐づくアイテム数と標準回答から乖離したアイテム数とともに取得する
+// @Tags canonical-questions
+// @Produce json
+// @Param department_id query int false "部門ID"
+// @Success 200 {array} domain.CanonicalQuestion
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/canonical-questions [get]
+func (h *CanonicalHandler) ListCanonicalQuestions(c *gin.Context) {
+	var departmentID int
+	if v := c.Query("department_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なdepartment_idです"})
+			return
+		}
+		departmentID = id
+	}
+
+	questions, err := h.useCase.ListCanonicalQuestions(departmentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, questions)
+}
+
+// GetCanonicalQuestion は標準質問を取得する
+// @Summary 標準質問詳細
+// @Description 標準質問を取得する
+// @Tags canonical-questions
+// @Produce json
+// @Param id path int true "標準質問ID"
+// @Success 200 {object} domain.CanonicalQuestion
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/canonical-questions/{id} [get]
+func (h *CanonicalHandler) GetCanonicalQuestion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	cq, err := h.useCase.GetCanonicalQuestion(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cq)
+}
+
+// UpdateCanonicalQuestion は標準質問を更新する
+// @Summary 標準質問更新
+// @Description 標準質問を更新する。標準回答が変わった場合、紐づくアイテムのうち回答が異なるものに乖離フラグ（answer_drifted）が付く
+// @Tags canonical-questions
+// @Accept json
+// @Produce json
+// @Param id path int true "標準質問ID"
+// @Param body body UpdateCanonicalQuestionRequest true "標準質問（master_answerは必須）"
+// @Success 200 {object} domain.CanonicalQuestion
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/canonical-questions/{id} [put]
+func (h *CanonicalHandler) UpdateCanonicalQuestion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req UpdateCanonicalQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	cq := &domain.CanonicalQuestion{
+		ID:           id,
+		Question:     req.Question,
+		MasterAnswer: *req.MasterAnswer,
+		DepartmentID: req.DepartmentID,
+		UpdatedBy:    req.Actor,
+	}
+	if err := h.useCase.UpdateCanonicalQuestion(cq); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cq)
+}
+
+// DeleteCanonicalQuestion は標準質問を削除する
+// @Summary 標準質問削除
+// @Description 標準質問を削除する。紐づくアイテムは紐づけが解除される
+// @Tags canonical-questions
+// @Param id path int true "標準質問ID"
+// @Success 204
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/canonical-questions/{id} [delete]
+func (h *CanonicalHandler) DeleteCanonicalQuestion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	if err := h.useCase.DeleteCanonicalQuestion(id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListVariants は標準質問に紐づく案件ごとの表現を取得する
+// @Summary 標準質問のバリエーション一覧
+// @Description 標準質問に紐づくナレッジアイテムを案件順に取得する。answer_driftedは回答が標準回答と異なることを示す
+// @Tags canonical-questions
+// @Produce json
+// @Param id path int true "標準質問ID"
+// @Success 200 {array} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/canonical-questions/{id}/variants [get]
+func (h *CanonicalHandler) ListVariants(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	items, err := h.useCase.ListVariants(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// LinkKnowledge はナレッジアイテムを標準質問に紐づける
+// @Summary 標準質問への紐づけ
+// @Description ナレッジアイテムを標準質問に紐づける。回答が標準回答と異なる場合はanswer_driftedがtrueになる
+// @Tags canonical-questions
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body LinkCanonicalRequest true "紐づけリクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/canonical [put]
+func (h *CanonicalHandler) LinkKnowledge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req LinkCanonicalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	expectedVersion, ok := optionalIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+		return
+	}
+
+	item, err := h.useCase.LinkKnowledge(id, req.CanonicalQuestionID, req.Actor, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusOK, item)
+}
+
+// UnlinkKnowledge はナレッジアイテムと標準質問の紐づけを解除する
+// @Summary 標準質問の紐づけ解除
+// @Description ナレッジアイテムと標準質問の紐づけを解除する
+// @Tags canonical-questions
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body UnlinkCanonicalRequest false "解除リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/canonical [delete]
+func (h *CanonicalHandler) UnlinkKnowledge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req UnlinkCanonicalRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	expectedVersion, ok := optionalIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+		return
+	}
+
+	item, err := h.useCase.UnlinkKnowledge(id, req.Actor, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusOK, item)
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// CommentHandler はナレッジアイテムのコメントスレッドに関するHTTPハンドラー
+type CommentHandler struct {
+	useCase usecase.CommentUseCase
+}
+
+// NewCommentHandler は新しいCommentHandlerを生成する
+func NewCommentHandler(useCase usecase.CommentUseCase) *CommentHandler {
+	return &CommentHandler{useCase: useCase}
+}
+
+// CommentRequest はコメントの投稿・編集リクエスト
+type CommentRequest struct {
+	// Body はコメント本文。@ユーザー名 または @部門名 でメンションする
+	Body  string `json:"body" binding:"required"`
+	Actor string `json:"actor"`
+}
+
+// ThreadActionRequest はスレッドの解決・再開リクエスト
+type ThreadActionRequest struct {
+	Actor string `json:"actor"`
+}
+
+// GetThread はナレッジアイテムのコメントスレッドを取得する
+// @Summary コメントスレッドの取得
+// @Description ナレッジアイテムのコメントを投稿順に、解決状態とともに取得する
+// @Tags comments
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {object} domain.CommentThread
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/comments [get]
+func (h *CommentHandler) GetThread(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	thread, err := h.useCase.GetThread(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// AddComment はナレッジアイテムにコメントを投稿する
+// @Summary コメント投稿
+// @Description ナレッジアイテムのスレッドにコメントを投稿する。本文中の @名前 は部門名と一致すれば部門、それ以外はユーザーへのメンションになる。解決済みのスレッドは未解決に戻る
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param body body CommentRequest true "コメント"
+// @Success 201 {object} domain.KnowledgeComment
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/comments [post]
+func (h *CommentHandler) AddComment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = domain.AnonymousCommentAuthor
+	}
+
+	comment, err := h.useCase.AddComment(id, req.Actor, req.Body)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// ResolveThread はコメントスレッドを解決済みにする
+// @Summary スレッドの解決
+// @Description ナレッジアイテムのコメントスレッドを解決済みにする。解決後にコメントが投稿されると未解決に戻る
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param body body ThreadActionRequest false "解決リクエスト"
+// @Success 200 {object} domain.CommentThread
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/comments/resolve [post]
+func (h *CommentHandler) ResolveThread(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req ThreadActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	thread, err := h.useCase.ResolveThread(id, req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// ReopenThread は解決済みのコメントスレッドを未解決に戻す
+// @Summary スレッドの再開
+// @Description 解決済みのコメントスレッドを未解決に戻す
+// @Tags comments
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {object} domain.CommentThread
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/comments/reopen [post]
+func (h *CommentHandler) ReopenThread(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	thread, err := h.useCase.ReopenThread(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// EditComment はコメントを編集する
+// @Summary コメント編集
+// @Description コメントの本文を編集し、メンションを付け直す。投稿者のみ編集できる。actorを指定しない場合や、actorを指定せずに投稿されたコメントは編集できない（403）
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "コメントID"
+// @Param body body CommentRequest true "コメント"
+// @Success 200 {object} domain.KnowledgeComment
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/comments/{id} [put]
+func (h *CommentHandler) EditComment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なコメントIDです"})
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comment, err := h.useCase.EditComment(id, req.Actor, req.Body)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment はコメントを削除する
+// @Summary コメント削除
+// @Description コメントを削除する。投稿者のみ削除できる。actorを指定しない場合や、actorを指定せずに投稿されたコメントは削除できない（403）
+// @Tags comments
+// @Param id path int true "コメントID"
+// @Param actor query string true "操作者"
+// @Success 204
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なコメントIDです"})
+		return
+	}
+
+	if err := h.useCase.DeleteComment(id, c.Query("actor")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListOpenThreads は案件内の未解決のコメントスレッドを取得する
+// @Summary 未解決のスレッド一覧
+// @Description 提出前に確認が必要な、コメントがあり未解決のスレッドを案件内のアイテム順に取得する
+// @Tags comments
+// @Produce json
+// @Param id path int true "案件ID"
+// @Param mentioned_user query string false "このユーザーがメンションされたスレッドに限定する"
+// @Param mentioned_department_id query int false "この部門がメンションされたスレッドに限定する"
+// @Success 200 {array} domain.OpenThread
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/open-threads [get]
+func (h *CommentHandler) ListOpenThreads(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	filter := domain.OpenThreadFilter{MentionedUser: strings.TrimSpace(c.Query("mentioned_user"))}
+	if v := c.Query("mentioned_department_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なmentioned_department_idです"})
+			return
+		}
+		filter.MentionedDepartmentID = &n
+	}
+
+	threads, err := h.useCase.ListOpenThreads(id, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, threads)
+}
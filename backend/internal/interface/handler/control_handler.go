@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// ControlHandler は統制フレームワークとナレッジの紐づけに関するHTTPハンドラー
+type ControlHandler struct {
+	useCase usecase.ControlUseCase
+}
+
+// NewControlHandler は新しいControlHandlerを生成する
+func NewControlHandler(useCase usecase.ControlUseCase) *ControlHandler {
+	return &ControlHandler{useCase: useCase}
+}
+
+// SetControlsRequest はナレッジアイテムへの統制項目の紐づけリクエスト
+type SetControlsRequest struct {
+	ControlIDs []int  `json:"control_ids"`
+	Actor      string `json:"actor"`
+}
+
+// SetQuestionGroupControlsRequest は質問グループへの統制項目の紐づけリクエスト
+type SetQuestionGroupControlsRequest struct {
+	QuestionGroup string `json:"question_group" binding:"required"`
+	ControlIDs    []int  `json:"control_ids"`
+	Actor         string `json:"actor"`
+}
+
+// ImportCatalog は統制カタログを取り込む
+// @Summary 統制カタログ取込
+// @Description JSONまたはCSVのカタログからフレームワークと統制項目を登録・更新する。CSVの列はframework_code, framework_name, framework_version, control_id, title, category, description
+// @Tags controls
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "カタログファイル（.jsonまたは.csv）"
+// @Success 200 {array} domain.ControlFramework
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/control-frameworks/import [post]
+func (h *ControlHandler) ImportCatalog(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ファイルが指定されていません"})
+		return
+	}
+
+	var format string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".json":
+		format = usecase.CatalogFormatJSON
+	case ".csv":
+		format = usecase.CatalogFormatCSV
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSONまたはCSVファイルのみ取り込めます"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ファイルのオープンに失敗しました"})
+		return
+	}
+	defer file.Close()
+
+	frameworks, err := h.useCase.LoadCatalog(file, format)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, frameworks)
+}
+
+// ListFrameworks はフレームワークの一覧を取得する
+// @Summary 統制フレームワーク一覧
+// @Description 登録されている統制フレームワークの一覧を取得する
+// @Tags controls
+// @Produce json
+// @Success 200 {array} domain.ControlFramework
+// @Failure 500 {object} gin.H
+// @Router /api/control-frameworks [get]
+func (h *ControlHandler) ListFrameworks(c *gin.Context) {
+	frameworks, err := h.useCase.ListFrameworks()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, frameworks)
+}
+
+// ListControls はフレームワークの統制項目を取得する
+// @Summary 統制項目一覧
+// @Description フレームワークの統制項目を表示順に取得する
+// @Tags controls
+// @Produce json
+// @Param code path string true "フレームワークのコード（例: ISO27001）"
+// @Success 200 {array} domain.Control
+// @Failure 404 {object} gin.H
+// @Router /api/control-frameworks/{code}/controls [get]
+func (h *ControlHandler) ListControls(c *gin.Context) {
+	controls, err := h.useCase.ListControls(c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
+
+// GetCoverage はフレームワークの統制項目に対する回答の網羅状況を取得する
+// @Summary 統制項目の網羅状況
+// @Description 統制項目ごとに承認済みまたは公開済みの回答があるかを集計する。質問グループを通じた紐づけも含む
+// @Tags controls
+// @Produce json
+// @Param code path string true "フレームワークのコード（例: ISO27001）"
+// @Param prefix query string false "統制番号とその下位の番号に絞り込む条件（例: A.9はA.9とA.9.xに一致し、A.90には一致しない）"
+// @Param project_id query int false "案件ID（指定した場合はその案件のアイテムのみ集計）"
+// @Success 200 {object} domain.ControlCoverageReport
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/control-frameworks/{code}/coverage [get]
+func (h *ControlHandler) GetCoverage(c *gin.Context) {
+	var projectID int
+	if v := c.Query("project_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なproject_idです"})
+			return
+		}
+		projectID = id
+	}
+
+	report, err := h.useCase.GetCoverage(c.Param("code"), c.Query("prefix"), projectID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetKnowledgeControls はナレッジアイテムに紐づく統制項目を取得する
+// @Summary ナレッジの統制項目一覧
+// @Description ナレッジアイテムに直接または質問グループを通じて紐づく統制項目を取得する。sourceは紐づけ元（item/question_group）
+// @Tags controls
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {array} domain.KnowledgeControl
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/controls [get]
+func (h *ControlHandler) GetKnowledgeControls(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	controls, err := h.useCase.GetKnowledgeControls(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
+
+// SetKnowledgeControls はナレッジアイテムに直接紐づける統制項目を置き換える
+// @Summary ナレッジの統制項目設定
+// @Description ナレッジアイテムに直接紐づける統制項目を指定した内容で置き換える。空の配列を指定すると直接の紐づけをすべて解除する
+// @Tags controls
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param body body SetControlsRequest true "紐づけリクエスト"
+// @Success 200 {array} domain.KnowledgeControl
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/controls [put]
+func (h *ControlHandler) SetKnowledgeControls(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req SetControlsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	controls, err := h.useCase.SetKnowledgeControls(id, req.ControlIDs, req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
+
+// GetQuestionGroupControls は質問グループに紐づく統制項目を取得する
+// @Summary 質問グループの統制項目一覧
+// @Description 質問グループに紐づく統制項目を取得する
+// @Tags controls
+// @Produce json
+// @Param question_group query string true "質問グループ"
+// @Success 200 {array} domain.Control
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/question-groups/controls [get]
+func (h *ControlHandler) GetQuestionGroupControls(c *gin.Context) {
+	controls, err := h.useCase.GetQuestionGroupControls(c.Query("question_group"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
+
+// SetQuestionGroupControls は質問グループに紐づける統制項目を置き換える
+// @Summary 質問グループの統制項目設定
+// @Description 質問グループに紐づける統制項目を指定した内容で置き換える。同じ質問グループのすべてのアイテムに適用される
+// @Tags controls
+// @Accept json
+// @Produce json
+// @Param body body SetQuestionGroupControlsRequest true "紐づけリクエスト"
+// @Success 200 {array} domain.Control
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/question-groups/controls [put]
+func (h *ControlHandler) SetQuestionGroupControls(c *gin.Context) {
+	var req SetQuestionGroupControlsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	controls, err := h.useCase.SetQuestionGroupControls(req.QuestionGroup, req.ControlIDs, req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// DuplicateHandler は重複したナレッジの検出・解消に関するHTTPハンドラー
+type DuplicateHandler struct {
+	useCase usecase.DuplicateUseCase
+}
+
+// NewDuplicateHandler は新しいDuplicateHandlerを生成する
+func NewDuplicateHandler(useCase usecase.DuplicateUseCase) *DuplicateHandler {
+	return &DuplicateHandler{useCase: useCase}
+}
+
+// ResolveDuplicatesRequest は重複の解消リクエスト
+type ResolveDuplicatesRequest struct {
+	KeepID       int   `json:"keep_id" binding:"required"`
+	DuplicateIDs []int `json:"duplicate_ids" binding:"required"`
+	// Action はmerge（残すアイテムに取り込み派生元として記録）またはdelete
+	Action string `json:"action" binding:"required"`
+	Actor  string `json:"actor"`
+}
+
+// FindDuplicates は案件内の重複したナレッジを検出する
+// @Summary 重複ナレッジの検出
+// @Description 案件内のナレッジアイテムを、正規化した質問の完全一致またはトライグラム類似度で重複のグループにまとめる
+// @Tags knowledge
+// @Produce json
+// @Param id path int true "案件ID"
+// @Param threshold query number false "類似度の閾値（0〜1、既定0.8）"
+// @Success 200 {array} domain.DuplicateGroup
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/knowledge/duplicates [get]
+func (h *DuplicateHandler) FindDuplicates(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	var threshold float64
+	if v := c.Query("threshold"); v != "" {
+		threshold, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なthresholdです"})
+			return
+		}
+	}
+
+	groups, err := h.useCase.FindDuplicates(projectID, threshold)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// ResolveDuplicates は重複を解消する
+// @Summary 重複ナレッジの解消
+// @Description 残すアイテムを1つ指定し、他の重複を統合（merge）またはゴミ箱に移す（delete）。mergeでは未設定の回答・部門・質問グループを重複から引き継ぎ（回答は残すアイテムがdraft/rejectedの場合のみ）、ゴミ箱に移した重複を派生元として記録する
+// @Tags knowledge
+// @Accept json
+// @Produce json
+// @Param id path int true "案件ID"
+// @Param If-Match header string false "残すアイテム取得時のETag"
+// @Param body body ResolveDuplicatesRequest true "解消リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/projects/{id}/knowledge/duplicates/resolve [post]
+func (h *DuplicateHandler) ResolveDuplicates(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	var req ResolveDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	expectedVersion, ok := optionalIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+		return
+	}
+
+	resolution := domain.DuplicateResolution{
+		KeepID:       req.KeepID,
+		DuplicateIDs: req.DuplicateIDs,
+		Action:       req.Action,
+	}
+	item, err := h.useCase.ResolveDuplicates(projectID, resolution, req.Actor, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusOK, item)
+}
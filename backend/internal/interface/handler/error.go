@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// respondError はエラーの種類に応じたステータスコードでエラーレスポンスを返す
+func respondError(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	var conflictErr *domain.VersionConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		// 競合時はクライアントがマージできるようサーバー上の最新の内容を返す
+		setKnowledgeETag(c, conflictErr.Current)
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "current": conflictErr.Current})
+	case errors.Is(err, domain.ErrVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrNotCommentAuthor):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// EvidenceHandler はナレッジアイテムに紐づくエビデンスに関するHTTPハンドラー
+type EvidenceHandler struct {
+	useCase usecase.EvidenceUseCase
+}
+
+// NewEvidenceHandler は新しいEvidenceHandlerを生成する
+func NewEvidenceHandler(useCase usecase.EvidenceUseCase) *EvidenceHandler {
+	return &EvidenceHandler{useCase: useCase}
+}
+
+// LinkEvidenceRequest は登録済みのエビデンスの紐づけリクエスト
+type LinkEvidenceRequest struct {
+	Actor string `json:"actor"`
+}
+
+// UpdateEvidenceRequest はエビデンスの更新リクエスト
+type UpdateEvidenceRequest struct {
+	Description string `json:"description"`
+	// ExpiresAt はエビデンスの有効期限（RFC3339。nullで期限なし）
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UploadEvidence はエビデンスをアップロードしてナレッジアイテムに紐づける
+// @Summary エビデンスのアップロード
+// @Description エビデンス（規程類のPDF、診断結果の概要、証明書など）をアップロードし、ナレッジアイテムに紐づける
+// @Tags evidence
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param file formData file true "エビデンスのファイル"
+// @Param description formData string false "説明"
+// @Param expires_at formData string false "有効期限（YYYY-MM-DDまたはRFC3339）"
+// @Param uploaded_by formData string false "アップロード者"
+// @Success 201 {object} domain.EvidenceFile
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/evidence [post]
+func (h *EvidenceHandler) UploadEvidence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ファイルが指定されていません"})
+		return
+	}
+
+	expiresAt, err := parseTimeValue("expires_at", c.PostForm("expires_at"), false)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	uploadedBy := c.PostForm("uploaded_by")
+	if uploadedBy == "" {
+		uploadedBy = "anonymous"
+	}
+
+	file, err := h.useCase.UploadEvidence(id, fileHeader, c.PostForm("description"), expiresAt, uploadedBy)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, file)
+}
+
+// ListKnowledgeEvidence はナレッジアイテムに紐づくエビデンスを取得する
+// @Summary ナレッジのエビデンス一覧
+// @Description ナレッジアイテムに紐づくエビデンスを紐づけた順に取得する
+// @Tags evidence
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {array} domain.KnowledgeEvidence
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/evidence [get]
+func (h *EvidenceHandler) ListKnowledgeEvidence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	links, err := h.useCase.ListKnowledgeEvidence(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// LinkEvidence は登録済みのエビデンスをナレッジアイテムに紐づける
+// @Summary エビデンスの紐づけ
+// @Description 他のアイテムで登録済みのエビデンスをナレッジアイテムに紐づける。紐づけ済みの場合は何もしない
+// @Tags evidence
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param evidenceId path int true "エビデンスID"
+// @Param body body LinkEvidenceRequest false "紐づけリクエスト"
+// @Success 200 {array} domain.KnowledgeEvidence
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/evidence/{evidenceId} [put]
+func (h *EvidenceHandler) LinkEvidence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+	evidenceID, err := strconv.Atoi(c.Param("evidenceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なエビデンスIDです"})
+		return
+	}
+
+	var req LinkEvidenceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	links, err := h.useCase.LinkEvidence(id, evidenceID, req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// UnlinkEvidence はナレッジアイテムとエビデンスの紐づけを解除する
+// @Summary エビデンスの紐づけ解除
+// @Description ナレッジアイテムとエビデンスの紐づけを解除する。どのアイテムからも参照されなくなったエビデンスはファイルごと削除する
+// @Tags evidence
+// @Param id path int true "ナレッジID"
+// @Param evidenceId path int true "エビデンスID"
+// @Success 204
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/evidence/{evidenceId} [delete]
+func (h *EvidenceHandler) UnlinkEvidence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+	evidenceID, err := strconv.Atoi(c.Param("evidenceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なエビデンスIDです"})
+		return
+	}
+
+	if err := h.useCase.UnlinkEvidence(id, evidenceID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListEvidence はエビデンスの一覧を取得する
+// @Summary エビデンス一覧
+// @Description すべてのエビデンスを紐づくアイテム数とともに有効期限の早い順（期限なしは最後）で取得する
+// @Tags evidence
+// @Produce json
+// @Success 200 {array} domain.EvidenceFile
+// @Failure 500 {object} gin.H
+// @Router /api/evidence [get]
+func (h *EvidenceHandler) ListEvidence(c *gin.Context) {
+	files, err := h.useCase.ListEvidence()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, files)
+}
+
+// UpdateEvidence はエビデンスの説明と有効期限を更新する
+// @Summary エビデンス更新
+// @Description エビデンスの説明と有効期限を更新する。証明書の更新などでファイルを差し替える場合は新しいエビデンスをアップロードする
+// @Tags evidence
+// @Accept json
+// @Produce json
+// @Param id path int true "エビデンスID"
+// @Param body body UpdateEvidenceRequest true "更新リクエスト"
+// @Success 200 {object} domain.EvidenceFile
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/evidence/{id} [put]
+func (h *EvidenceHandler) UpdateEvidence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なエビデンスIDです"})
+		return
+	}
+
+	var req UpdateEvidenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := h.useCase.UpdateEvidence(id, req.Description, req.ExpiresAt)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, file)
+}
+
+// DownloadEvidence はエビデンスのファイルをダウンロードする
+// @Summary エビデンスのダウンロード
+// @Description エビデンスのファイルをアップロード時のファイル名でダウンロードする
+// @Tags evidence
+// @Produce octet-stream
+// @Param id path int true "エビデンスID"
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/evidence/{id}/download [get]
+func (h *EvidenceHandler) DownloadEvidence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なエビデンスIDです"})
+		return
+	}
+
+	file, err := h.useCase.GetEvidence(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if file.ContentType != "" {
+		c.Header("Content-Type", file.ContentType)
+	}
+	c.FileAttachment(file.FilePath, file.FileName)
+}
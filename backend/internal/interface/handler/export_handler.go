@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// ExportHandler はエクスポートに関するHTTPハンドラー
+type ExportHandler struct {
+	useCase usecase.ExportUseCase
+}
+
+// NewExportHandler は新しいExportHandlerを生成する
+func NewExportHandler(useCase usecase.ExportUseCase) *ExportHandler {
+	return &ExportHandler{useCase: useCase}
+}
+
+// ExportProjectKnowledge は案件のナレッジをCSVでエクスポートする
+// @Summary 案件のナレッジCSVエクスポート
+// @Description 指定された案件に紐づくナレッジをCSVで出力する
+// @Tags export
+// @Produce text/csv
+// @Param id path int true "案件ID"
+// @Param encoding query string false "文字コード（utf-8-bom / utf-8）"
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/knowledge/export.csv [get]
+func (h *ExportHandler) ExportProjectKnowledge(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	opts := usecase.ExportOptions{Encoding: c.Query("encoding")}
+	fileName := fmt.Sprintf("knowledge_project_%d_%s.csv", projectID, time.Now().Format("20060102_150405"))
+
+	writeCSV(c, fileName, func(w io.Writer) error {
+		return h.useCase.ExportProjectKnowledge(projectID, w, opts)
+	})
+}
+
+// ExportSearchKnowledge は検索結果のナレッジをCSVでエクスポートする
+// @Summary ナレッジ検索結果CSVエクスポート
+// @Description 検索条件に一致するナレッジをCSVで出力する
+// @Tags export
+// @Produce text/csv
+// @Param q query string false "検索クエリ"
+// @Param project_id query []int false "案件ID（複数指定可）" collectionFormat(multi)
+// @Param department_id query []int false "部門ID（複数指定可）" collectionFormat(multi)
+// @Param status query []string false "ステータス（複数指定可）" collectionFormat(multi)
+// @Param question_group query []string false "質問グループ（複数指定可）" collectionFormat(multi)
+// @Param created_by query []string false "作成者（複数指定可）" collectionFormat(multi)
+// @Param assignee query []string false "担当者（複数指定可）" collectionFormat(multi)
+// @Param task_state query []string false "担当タスクの状態（todo/in_progress/done、複数指定可）" collectionFormat(multi)
+// @Param tag_id query []int false "タグID（複数指定可。子孫のタグが紐づくアイテムも含む）" collectionFormat(multi)
+// @Param control_id query []int false "統制項目ID（複数指定可。質問グループを通じた紐づけも含む）" collectionFormat(multi)
+// @Param created_from query string false "作成日時の開始（YYYY-MM-DDまたはRFC3339）"
+// @Param created_to query string false "作成日時の終了（日付のみの場合はその日を含む）"
+// @Param updated_from query string false "更新日時の開始（YYYY-MM-DDまたはRFC3339）"
+// @Param updated_to query string false "更新日時の終了（日付のみの場合はその日を含む）"
+// @Param unanswered query bool false "未回答のみ"
+// @Param encoding query string false "文字コード（utf-8-bom / utf-8）"
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/search/export.csv [get]
+func (h *ExportHandler) ExportSearchKnowledge(c *gin.Context) {
+	query := c.Query("q")
+	filter, err := parseSearchFilters(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	opts := usecase.ExportOptions{Encoding: c.Query("encoding")}
+	fileName := fmt.Sprintf("knowledge_search_%s.csv", time.Now().Format("20060102_150405"))
+
+	writeCSV(c, fileName, func(w io.Writer) error {
+		return h.useCase.ExportSearchKnowledge(query, filter, w, opts)
+	})
+}
+
+// ExportProjectKnowledgeZip は案件のナレッジをエビデンスとともにzipでエクスポートする
+// @Summary 案件のナレッジのエビデンス付きエクスポート
+// @Description 指定された案件に紐づくナレッジのCSV（evidence列付き）と、紐づくエビデンスのファイルをzipで出力する。有効期限切れのエビデンスは同梱せず、evidence列にファイル名と期限のみを記載する
+// @Tags export
+// @Produce application/zip
+// @Param id path int true "案件ID"
+// @Param encoding query string false "CSVの文字コード（utf-8-bom / utf-8）"
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/knowledge/export.zip [get]
+func (h *ExportHandler) ExportProjectKnowledgeZip(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	opts := usecase.ExportOptions{Encoding: c.Query("encoding"), WithEvidence: true}
+	fileName := fmt.Sprintf("knowledge_project_%d_%s.zip", projectID, time.Now().Format("20060102_150405"))
+
+	writeAttachment(c, "application/zip", fileName, func(w io.Writer) error {
+		return h.useCase.ExportProjectKnowledge(projectID, w, opts)
+	})
+}
+
+// ExportSearchKnowledgeZip は検索結果のナレッジをエビデンスとともにzipでエクスポートする
+// @Summary ナレッジ検索結果のエビデンス付きエクスポート
+// @Description 検索条件に一致するナレッジのCSV（evidence列付き）と、紐づくエビデンスのファイルをzipで出力する。検索条件はCSVエクスポートと同じ
+// @Tags export
+// @Produce application/zip
+// @Param q query string false "検索クエリ"
+// @Param encoding query string false "CSVの文字コード（utf-8-bom / utf-8）"
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/search/export.zip [get]
+func (h *ExportHandler) ExportSearchKnowledgeZip(c *gin.Context) {
+	query := c.Query("q")
+	filter, err := parseSearchFilters(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	opts := usecase.ExportOptions{Encoding: c.Query("encoding"), WithEvidence: true}
+	fileName := fmt.Sprintf("knowledge_search_%s.zip", time.Now().Format("20060102_150405"))
+
+	writeAttachment(c, "application/zip", fileName, func(w io.Writer) error {
+		return h.useCase.ExportSearchKnowledge(query, filter, w, opts)
+	})
+}
+
+// writeCSV はCSVダウンロード用のヘッダーを付与してレスポンスを書き出す
+func writeCSV(c *gin.Context, fileName string, write func(w io.Writer) error) {
+	writeAttachment(c, "text/csv; charset=utf-8", fileName, write)
+}
+
+// writeAttachment はダウンロード用のヘッダーを付与してレスポンスを書き出す
+func writeAttachment(c *gin.Context, contentType string, fileName string, write func(w io.Writer) error) {
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	if err := write(c.Writer); err != nil {
+		// 書き出し開始後はステータスを変更できないため、そのまま終了する
+		if c.Writer.Written() {
+			c.Error(err)
+			return
+		}
+
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		respondError(c, err)
+	}
+}
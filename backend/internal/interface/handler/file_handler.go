@@ -86,13 +86,18 @@ func (h *FileHandler) GetFile(c *gin.Context) {
 	c.JSON(http.StatusOK, file)
 }
 
-// ListFilesByProject は指定された案件のすべてのファイルを取得する
+// ListFilesByProject は指定された案件のファイル一覧を取得する
 // @Summary 案件のファイル一覧取得
-// @Description 指定された案件に紐づくすべてのファイルを取得する
+// @Description 指定された案件に紐づくファイルをページ単位で取得する
 // @Tags files
 // @Produce json
 // @Param id path int true "案件ID"
-// @Success 200 {array} domain.UploadedFile
+// @Param limit query int false "取得件数（既定50、最大500）"
+// @Param offset query int false "開始位置"
+// @Param sort query string false "並び替え項目（uploaded_at, file_name, file_size, id）"
+// @Param order query string false "並び順（asc, desc）"
+// @Success 200 {object} domain.Page[domain.UploadedFile]
+// @Failure 400 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/projects/{id}/files [get]
 func (h *FileHandler) ListFilesByProject(c *gin.Context) {
@@ -102,22 +107,29 @@ func (h *FileHandler) ListFilesByProject(c *gin.Context) {
 		return
 	}
 
-	files, err := h.useCase.GetFilesByProject(projectID)
+	page, err := parsePageRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	files, err := h.useCase.GetFilesByProject(projectID, page)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, files)
 }
 
-// DeleteFile はファイルを削除する
+// DeleteFile はファイルをゴミ箱に移す
 // @Summary ファイル削除
-// @Description ファイルを削除する
+// @Description ファイルをゴミ箱に移す。保持期間内であれば /api/trash/files/{id}/restore で復元できる
 // @Tags files
 // @Param id path int true "ファイルID"
 // @Success 204
 // @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/files/{id} [delete]
 func (h *FileHandler) DeleteFile(c *gin.Context) {
@@ -128,7 +140,7 @@ func (h *FileHandler) DeleteFile(c *gin.Context) {
 	}
 
 	if err := h.useCase.DeleteFile(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
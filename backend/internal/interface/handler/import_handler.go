@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// ImportHandler はインポートに関するHTTPハンドラー
+type ImportHandler struct {
+	useCase usecase.ImportUseCase
+}
+
+// NewImportHandler は新しいImportHandlerを生成する
+func NewImportHandler(useCase usecase.ImportUseCase) *ImportHandler {
+	return &ImportHandler{useCase: useCase}
+}
+
+// ImportKnowledge はCSVまたはExcelファイルからナレッジを取り込む
+// @Summary ナレッジCSV・Excelインポート
+// @Description エクスポートと同じスキーマのCSV、または同じ列名をヘッダー行に持つExcelファイル（.xlsx、最初のシート）からナレッジを取り込む。取り込んだナレッジはstatus列に関わらず下書き（draft）として作成する
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSVまたはExcel（.xlsx）ファイル"
+// @Param project_id formData int true "取込先の案件ID"
+// @Param created_by formData string false "作成者（CSVのcreated_by列が空の場合に使用）"
+// @Param dry_run formData bool false "trueの場合は検証のみ行い登録しない"
+// @Param allow_duplicates formData bool false "trueの場合は既存と重複する質問も登録する"
+// @Success 200 {object} usecase.ImportResult
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/import [post]
+func (h *ImportHandler) ImportKnowledge(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.PostForm("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ファイルが指定されていません"})
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	if ext != ".csv" && ext != ".xlsx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSVまたはExcel（.xlsx）ファイルのみ取り込めます"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ファイルのオープンに失敗しました"})
+		return
+	}
+	defer file.Close()
+
+	opts := usecase.ImportOptions{
+		ProjectID:       projectID,
+		CreatedBy:       c.PostForm("created_by"),
+		DryRun:          c.PostForm("dry_run") == "true",
+		AllowDuplicates: c.PostForm("allow_duplicates") == "true",
+	}
+	if opts.CreatedBy == "" {
+		opts.CreatedBy = "anonymous"
+	}
+
+	var result *usecase.ImportResult
+	if ext == ".xlsx" {
+		result, err = h.useCase.ImportKnowledgeXLSX(file, opts)
+	} else {
+		result, err = h.useCase.ImportKnowledge(file, opts)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/security-checksheets/backend/internal/domain"
@@ -30,11 +33,72 @@ type CreateKnowledgeRequest struct {
 	DepartmentID  *int   `json:"department_id"`
 	QuestionGroup string `json:"question_group"`
 	CreatedBy     string `json:"created_by"`
+	// ReviewDueAt は次回の定期レビューの期限、ValidUntil は回答の有効期限（RFC3339）
+	ReviewDueAt *time.Time `json:"review_due_at"`
+	ValidUntil  *time.Time `json:"valid_until"`
+	// ReviewIntervalDays は再レビューの承認時に次回のレビュー期限を決める間隔（日数、省略時は365日）
+	ReviewIntervalDays *int `json:"review_interval_days"`
+	// Assignee は回答の担当者、AssignmentDueAt は担当の期限（RFC3339）
+	Assignee        string     `json:"assignee"`
+	AssignmentDueAt *time.Time `json:"assignment_due_at"`
+	// Answers は回答バリエーション（指定した場合answerはこれらから合成される）
+	Answers []*domain.KnowledgeAnswer `json:"answers"`
+}
+
+// UpdateKnowledgeRequest はナレッジ更新リクエスト（省略した項目は変更しない）
+// department_id・review_due_at・valid_until・review_interval_daysはnullを指定すると値を消す
+type UpdateKnowledgeRequest struct {
+	SheetName     *string                    `json:"sheet_name"`
+	SourceRange   *string                    `json:"source_range"`
+	Question      *string                    `json:"question"`
+	Answer        *string                    `json:"answer"`
+	DepartmentID  domain.Optional[int]       `json:"department_id" swaggertype:"integer"`
+	QuestionGroup *string                    `json:"question_group"`
+	ReviewDueAt   domain.Optional[time.Time] `json:"review_due_at" swaggertype:"string" format:"date-time"`
+	ValidUntil    domain.Optional[time.Time] `json:"valid_until" swaggertype:"string" format:"date-time"`
+	// ReviewIntervalDays は再レビューの承認時に次回のレビュー期限を決める間隔（日数）
+	ReviewIntervalDays domain.Optional[int] `json:"review_interval_days" swaggertype:"integer"`
+	// Answers は回答バリエーションの置き換え（IDのない回答は追加、含まれない回答は削除）
+	Answers []*domain.KnowledgeAnswer `json:"answers"`
+	// Status は現在のステータスと同じ値のみ受け付ける（変更はワークフローのエンドポイントで行う）
+	Status    *string `json:"status"`
+	UpdatedBy string  `json:"updated_by"`
+	// Version は取得時の版（If-Matchヘッダーを指定した場合はそちらを優先する）
+	Version int `json:"version"`
 }
 
 // BulkCreateKnowledgeRequest は一括作成リクエスト
 type BulkCreateKnowledgeRequest struct {
 	Items []CreateKnowledgeRequest `json:"items" binding:"required"`
+	// Mode はall_or_nothing（既定）またはbest_effort
+	Mode string `json:"mode"`
+}
+
+// BulkKnowledgePatch は一括更新で変更する項目（省略した項目は変更しない）
+type BulkKnowledgePatch struct {
+	// ProjectID は移動先の案件
+	ProjectID     *int    `json:"project_id"`
+	DepartmentID  *int    `json:"department_id"`
+	QuestionGroup *string `json:"question_group"`
+	// Status はワークフローの遷移規則に従って変更する（rejectedにはreasonが必要）
+	Status *string `json:"status"`
+	Reason string  `json:"reason"`
+}
+
+// BulkUpdateKnowledgeRequest は一括更新リクエスト
+type BulkUpdateKnowledgeRequest struct {
+	IDs   []int              `json:"ids" binding:"required"`
+	Patch BulkKnowledgePatch `json:"patch"`
+	// Mode はall_or_nothing（既定）またはbest_effort
+	Mode  string `json:"mode"`
+	Actor string `json:"actor"`
+}
+
+// BulkDeleteKnowledgeRequest は一括削除リクエスト
+type BulkDeleteKnowledgeRequest struct {
+	IDs []int `json:"ids" binding:"required"`
+	// Mode はall_or_nothing（既定）またはbest_effort
+	Mode string `json:"mode"`
 }
 
 // CreateKnowledge はナレッジアイテムを作成する
@@ -69,9 +133,15 @@ func (h *KnowledgeHandler) CreateKnowledge(c *gin.Context) {
 	if req.QuestionGroup != "" {
 		item.QuestionGroup = req.QuestionGroup
 	}
+	item.Answers = req.Answers
+	item.ReviewDueAt = req.ReviewDueAt
+	item.ValidUntil = req.ValidUntil
+	item.ReviewIntervalDays = req.ReviewIntervalDays
+	item.Assignee = strings.TrimSpace(req.Assignee)
+	item.AssignmentDueAt = req.AssignmentDueAt
 
 	if err := h.useCase.CreateKnowledge(item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -80,13 +150,15 @@ func (h *KnowledgeHandler) CreateKnowledge(c *gin.Context) {
 
 // BulkCreateKnowledge は複数のナレッジアイテムを一括作成する
 // @Summary ナレッジ一括作成
-// @Description 複数のナレッジアイテムを一括作成する
+// @Description 複数のナレッジアイテムを1トランザクションで一括作成し、項目ごとの結果を返す
 // @Tags knowledge
 // @Accept json
 // @Produce json
 // @Param body body BulkCreateKnowledgeRequest true "一括作成リクエスト"
-// @Success 201 {object} gin.H
+// @Success 201 {object} usecase.BulkCreateResult
+// @Success 200 {object} usecase.BulkCreateResult "best_effortで一部失敗"
 // @Failure 400 {object} gin.H
+// @Failure 422 {object} usecase.BulkCreateResult "all_or_nothingで失敗"
 // @Failure 500 {object} gin.H
 // @Router /api/knowledge/bulk [post]
 func (h *KnowledgeHandler) BulkCreateKnowledge(c *gin.Context) {
@@ -111,19 +183,110 @@ func (h *KnowledgeHandler) BulkCreateKnowledge(c *gin.Context) {
 		if itemReq.QuestionGroup != "" {
 			items[i].QuestionGroup = itemReq.QuestionGroup
 		}
+		items[i].Answers = itemReq.Answers
+		items[i].ReviewDueAt = itemReq.ReviewDueAt
+		items[i].ValidUntil = itemReq.ValidUntil
+		items[i].ReviewIntervalDays = itemReq.ReviewIntervalDays
+		items[i].Assignee = strings.TrimSpace(itemReq.Assignee)
+		items[i].AssignmentDueAt = itemReq.AssignmentDueAt
+	}
+
+	result, err := h.useCase.BulkCreateKnowledge(items, req.Mode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	switch {
+	case result.Failed == 0:
+		c.JSON(http.StatusCreated, result)
+	case result.Mode == usecase.BulkModeAllOrNothing:
+		c.JSON(http.StatusUnprocessableEntity, result)
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// BulkUpdateKnowledge は複数のナレッジアイテムに同じ変更を一括で反映する
+// @Summary ナレッジ一括更新
+// @Description 選択した複数のアイテムの部門・質問グループ・ステータス・案件を1トランザクションで変更し、IDごとの結果を返す。ステータスはワークフローの遷移規則に従って変更する
+// @Tags knowledge
+// @Accept json
+// @Produce json
+// @Param body body BulkUpdateKnowledgeRequest true "一括更新リクエスト"
+// @Success 200 {object} usecase.BulkResult
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 422 {object} usecase.BulkResult "all_or_nothingで失敗"
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/bulk-update [post]
+func (h *KnowledgeHandler) BulkUpdateKnowledge(c *gin.Context) {
+	var req BulkUpdateKnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	patch := domain.KnowledgeBulkPatch{
+		ProjectID:     req.Patch.ProjectID,
+		DepartmentID:  req.Patch.DepartmentID,
+		QuestionGroup: req.Patch.QuestionGroup,
+		Status:        req.Patch.Status,
+		Reason:        req.Patch.Reason,
 	}
 
-	if err := h.useCase.BulkCreateKnowledge(items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	result, err := h.useCase.BulkUpdateKnowledge(req.IDs, patch, req.Mode, req.Actor)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "一括作成が完了しました", "count": len(items)})
+	respondBulkResult(c, result)
+}
+
+// BulkDeleteKnowledge は複数のナレッジアイテムを一括で削除する
+// @Summary ナレッジ一括削除
+// @Description 選択した複数のアイテムを1トランザクションでゴミ箱に移し、IDごとの結果を返す
+// @Tags knowledge
+// @Accept json
+// @Produce json
+// @Param body body BulkDeleteKnowledgeRequest true "一括削除リクエスト"
+// @Success 200 {object} usecase.BulkResult
+// @Failure 400 {object} gin.H
+// @Failure 422 {object} usecase.BulkResult "all_or_nothingで失敗"
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/bulk-delete [post]
+func (h *KnowledgeHandler) BulkDeleteKnowledge(c *gin.Context) {
+	var req BulkDeleteKnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.useCase.BulkDeleteKnowledge(req.IDs, req.Mode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondBulkResult(c, result)
+}
+
+// respondBulkResult は一括更新・一括削除の結果を返す。all_or_nothingで失敗した場合は422とする
+func respondBulkResult(c *gin.Context, result *usecase.BulkResult) {
+	if result.Failed > 0 && result.Mode == usecase.BulkModeAllOrNothing {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
 }
 
 // GetKnowledge はナレッジアイテムを取得する
 // @Summary ナレッジ取得
-// @Description 指定されたIDのナレッジアイテムを取得する
+// @Description 指定されたIDのナレッジアイテムを回答バリエーションとともに取得する
 // @Tags knowledge
 // @Produce json
 // @Param id path int true "ナレッジID"
@@ -143,16 +306,23 @@ func (h *KnowledgeHandler) GetKnowledge(c *gin.Context) {
 		return
 	}
 
+	setKnowledgeETag(c, item)
 	c.JSON(http.StatusOK, item)
 }
 
 // ListKnowledgeByProject は案件に紐づくナレッジアイテムを取得する
 // @Summary 案件のナレッジ一覧取得
-// @Description 指定された案件に紐づくナレッジアイテムを取得する
+// @Description 指定された案件に紐づくナレッジアイテムをページ単位で取得する
 // @Tags knowledge
 // @Produce json
 // @Param id path int true "案件ID"
-// @Success 200 {array} domain.KnowledgeItem
+// @Param limit query int false "取得件数（既定50、最大500）"
+// @Param offset query int false "開始位置"
+// @Param sort query string false "並び替え項目（created_at, updated_at, id, question, sheet_name, source_range, status, department_id）"
+// @Param order query string false "並び順（asc, desc）"
+// @Success 200 {object} domain.Page[domain.KnowledgeItem]
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/projects/{id}/knowledge [get]
 func (h *KnowledgeHandler) ListKnowledgeByProject(c *gin.Context) {
@@ -162,27 +332,40 @@ func (h *KnowledgeHandler) ListKnowledgeByProject(c *gin.Context) {
 		return
 	}
 
-	items, err := h.useCase.GetKnowledgeByProject(projectID)
+	page, err := parsePageRequest(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items, err := h.useCase.ListKnowledgeByProject(projectID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, items)
 }
 
-// UpdateKnowledge はナレッジアイテムを更新する
+// UpdateKnowledge はナレッジアイテムの指定された項目だけを更新する
 // @Summary ナレッジ更新
-// @Description ナレッジアイテムを更新する
+// @Description ナレッジアイテムの指定された項目だけを更新する（PUTとPATCHは同じ動作）。取得時の版をIf-Matchヘッダーまたはversionで指定する。質問・回答はdraft/rejectedのアイテムのみ変更でき、それ以外のステータスでは400を返す。
+// @Description 省略した項目は変更しない。project_id・file_id・created_byと担当の割り当ては変更できない。
+// @Description answersを指定した場合は回答バリエーションを置き換える（IDのない回答は追加、含まれない回答は削除）
 // @Tags knowledge
 // @Accept json
 // @Produce json
 // @Param id path int true "ナレッジID"
-// @Param body body domain.KnowledgeItem true "ナレッジアイテム"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body UpdateKnowledgeRequest true "ナレッジ更新リクエスト"
 // @Success 200 {object} domain.KnowledgeItem
 // @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H "版の競合（currentに最新の内容）"
+// @Failure 428 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/knowledge/{id} [put]
+// @Router /api/knowledge/{id} [patch]
 func (h *KnowledgeHandler) UpdateKnowledge(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -190,28 +373,86 @@ func (h *KnowledgeHandler) UpdateKnowledge(c *gin.Context) {
 		return
 	}
 
-	var item domain.KnowledgeItem
-	if err := c.ShouldBindJSON(&item); err != nil {
+	var req UpdateKnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	item.ID = id
-	if err := h.useCase.UpdateKnowledge(&item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 期待する版はIf-Matchを優先し、なければボディのversionを使う
+	expectedVersion := req.Version
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		v, ok := parseKnowledgeETag(ifMatch)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+			return
+		}
+		expectedVersion = v
+	}
+	if expectedVersion <= 0 {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Matchヘッダーまたはversionで取得時の版を指定してください"})
+		return
+	}
+
+	patch := domain.KnowledgePatch{
+		SheetName:          req.SheetName,
+		SourceRange:        req.SourceRange,
+		Question:           req.Question,
+		Answer:             req.Answer,
+		Answers:            req.Answers,
+		DepartmentID:       req.DepartmentID,
+		QuestionGroup:      req.QuestionGroup,
+		ReviewDueAt:        req.ReviewDueAt,
+		ValidUntil:         req.ValidUntil,
+		ReviewIntervalDays: req.ReviewIntervalDays,
+		Status:             req.Status,
+		UpdatedBy:          req.UpdatedBy,
+	}
+
+	item, err := h.useCase.UpdateKnowledge(id, patch, expectedVersion)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
+	setKnowledgeETag(c, item)
 	c.JSON(http.StatusOK, item)
 }
 
-// DeleteKnowledge はナレッジアイテムを削除する
+// setKnowledgeETag はナレッジアイテムの版をETagヘッダーに設定する
+func setKnowledgeETag(c *gin.Context, item *domain.KnowledgeItem) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(item.Version)))
+}
+
+// optionalIfMatch は任意のIf-Matchヘッダーから版を取得する
+// ヘッダーがない場合は0（版を確認しない）を返し、形式が不正な場合はfalseを返す
+func optionalIfMatch(c *gin.Context) (int, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return 0, true
+	}
+	return parseKnowledgeETag(ifMatch)
+}
+
+// parseKnowledgeETag はIf-Matchヘッダーの値から版を取り出す（"3", W/"3", 3 を受け付ける）
+func parseKnowledgeETag(value string) (int, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// DeleteKnowledge はナレッジアイテムをゴミ箱に移す
 // @Summary ナレッジ削除
-// @Description ナレッジアイテムを削除する
+// @Description ナレッジアイテムをゴミ箱に移す。保持期間内であれば /api/trash/knowledge/{id}/restore で復元できる
 // @Tags knowledge
 // @Param id path int true "ナレッジID"
 // @Success 204
 // @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/knowledge/{id} [delete]
 func (h *KnowledgeHandler) DeleteKnowledge(c *gin.Context) {
@@ -222,7 +463,7 @@ func (h *KnowledgeHandler) DeleteKnowledge(c *gin.Context) {
 	}
 
 	if err := h.useCase.DeleteKnowledge(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -231,41 +472,202 @@ func (h *KnowledgeHandler) DeleteKnowledge(c *gin.Context) {
 
 // SearchKnowledge はナレッジアイテムを検索する
 // @Summary ナレッジ検索
-// @Description ナレッジアイテムを検索する
+// @Description ナレッジアイテムを検索し、部門・ステータス・質問グループ・案件・作成年ごとの件数（ファセット）を返す。同じ項目の複数の値はOR、異なる項目はANDで絞り込む
 // @Tags knowledge
 // @Produce json
 // @Param q query string false "検索クエリ"
-// @Param project_id query int false "案件ID"
-// @Param department_id query int false "部門ID"
-// @Param status query string false "ステータス"
-// @Success 200 {array} domain.KnowledgeItem
+// @Param project_id query []int false "案件ID（複数指定可）" collectionFormat(multi)
+// @Param department_id query []int false "部門ID（複数指定可）" collectionFormat(multi)
+// @Param status query []string false "ステータス（複数指定可）" collectionFormat(multi)
+// @Param question_group query []string false "質問グループ（複数指定可）" collectionFormat(multi)
+// @Param created_by query []string false "作成者（複数指定可）" collectionFormat(multi)
+// @Param assignee query []string false "担当者（複数指定可）" collectionFormat(multi)
+// @Param task_state query []string false "担当タスクの状態（todo/in_progress/done、複数指定可）" collectionFormat(multi)
+// @Param tag_id query []int false "タグID（複数指定可。子孫のタグが紐づくアイテムも含む）" collectionFormat(multi)
+// @Param control_id query []int false "統制項目ID（複数指定可。質問グループを通じた紐づけも含む）" collectionFormat(multi)
+// @Param created_from query string false "作成日時の開始（YYYY-MM-DDまたはRFC3339）"
+// @Param created_to query string false "作成日時の終了（日付のみの場合はその日を含む）"
+// @Param updated_from query string false "更新日時の開始（YYYY-MM-DDまたはRFC3339）"
+// @Param updated_to query string false "更新日時の終了（日付のみの場合はその日を含む）"
+// @Param unanswered query bool false "未回答のみ"
+// @Param limit query int false "取得件数（既定50、最大500）"
+// @Param offset query int false "開始位置"
+// @Param sort query string false "並び替え項目（created_at, updated_at, id, question, sheet_name, source_range, status, department_id）"
+// @Param order query string false "並び順（asc, desc）"
+// @Success 200 {object} domain.KnowledgeSearchResult
+// @Failure 400 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/knowledge/search [get]
 func (h *KnowledgeHandler) SearchKnowledge(c *gin.Context) {
 	query := c.Query("q")
-	filters := make(map[string]interface{})
+	filter, err := parseSearchFilters(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
-	if projectIDStr := c.Query("project_id"); projectIDStr != "" {
-		if projectID, err := strconv.Atoi(projectIDStr); err == nil {
-			filters["project_id"] = projectID
-		}
+	page, err := parsePageRequest(c)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	if departmentIDStr := c.Query("department_id"); departmentIDStr != "" {
-		if departmentID, err := strconv.Atoi(departmentIDStr); err == nil {
-			filters["department_id"] = departmentID
-		}
+	result, err := h.useCase.SearchKnowledge(query, filter, page)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	if status := c.Query("status"); status != "" {
-		filters["status"] = status
+	c.JSON(http.StatusOK, result)
+}
+
+// FindSimilarKnowledge は類似質問を検索する
+// @Summary 類似質問検索
+// @Description pg_trgmの類似度で質問が似ているナレッジアイテムをスコア付きで取得する
+// @Tags knowledge
+// @Produce json
+// @Param text query string true "比較するテキスト"
+// @Param threshold query number false "類似度の閾値（0〜1、既定0.3）"
+// @Param exclude_project_id query int false "除外する案件ID（回答中の案件など）"
+// @Param status query string false "ステータス"
+// @Param limit query int false "最大件数（既定20、最大100）"
+// @Success 200 {array} domain.ScoredKnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/similar [get]
+func (h *KnowledgeHandler) FindSimilarKnowledge(c *gin.Context) {
+	opts := domain.SimilarSearchOptions{Status: c.Query("status")}
+
+	if v := c.Query("threshold"); v != "" {
+		threshold, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なthresholdです"})
+			return
+		}
+		opts.Threshold = threshold
+	}
+	if v := c.Query("exclude_project_id"); v != "" {
+		projectID, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なexclude_project_idです"})
+			return
+		}
+		opts.ExcludeProjectID = projectID
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なlimitです"})
+			return
+		}
+		opts.Limit = limit
 	}
 
-	items, err := h.useCase.SearchKnowledge(query, filters)
+	items, err := h.useCase.FindSimilarKnowledge(c.Query("text"), opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, items)
 }
+
+// parseSearchFilters はクエリパラメータから検索フィルタを組み立てる
+// 複数の値は同じキーの繰り返し（department_id=1&department_id=3）またはカンマ区切りで指定する
+func parseSearchFilters(c *gin.Context) (domain.KnowledgeSearchFilter, error) {
+	var filter domain.KnowledgeSearchFilter
+	var err error
+
+	if filter.ProjectIDs, err = queryInts(c, "project_id"); err != nil {
+		return filter, err
+	}
+	if filter.DepartmentIDs, err = queryInts(c, "department_id"); err != nil {
+		return filter, err
+	}
+	filter.Statuses = queryStrings(c, "status")
+	filter.QuestionGroups = queryStrings(c, "question_group")
+	filter.CreatedBy = queryStrings(c, "created_by")
+	filter.Assignees = queryStrings(c, "assignee")
+	filter.TaskStates = queryStrings(c, "task_state")
+	if filter.ControlIDs, err = queryInts(c, "control_id"); err != nil {
+		return filter, err
+	}
+	if filter.TagIDs, err = queryInts(c, "tag_id"); err != nil {
+		return filter, err
+	}
+
+	if filter.CreatedFrom, err = queryTime(c, "created_from", false); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = queryTime(c, "created_to", true); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedFrom, err = queryTime(c, "updated_from", false); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedTo, err = queryTime(c, "updated_to", true); err != nil {
+		return filter, err
+	}
+
+	if v := c.Query("unanswered"); v != "" {
+		unanswered, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, &domain.ValidationError{Field: "unanswered", Message: "unansweredにはtrueまたはfalseを指定してください"}
+		}
+		filter.UnansweredOnly = unanswered
+	}
+
+	return filter, nil
+}
+
+// queryStrings は複数指定されたクエリパラメータを空の値を除いて取得する
+func queryStrings(c *gin.Context, key string) []string {
+	var values []string
+	for _, param := range c.QueryArray(key) {
+		for _, v := range strings.Split(param, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// queryInts は複数指定されたクエリパラメータを整数として取得する
+func queryInts(c *gin.Context, key string) ([]int, error) {
+	var values []int
+	for _, v := range queryStrings(c, key) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, &domain.ValidationError{Field: key, Message: fmt.Sprintf("無効な%sです: %s", key, v)}
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// queryTime は日付（2006-01-02）またはRFC3339形式のクエリパラメータを取得する
+// 範囲の終端（end）に日付のみを指定した場合は、その日を含むよう翌日の0時を返す
+func queryTime(c *gin.Context, key string, end bool) (*time.Time, error) {
+	return parseTimeValue(key, c.Query(key), end)
+}
+
+// parseTimeValue は日付（2006-01-02）またはRFC3339形式の値を解析する（空の場合はnil）
+func parseTimeValue(key string, v string, end bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, &domain.ValidationError{Field: key, Message: fmt.Sprintf("%sには日付（YYYY-MM-DD）またはRFC3339形式の日時を指定してください", key)}
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
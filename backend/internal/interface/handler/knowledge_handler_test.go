@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKnowledgeETag(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   int
+		wantOK bool
+	}{
+		{value: `"3"`, want: 3, wantOK: true},
+		{value: `W/"12"`, want: 12, wantOK: true},
+		{value: `5`, want: 5, wantOK: true},
+		{value: `"abc"`, wantOK: false},
+		{value: `"0"`, wantOK: false},
+		{value: `*`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseKnowledgeETag(tt.value)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
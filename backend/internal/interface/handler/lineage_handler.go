@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// LineageHandler はナレッジの分割・統合に関するHTTPハンドラー
+type LineageHandler struct {
+	useCase usecase.LineageUseCase
+}
+
+// NewLineageHandler は新しいLineageHandlerを生成する
+func NewLineageHandler(useCase usecase.LineageUseCase) *LineageHandler {
+	return &LineageHandler{useCase: useCase}
+}
+
+// SplitKnowledgeRequest はナレッジ分割リクエスト
+type SplitKnowledgeRequest struct {
+	Segments []domain.SplitSegment `json:"segments" binding:"required"`
+	Actor    string                `json:"actor"`
+}
+
+// MergeKnowledgeRequest はナレッジ統合リクエスト
+type MergeKnowledgeRequest struct {
+	IDs []int `json:"ids" binding:"required"`
+	// Question とAnswer は省略時に元の内容を連結する
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+	Actor    string `json:"actor"`
+}
+
+// SplitKnowledge はナレッジアイテムを分割する
+// @Summary ナレッジの分割
+// @Description 複数の質問を含む1つのナレッジアイテムをセグメントごとに分割する。元のアイテムはゴミ箱に移され、派生元として記録される
+// @Tags knowledge
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body SplitKnowledgeRequest true "分割リクエスト"
+// @Success 201 {array} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/split [post]
+func (h *LineageHandler) SplitKnowledge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req SplitKnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	expectedVersion, ok := optionalIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+		return
+	}
+
+	items, err := h.useCase.Split(id, req.Segments, req.Actor, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, items)
+}
+
+// MergeKnowledge は複数のナレッジアイテムを統合する
+// @Summary ナレッジの統合
+// @Description 同じ案件の複数のナレッジアイテムを指定順に1つへ統合する。元のアイテムはゴミ箱に移され、派生元として記録される
+// @Tags knowledge
+// @Accept json
+// @Produce json
+// @Param body body MergeKnowledgeRequest true "統合リクエスト"
+// @Success 201 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/merge [post]
+func (h *LineageHandler) MergeKnowledge(c *gin.Context) {
+	var req MergeKnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	item, err := h.useCase.Merge(req.IDs, req.Question, req.Answer, req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusCreated, item)
+}
+
+// ListLineage はナレッジアイテムの派生元を取得する
+// @Summary ナレッジの派生元
+// @Description 分割・統合で作成されたナレッジアイテムの派生元（元のシート名・範囲・内容）を取得する
+// @Tags knowledge
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {array} domain.KnowledgeLineage
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/lineage [get]
+func (h *LineageHandler) ListLineage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	lineage, err := h.useCase.ListLineage(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lineage)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// parsePageRequest はlimit・offset・sort・orderクエリパラメータからページング条件を読み取る
+// 既定値の設定と並び替え項目の検証はユースケースで行う
+func parsePageRequest(c *gin.Context) (domain.PageRequest, error) {
+	page := domain.PageRequest{
+		Sort:  c.Query("sort"),
+		Order: c.Query("order"),
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return page, &domain.ValidationError{Field: "limit", Message: "無効なlimitです"}
+		}
+		page.Limit = limit
+	}
+
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return page, &domain.ValidationError{Field: "offset", Message: "無効なoffsetです"}
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
@@ -21,17 +21,18 @@ func NewProjectHandler(useCase usecase.ProjectUseCase) *ProjectHandler {
 
 // CreateProjectRequest は案件作成リクエスト
 type CreateProjectRequest struct {
-	CustomerName string `json:"customer_name" binding:"required"`
-	Description  string `json:"description"`
-	Owner        string `json:"owner"`
+	CustomerName string            `json:"customer_name" binding:"required"`
+	Description  string            `json:"description"`
+	Owner        string            `json:"owner"`
+	Variables    map[string]string `json:"variables"`
 }
 
-// UpdateProjectRequest は案件更新リクエスト
+// UpdateProjectRequest は案件更新リクエスト（省略した項目は変更しない）
 type UpdateProjectRequest struct {
-	CustomerName string `json:"customer_name" binding:"required"`
-	Description  string `json:"description"`
-	Owner        string `json:"owner"`
-	Status       string `json:"status"`
+	CustomerName *string `json:"customer_name"`
+	Description  *string `json:"description"`
+	Owner        *string `json:"owner"`
+	Status       *string `json:"status"`
 }
 
 // CreateProject は新規案件を作成する
@@ -53,9 +54,12 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	}
 
 	project := domain.NewProject(req.CustomerName, req.Description, req.Owner)
+	if req.Variables != nil {
+		project.Variables = req.Variables
+	}
 
 	if err := h.useCase.CreateProject(project); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -88,27 +92,38 @@ func (h *ProjectHandler) GetProject(c *gin.Context) {
 	c.JSON(http.StatusOK, project)
 }
 
-// ListProjects はすべての案件を取得する
+// ListProjects は案件の一覧を取得する
 // @Summary 案件一覧取得
-// @Description すべての案件を取得する
+// @Description 案件の一覧をページ単位で取得する
 // @Tags projects
 // @Produce json
-// @Success 200 {array} domain.Project
+// @Param limit query int false "取得件数（既定50、最大500）"
+// @Param offset query int false "開始位置"
+// @Param sort query string false "並び替え項目（created_at, updated_at, customer_name, owner, status, id）"
+// @Param order query string false "並び順（asc, desc）"
+// @Success 200 {object} domain.Page[domain.Project]
+// @Failure 400 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/projects [get]
 func (h *ProjectHandler) ListProjects(c *gin.Context) {
-	projects, err := h.useCase.ListProjects()
+	page, err := parsePageRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	projects, err := h.useCase.ListProjects(page)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, projects)
 }
 
-// UpdateProject は案件情報を更新する
+// UpdateProject は案件情報の指定された項目だけを更新する
 // @Summary 案件更新
-// @Description 案件情報を更新する
+// @Description 案件情報の指定された項目だけを更新する（PUTとPATCHは同じ動作）。省略した項目は変更しない
 // @Tags projects
 // @Accept json
 // @Produce json
@@ -116,8 +131,10 @@ func (h *ProjectHandler) ListProjects(c *gin.Context) {
 // @Param project body UpdateProjectRequest true "案件情報"
 // @Success 200 {object} domain.Project
 // @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/projects/{id} [put]
+// @Router /api/projects/{id} [patch]
 func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -131,29 +148,30 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	project := &domain.Project{
-		ID:           id,
+	patch := domain.ProjectPatch{
 		CustomerName: req.CustomerName,
 		Description:  req.Description,
 		Owner:        req.Owner,
 		Status:       req.Status,
 	}
 
-	if err := h.useCase.UpdateProject(project); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	project, err := h.useCase.UpdateProject(id, patch)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, project)
 }
 
-// DeleteProject は案件を削除する
+// DeleteProject は案件をゴミ箱に移す
 // @Summary 案件削除
-// @Description 案件を削除する
+// @Description 案件を案件のファイル・ナレッジとともにゴミ箱に移す。保持期間内であれば /api/trash/projects/{id}/restore で復元できる
 // @Tags projects
 // @Param id path int true "案件ID"
 // @Success 204
 // @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/projects/{id} [delete]
 func (h *ProjectHandler) DeleteProject(c *gin.Context) {
@@ -164,7 +182,7 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	}
 
 	if err := h.useCase.DeleteProject(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -32,17 +34,20 @@ func (m *MockProjectUseCase) GetProject(id int) (*domain.Project, error) {
 	return args.Get(0).(*domain.Project), args.Error(1)
 }
 
-func (m *MockProjectUseCase) ListProjects() ([]*domain.Project, error) {
-	args := m.Called()
+func (m *MockProjectUseCase) ListProjects(page domain.PageRequest) (*domain.Page[*domain.Project], error) {
+	args := m.Called(page)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*domain.Project), args.Error(1)
+	return args.Get(0).(*domain.Page[*domain.Project]), args.Error(1)
 }
 
-func (m *MockProjectUseCase) UpdateProject(project *domain.Project) error {
-	args := m.Called(project)
-	return args.Error(0)
+func (m *MockProjectUseCase) UpdateProject(id int, patch domain.ProjectPatch) (*domain.Project, error) {
+	args := m.Called(id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Project), args.Error(1)
 }
 
 func (m *MockProjectUseCase) DeleteProject(id int) error {
@@ -81,6 +86,30 @@ func TestProjectHandler_CreateProject(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestProjectHandler_CreateProject_InvalidVariables(t *testing.T) {
+	mockUseCase := new(MockProjectUseCase)
+	handler := NewProjectHandler(mockUseCase)
+
+	router := setupRouter()
+	router.POST("/api/projects", handler.CreateProject)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"customer_name": "テスト株式会社",
+		"variables":     map[string]string{"product name": "SecureBox"},
+	})
+
+	mockUseCase.On("CreateProject", mock.AnythingOfType("*domain.Project")).
+		Return(&domain.ValidationError{Field: "variables", Message: "変数名が不正です: product name"})
+
+	req, _ := http.NewRequest("POST", "/api/projects", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestProjectHandler_CreateProject_InvalidJSON(t *testing.T) {
 	mockUseCase := new(MockProjectUseCase)
 	handler := NewProjectHandler(mockUseCase)
@@ -158,22 +187,41 @@ func TestProjectHandler_ListProjects(t *testing.T) {
 		{ID: 2, CustomerName: "テスト株式会社2", Status: "active"},
 	}
 
-	mockUseCase.On("ListProjects").Return(expectedProjects, nil)
+	page := domain.PageRequest{Limit: 2, Offset: 10, Sort: "customer_name", Order: "asc"}
+	mockUseCase.On("ListProjects", page).Return(domain.NewPage(expectedProjects, 12, page), nil)
 
-	req, _ := http.NewRequest("GET", "/api/projects", nil)
+	req, _ := http.NewRequest("GET", "/api/projects?limit=2&offset=10&sort=customer_name&order=asc", nil)
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response []*domain.Project
+	var response domain.Page[*domain.Project]
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Len(t, response, 2)
+	assert.Len(t, response.Items, 2)
+	assert.Equal(t, 12, response.Total)
+	assert.Equal(t, 10, response.Offset)
 
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestProjectHandler_ListProjects_InvalidLimit(t *testing.T) {
+	mockUseCase := new(MockProjectUseCase)
+	handler := NewProjectHandler(mockUseCase)
+
+	router := setupRouter()
+	router.GET("/api/projects", handler.ListProjects)
+
+	req, _ := http.NewRequest("GET", "/api/projects?limit=abc", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertNotCalled(t, "ListProjects", mock.Anything)
+}
+
 func TestProjectHandler_UpdateProject(t *testing.T) {
 	mockUseCase := new(MockProjectUseCase)
 	handler := NewProjectHandler(mockUseCase)
@@ -189,7 +237,7 @@ func TestProjectHandler_UpdateProject(t *testing.T) {
 	}
 	body, _ := json.Marshal(reqBody)
 
-	mockUseCase.On("UpdateProject", mock.AnythingOfType("*domain.Project")).Return(nil)
+	mockUseCase.On("UpdateProject", 1, mock.AnythingOfType("domain.ProjectPatch")).Return(&domain.Project{ID: 1, CustomerName: "更新株式会社"}, nil)
 
 	req, _ := http.NewRequest("PUT", "/api/projects/1", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -201,6 +249,47 @@ func TestProjectHandler_UpdateProject(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestProjectHandler_PatchProject(t *testing.T) {
+	mockUseCase := new(MockProjectUseCase)
+	handler := NewProjectHandler(mockUseCase)
+
+	router := setupRouter()
+	router.PATCH("/api/projects/:id", handler.UpdateProject)
+
+	// 省略した項目はnilのまま渡し、既存の値を維持させる
+	mockUseCase.On("UpdateProject", 1, mock.MatchedBy(func(patch domain.ProjectPatch) bool {
+		return patch.Status != nil && *patch.Status == "completed" &&
+			patch.CustomerName == nil && patch.Description == nil && patch.Owner == nil
+	})).Return(&domain.Project{ID: 1, CustomerName: "テスト株式会社", Status: "completed"}, nil)
+
+	req, _ := http.NewRequest("PATCH", "/api/projects/1", bytes.NewBufferString(`{"status":"completed"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestProjectHandler_UpdateProject_NotFound(t *testing.T) {
+	mockUseCase := new(MockProjectUseCase)
+	handler := NewProjectHandler(mockUseCase)
+
+	router := setupRouter()
+	router.PATCH("/api/projects/:id", handler.UpdateProject)
+
+	mockUseCase.On("UpdateProject", 999, mock.Anything).Return(nil, fmt.Errorf("案件が存在しません: %w", sql.ErrNoRows))
+
+	req, _ := http.NewRequest("PATCH", "/api/projects/999", bytes.NewBufferString(`{"owner":"山田太郎"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 func TestProjectHandler_DeleteProject(t *testing.T) {
 	mockUseCase := new(MockProjectUseCase)
 	handler := NewProjectHandler(mockUseCase)
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// RecommendationHandler は回答推薦に関するHTTPハンドラー
+type RecommendationHandler struct {
+	useCase usecase.RecommendationUseCase
+}
+
+// NewRecommendationHandler は新しいRecommendationHandlerを生成する
+func NewRecommendationHandler(useCase usecase.RecommendationUseCase) *RecommendationHandler {
+	return &RecommendationHandler{useCase: useCase}
+}
+
+// AcceptRecommendationRequest は推薦候補の採用リクエスト
+type AcceptRecommendationRequest struct {
+	Actor string `json:"actor"`
+}
+
+// GenerateRecommendations は案件の未回答の質問に対する推薦候補を生成する
+// @Summary 回答推薦の生成
+// @Description 案件内の未回答のナレッジアイテム（draft/rejectedのもの）ごとに、他案件の公開済みナレッジから類似度の高い回答候補を保存する
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path int true "案件ID"
+// @Param body body usecase.RecommendationOptions false "生成条件（limit既定3、threshold既定0.3）"
+// @Success 200 {object} usecase.RecommendationResult
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/recommendations [post]
+func (h *RecommendationHandler) GenerateRecommendations(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	var opts usecase.RecommendationOptions
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := h.useCase.GenerateRecommendations(projectID, opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListRecommendations は案件の推薦候補を取得する
+// @Summary 回答推薦の一覧
+// @Description 案件内のナレッジアイテムに対する推薦候補をアイテム・順位順に取得する
+// @Tags recommendations
+// @Produce json
+// @Param id path int true "案件ID"
+// @Success 200 {array} domain.KnowledgeRecommendation
+// @Failure 404 {object} gin.H
+// @Router /api/projects/{id}/recommendations [get]
+func (h *RecommendationHandler) ListRecommendations(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	recommendations, err := h.useCase.ListRecommendations(projectID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
+
+// AcceptRecommendation は推薦候補を採用する
+// @Summary 回答推薦の採用
+// @Description 推薦元の回答を対象のナレッジアイテムにコピーし、コピー元のIDを記録する。推薦の生成後に回答が入力された場合、対象のアイテムがdraft/rejectedでない場合、推薦元が公開済みでなくなった場合は400を返す。推薦元の回答のプレースホルダーが推薦先の案件で解決できない場合も400を返す
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path int true "推薦候補ID"
+// @Param If-Match header string false "対象ナレッジ取得時のETag"
+// @Param body body AcceptRecommendationRequest false "採用リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/recommendations/{id}/accept [post]
+func (h *RecommendationHandler) AcceptRecommendation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req AcceptRecommendationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	expectedVersion, ok := optionalIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+		return
+	}
+
+	item, err := h.useCase.AcceptRecommendation(id, req.Actor, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusOK, item)
+}
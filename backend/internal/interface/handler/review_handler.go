@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// ReviewHandler は回答の有効期限と定期レビューに関するHTTPハンドラー
+type ReviewHandler struct {
+	useCase usecase.ReviewUseCase
+}
+
+// NewReviewHandler は新しいReviewHandlerを生成する
+func NewReviewHandler(useCase usecase.ReviewUseCase) *ReviewHandler {
+	return &ReviewHandler{useCase: useCase}
+}
+
+// ListDueForReview は再レビュー対象のナレッジを部門ごとに取得する
+// @Summary 再レビュー対象のナレッジ一覧
+// @Description needs_reviewのナレッジと、レビュー期限（review_due_at）または有効期限（valid_until）を過ぎたナレッジを部門ごとに期限の早い順で取得する。部門未設定のアイテムは最後のグループにまとめる
+// @Tags review
+// @Produce json
+// @Param within_days query int false "この日数以内に期限を迎えるアイテムも含める（既定0、最大365）"
+// @Param department_id query int false "部門ID"
+// @Success 200 {array} domain.DueForReviewGroup
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/due-for-review [get]
+func (h *ReviewHandler) ListDueForReview(c *gin.Context) {
+	var withinDays int
+	if v := c.Query("within_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なwithin_daysです"})
+			return
+		}
+		withinDays = n
+	}
+
+	var departmentID *int
+	if v := c.Query("department_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なdepartment_idです"})
+			return
+		}
+		departmentID = &n
+	}
+
+	groups, err := h.useCase.ListDueForReview(withinDays, departmentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
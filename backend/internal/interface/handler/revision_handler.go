@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// RevisionHandler はナレッジの変更履歴に関するHTTPハンドラー
+type RevisionHandler struct {
+	useCase usecase.RevisionUseCase
+}
+
+// NewRevisionHandler は新しいRevisionHandlerを生成する
+func NewRevisionHandler(useCase usecase.RevisionUseCase) *RevisionHandler {
+	return &RevisionHandler{useCase: useCase}
+}
+
+// RestoreRevisionRequest は履歴復元リクエスト
+type RestoreRevisionRequest struct {
+	RestoredBy string `json:"restored_by"`
+}
+
+// ListRevisions はナレッジアイテムの変更履歴を取得する
+// @Summary ナレッジ変更履歴一覧
+// @Description 指定されたナレッジアイテムの変更履歴を新しい順に取得する
+// @Tags knowledge
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {array} domain.KnowledgeRevision
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/revisions [get]
+func (h *RevisionHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	revisions, err := h.useCase.ListRevisions(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// DiffRevisions は2つの履歴の差分を取得する
+// @Summary ナレッジ変更履歴の差分
+// @Description 指定された2つの履歴の差分を取得する
+// @Tags knowledge
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param from query int true "比較元の履歴番号"
+// @Param to query int true "比較先の履歴番号"
+// @Success 200 {object} domain.RevisionDiff
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な比較元の履歴番号です"})
+		return
+	}
+
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な比較先の履歴番号です"})
+		return
+	}
+
+	diff, err := h.useCase.DiffRevisions(id, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision はナレッジアイテムを指定された履歴の内容に戻す
+// @Summary ナレッジ履歴の復元
+// @Description 指定された履歴の質問・回答・部門を新しい版として復元する（ステータスは変更しない）。通常の編集と同じく、draft/rejected以外のアイテムや、回答バリエーションがあるアイテムの回答を変える復元は400を返す。If-Matchの版が最新でない場合は409と最新の内容を返す
+// @Tags knowledge
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param rev path int true "履歴番号"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body RestoreRevisionRequest false "復元リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/revisions/{rev}/restore [post]
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な履歴番号です"})
+		return
+	}
+
+	var req RestoreRevisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.RestoredBy == "" {
+		req.RestoredBy = "anonymous"
+	}
+
+	expectedVersion, ok := optionalIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+		return
+	}
+
+	item, err := h.useCase.RestoreRevision(id, rev, req.RestoredBy, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusOK, item)
+}
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// TagHandler はタグ分類に関するHTTPハンドラー
+type TagHandler struct {
+	useCase usecase.TagUseCase
+}
+
+// NewTagHandler は新しいTagHandlerを生成する
+func NewTagHandler(useCase usecase.TagUseCase) *TagHandler {
+	return &TagHandler{useCase: useCase}
+}
+
+// TagRequest はタグの作成・更新リクエスト
+type TagRequest struct {
+	Name        string `json:"name" binding:"required"`
+	ParentID    *int   `json:"parent_id"`
+	Description string `json:"description"`
+}
+
+// SetTagsRequest はナレッジアイテムへのタグの紐づけリクエスト
+type SetTagsRequest struct {
+	TagIDs []int  `json:"tag_ids"`
+	Actor  string `json:"actor"`
+}
+
+// BulkTagRequest は一括タグ付けリクエスト
+type BulkTagRequest struct {
+	KnowledgeIDs []int  `json:"knowledge_ids" binding:"required"`
+	AddTagIDs    []int  `json:"add_tag_ids"`
+	RemoveTagIDs []int  `json:"remove_tag_ids"`
+	Actor        string `json:"actor"`
+}
+
+// MigrateQuestionGroupsRequest は質問グループの移行リクエスト
+type MigrateQuestionGroupsRequest struct {
+	Actor string `json:"actor"`
+}
+
+// CreateTag はタグを作成する
+// @Summary タグ作成
+// @Description タグを作成する。parent_idを指定すると子タグになる
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param body body TagRequest true "タグ"
+// @Success 201 {object} domain.Tag
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag := domain.NewTag(req.Name, req.ParentID, req.Description)
+	if err := h.useCase.CreateTag(tag); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// ListTags はタグの一覧を取得する
+// @Summary タグ一覧
+// @Description タグを親の直後に子が並ぶ階層順で取得する。pathはルートからの階層パス、depthはルートを0とする深さ
+// @Tags tags
+// @Produce json
+// @Success 200 {array} domain.Tag
+// @Failure 500 {object} gin.H
+// @Router /api/tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	tags, err := h.useCase.ListTags()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// GetTag はタグを取得する
+// @Summary タグ詳細
+// @Description タグを取得する
+// @Tags tags
+// @Produce json
+// @Param id path int true "タグID"
+// @Success 200 {object} domain.Tag
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/tags/{id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	tag, err := h.useCase.GetTag(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// UpdateTag はタグを更新する
+// @Summary タグ更新
+// @Description タグの名前・親・説明を更新する。自分自身や子孫のタグを親にはできない
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "タグID"
+// @Param body body TagRequest true "タグ"
+// @Success 200 {object} domain.Tag
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag := &domain.Tag{
+		ID:          id,
+		Name:        req.Name,
+		ParentID:    req.ParentID,
+		Description: req.Description,
+	}
+	if err := h.useCase.UpdateTag(tag); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag はタグを削除する
+// @Summary タグ削除
+// @Description タグを削除する。アイテムとの紐づけも削除される。子タグがある場合は削除できない
+// @Tags tags
+// @Param id path int true "タグID"
+// @Success 204
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	if err := h.useCase.DeleteTag(id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MigrateQuestionGroups は既存の質問グループの値をタグに移行する
+// @Summary 質問グループのタグ移行
+// @Description ナレッジアイテムの質問グループの値をタグに移行し、アイテムに紐づける。表記ゆれは正規化してまとめ、「>」で区切られた値は階層として扱う。すべての値を1トランザクションで移行し、繰り返し実行しても結果は変わらない。質問グループの値は残り、統制項目との対応付けと検索の絞り込みは引き続き質問グループを基準にする
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param body body MigrateQuestionGroupsRequest false "移行リクエスト"
+// @Success 200 {object} domain.QuestionGroupMigrationResult
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/tags/migrate-question-groups [post]
+func (h *TagHandler) MigrateQuestionGroups(c *gin.Context) {
+	var req MigrateQuestionGroupsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	result, err := h.useCase.MigrateQuestionGroups(req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetKnowledgeTags はナレッジアイテムに紐づくタグを取得する
+// @Summary ナレッジのタグ一覧
+// @Description ナレッジアイテムに紐づくタグを取得する
+// @Tags tags
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {array} domain.Tag
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/tags [get]
+func (h *TagHandler) GetKnowledgeTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	tags, err := h.useCase.GetKnowledgeTags(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// SetKnowledgeTags はナレッジアイテムに紐づけるタグを置き換える
+// @Summary ナレッジのタグ設定
+// @Description ナレッジアイテムに紐づけるタグを指定した内容で置き換える。空の配列を指定するとすべて解除する
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param body body SetTagsRequest true "紐づけリクエスト"
+// @Success 200 {array} domain.Tag
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/{id}/tags [put]
+func (h *TagHandler) SetKnowledgeTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req SetTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	tags, err := h.useCase.SetKnowledgeTags(id, req.TagIDs, req.Actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// BulkTag は複数のナレッジアイテムにタグを一括で追加・削除する
+// @Summary 一括タグ付け
+// @Description 複数のナレッジアイテムにタグを一括で追加・削除する。すでに紐づいているタグの追加は無視される
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param body body BulkTagRequest true "一括タグ付けリクエスト"
+// @Success 200 {object} usecase.BulkTagResult
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/knowledge/bulk-tag [post]
+func (h *TagHandler) BulkTag(c *gin.Context) {
+	var req BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	result, err := h.useCase.BulkTag(usecase.BulkTagRequest{
+		KnowledgeIDs: req.KnowledgeIDs,
+		AddTagIDs:    req.AddTagIDs,
+		RemoveTagIDs: req.RemoveTagIDs,
+		Actor:        req.Actor,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// TemplateHandler は回答のプレースホルダーと案件変数に関するHTTPハンドラー
+type TemplateHandler struct {
+	useCase usecase.TemplateUseCase
+}
+
+// NewTemplateHandler は新しいTemplateHandlerを生成する
+func NewTemplateHandler(useCase usecase.TemplateUseCase) *TemplateHandler {
+	return &TemplateHandler{useCase: useCase}
+}
+
+// SetVariablesRequest は案件変数の更新リクエスト
+type SetVariablesRequest struct {
+	Variables map[string]string `json:"variables"`
+}
+
+// RenderAnswerRequest は回答プレビューのリクエスト
+type RenderAnswerRequest struct {
+	Answer string `json:"answer" binding:"required"`
+}
+
+// SetProjectVariables は案件変数を置き換える
+// @Summary 案件変数の設定
+// @Description 回答のプレースホルダー（{{name}}）に埋め込む案件ごとの値を置き換える。customer_nameとownerは案件の項目から解決されるため定義できない
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "案件ID"
+// @Param body body SetVariablesRequest true "案件変数"
+// @Success 200 {object} domain.Project
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/projects/{id}/variables [put]
+func (h *TemplateHandler) SetProjectVariables(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req SetVariablesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.useCase.SetProjectVariables(id, req.Variables)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// RenderAnswer は回答文を案件の値でプレビューする
+// @Summary 回答プレビュー
+// @Description 回答文のプレースホルダーを案件の項目と案件変数で置き換えた結果を返す。解決できないプレースホルダーはunknownに含まれ、置き換えずに残る
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "案件ID"
+// @Param body body RenderAnswerRequest true "回答文"
+// @Success 200 {object} domain.RenderedAnswer
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/projects/{id}/render [post]
+func (h *TemplateHandler) RenderAnswer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req RenderAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rendered, err := h.useCase.RenderAnswer(id, req.Answer)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rendered)
+}
+
+// RenderKnowledge はナレッジアイテムの回答を案件の値でプレビューする
+// @Summary ナレッジ回答のプレビュー
+// @Description ナレッジアイテムの回答のプレースホルダーを指定した案件（省略時はアイテムの案件）の値で置き換えた結果を返す
+// @Tags templates
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param project_id query int false "プレビューする案件ID"
+// @Success 200 {object} domain.RenderedAnswer
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/render [get]
+func (h *TemplateHandler) RenderKnowledge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var projectID int
+	if v := c.Query("project_id"); v != "" {
+		projectID, err = strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無効なproject_idです"})
+			return
+		}
+	}
+
+	rendered, err := h.useCase.RenderKnowledge(id, projectID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rendered)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// TrashHandler はゴミ箱に関するHTTPハンドラー
+type TrashHandler struct {
+	useCase usecase.TrashUseCase
+}
+
+// NewTrashHandler は新しいTrashHandlerを生成する
+func NewTrashHandler(useCase usecase.TrashUseCase) *TrashHandler {
+	return &TrashHandler{useCase: useCase}
+}
+
+// ListTrash はゴミ箱のデータを取得する
+// @Summary ゴミ箱の一覧
+// @Description 削除した案件・ファイル・ナレッジを削除日時の新しい順に、完全に削除される日時（purge_at）とともに取得する。案件と一緒に削除されたファイル・ナレッジは案件の復元で戻すため含めない
+// @Tags trash
+// @Produce json
+// @Success 200 {array} domain.TrashItem
+// @Failure 500 {object} gin.H
+// @Router /api/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	items, err := h.useCase.ListTrash()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// RestoreProject はゴミ箱の案件を復元する
+// @Summary 案件の復元
+// @Description ゴミ箱の案件を、案件と一緒に削除されたファイル・ナレッジとともに復元する。案件より前に個別に削除したものはゴミ箱に残る
+// @Tags trash
+// @Produce json
+// @Param id path int true "案件ID"
+// @Success 200 {object} domain.Project
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/trash/projects/{id}/restore [post]
+func (h *TrashHandler) RestoreProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効な案件IDです"})
+		return
+	}
+
+	project, err := h.useCase.RestoreProject(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// RestoreFile はゴミ箱のファイルを復元する
+// @Summary ファイルの復元
+// @Description ゴミ箱のファイルを復元する。案件がゴミ箱にある場合や、同じ名前のファイルが案件にある場合は復元できない
+// @Tags trash
+// @Produce json
+// @Param id path int true "ファイルID"
+// @Success 200 {object} domain.UploadedFile
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/trash/files/{id}/restore [post]
+func (h *TrashHandler) RestoreFile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なファイルIDです"})
+		return
+	}
+
+	file, err := h.useCase.RestoreFile(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, file)
+}
+
+// RestoreKnowledge はゴミ箱のナレッジアイテムを復元する
+// @Summary ナレッジの復元
+// @Description ゴミ箱のナレッジアイテムを復元する。案件がゴミ箱にある場合は先に案件を復元する
+// @Tags trash
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/trash/knowledge/{id}/restore [post]
+func (h *TrashHandler) RestoreKnowledge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	item, err := h.useCase.RestoreKnowledge(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusOK, item)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/security-checksheets/backend/internal/usecase"
+)
+
+// WorkflowHandler はナレッジのレビュー・承認ワークフローに関するHTTPハンドラー
+type WorkflowHandler struct {
+	useCase usecase.WorkflowUseCase
+}
+
+// NewWorkflowHandler は新しいWorkflowHandlerを生成する
+func NewWorkflowHandler(useCase usecase.WorkflowUseCase) *WorkflowHandler {
+	return &WorkflowHandler{useCase: useCase}
+}
+
+// TransitionRequest はステータス遷移リクエスト
+type TransitionRequest struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+// Submit はレビュー依頼を行う
+// @Summary ナレッジのレビュー依頼
+// @Description draft/rejectedのナレッジをin_reviewにする
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body TransitionRequest false "遷移リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/submit [post]
+func (h *WorkflowHandler) Submit(c *gin.Context) {
+	h.handleTransition(c, func(id int, req TransitionRequest, version int) (*domain.KnowledgeItem, error) {
+		return h.useCase.Submit(id, req.Actor, version)
+	})
+}
+
+// Approve はレビューを承認する
+// @Summary ナレッジの承認
+// @Description in_reviewのナレッジをapprovedにする
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body TransitionRequest false "遷移リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/approve [post]
+func (h *WorkflowHandler) Approve(c *gin.Context) {
+	h.handleTransition(c, func(id int, req TransitionRequest, version int) (*domain.KnowledgeItem, error) {
+		return h.useCase.Approve(id, req.Actor, version)
+	})
+}
+
+// Reject はレビューを差し戻す
+// @Summary ナレッジの差し戻し
+// @Description in_reviewのナレッジを理由付きでrejectedにする
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body TransitionRequest true "遷移リクエスト（reasonは必須）"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/reject [post]
+func (h *WorkflowHandler) Reject(c *gin.Context) {
+	h.handleTransition(c, func(id int, req TransitionRequest, version int) (*domain.KnowledgeItem, error) {
+		return h.useCase.Reject(id, req.Actor, req.Reason, version)
+	})
+}
+
+// Publish は承認済みのナレッジを公開する
+// @Summary ナレッジの公開
+// @Description approvedのナレッジをpublishedにする
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body TransitionRequest false "遷移リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/publish [post]
+func (h *WorkflowHandler) Publish(c *gin.Context) {
+	h.handleTransition(c, func(id int, req TransitionRequest, version int) (*domain.KnowledgeItem, error) {
+		return h.useCase.Publish(id, req.Actor, version)
+	})
+}
+
+// Archive はナレッジをアーカイブする
+// @Summary ナレッジのアーカイブ
+// @Description draft/publishedのナレッジをarchivedにする
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Param If-Match header string false "取得時のETag"
+// @Param body body TransitionRequest false "遷移リクエスト"
+// @Success 200 {object} domain.KnowledgeItem
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Router /api/knowledge/{id}/archive [post]
+func (h *WorkflowHandler) Archive(c *gin.Context) {
+	h.handleTransition(c, func(id int, req TransitionRequest, version int) (*domain.KnowledgeItem, error) {
+		return h.useCase.Archive(id, req.Actor, version)
+	})
+}
+
+// ListTransitions はステータス遷移の履歴を取得する
+// @Summary ナレッジのステータス遷移履歴
+// @Description 誰がいつステータスを変更したかを古い順に取得する
+// @Tags workflow
+// @Produce json
+// @Param id path int true "ナレッジID"
+// @Success 200 {array} domain.StatusTransition
+// @Failure 404 {object} gin.H
+// @Router /api/knowledge/{id}/transitions [get]
+func (h *WorkflowHandler) ListTransitions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	transitions, err := h.useCase.ListTransitions(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
+
+// handleTransition はステータス遷移系エンドポイントの共通処理
+func (h *WorkflowHandler) handleTransition(
+	c *gin.Context,
+	do func(id int, req TransitionRequest, expectedVersion int) (*domain.KnowledgeItem, error),
+) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		return
+	}
+
+	var req TransitionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Actor == "" {
+		req.Actor = "anonymous"
+	}
+
+	expectedVersion, ok := optionalIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無効なIf-Matchヘッダーです"})
+		return
+	}
+
+	item, err := do(id, req, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setKnowledgeETag(c, item)
+	c.JSON(http.StatusOK, item)
+}
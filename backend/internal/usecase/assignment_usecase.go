@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// AssignmentUseCase は質問ごとの担当割り当てと進捗に関するビジネスロジックを提供する
+type AssignmentUseCase interface {
+	// BulkAssign は案件内の選択したアイテム、または部門のアイテムすべてに部門・担当者・期限を一括で割り当てる
+	BulkAssign(projectID int, target domain.AssignmentTarget, change domain.AssignmentChange, actor string) ([]*domain.KnowledgeItem, error)
+	// UpdateTask はアイテムの担当者・期限・タスクの状態を置き換える
+	UpdateTask(id int, assignee string, dueAt *time.Time, taskState string) (*domain.KnowledgeItem, error)
+	// GetProgress は案件の回答状況を部門ごとに集計する
+	GetProgress(projectID int) (*domain.ProjectProgress, error)
+}
+
+// AssignmentUseCaseImpl はAssignmentUseCaseの実装
+type AssignmentUseCaseImpl struct {
+	assignmentRepo domain.KnowledgeAssignmentRepository
+	knowledgeRepo  domain.KnowledgeRepository
+	projectRepo    domain.ProjectRepository
+	departmentRepo domain.DepartmentRepository
+}
+
+// NewAssignmentUseCase は新しいAssignmentUseCaseを生成する
+func NewAssignmentUseCase(
+	assignmentRepo domain.KnowledgeAssignmentRepository,
+	knowledgeRepo domain.KnowledgeRepository,
+	projectRepo domain.ProjectRepository,
+	departmentRepo domain.DepartmentRepository,
+) AssignmentUseCase {
+	return &AssignmentUseCaseImpl{
+		assignmentRepo: assignmentRepo,
+		knowledgeRepo:  knowledgeRepo,
+		projectRepo:    projectRepo,
+		departmentRepo: departmentRepo,
+	}
+}
+
+// BulkAssign は対象のアイテムに部門・担当者・期限を一括で割り当てる
+func (u *AssignmentUseCaseImpl) BulkAssign(projectID int, target domain.AssignmentTarget, change domain.AssignmentChange, actor string) ([]*domain.KnowledgeItem, error) {
+	// 案件の存在確認
+	if _, err := u.projectRepo.GetByID(projectID); err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	target.KnowledgeIDs = uniqueInts(target.KnowledgeIDs)
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	if change.Assignee != nil {
+		assignee := strings.TrimSpace(*change.Assignee)
+		change.Assignee = &assignee
+	}
+	if err := change.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 付与する部門は有効な部門に限る
+	if change.DepartmentID != nil {
+		department, err := u.departmentRepo.GetByID(*change.DepartmentID)
+		if err != nil {
+			return nil, fmt.Errorf("部門が存在しません: %w", err)
+		}
+		if !department.IsActive {
+			return nil, &domain.ValidationError{Field: "department_id", Message: fmt.Sprintf("無効化された部門は付与できません: %s", department.Name)}
+		}
+	}
+
+	items, err := u.assignmentRepo.BulkAssign(projectID, target, change, actor)
+	if err != nil {
+		return nil, fmt.Errorf("担当の割り当てに失敗しました: %w", err)
+	}
+
+	return items, nil
+}
+
+// UpdateTask はアイテムの担当者・期限・タスクの状態を置き換える
+func (u *AssignmentUseCaseImpl) UpdateTask(id int, assignee string, dueAt *time.Time, taskState string) (*domain.KnowledgeItem, error) {
+	item, err := u.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	if err := item.AssignTask(assignee, dueAt, taskState); err != nil {
+		return nil, err
+	}
+
+	if err := u.assignmentRepo.UpdateTask(item); err != nil {
+		return nil, fmt.Errorf("担当の更新に失敗しました: %w", err)
+	}
+
+	return item, nil
+}
+
+// GetProgress は案件の回答状況を部門ごとに集計する
+func (u *AssignmentUseCaseImpl) GetProgress(projectID int) (*domain.ProjectProgress, error) {
+	// 案件の存在確認
+	if _, err := u.projectRepo.GetByID(projectID); err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	departments, err := u.assignmentRepo.GetProgress(projectID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("進捗の集計に失敗しました: %w", err)
+	}
+
+	return domain.SummarizeProgress(projectID, departments), nil
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKnowledgeAssignmentRepository はKnowledgeAssignmentRepositoryのモック
+type MockKnowledgeAssignmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeAssignmentRepository) BulkAssign(projectID int, target domain.AssignmentTarget, change domain.AssignmentChange, actor string) ([]*domain.KnowledgeItem, error) {
+	args := m.Called(projectID, target, change, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeItem), args.Error(1)
+}
+
+func (m *MockKnowledgeAssignmentRepository) UpdateTask(item *domain.KnowledgeItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeAssignmentRepository) GetProgress(projectID int, now time.Time) ([]*domain.DepartmentProgress, error) {
+	args := m.Called(projectID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DepartmentProgress), args.Error(1)
+}
+
+func TestAssignmentUseCase_BulkAssign(t *testing.T) {
+	t.Run("選択したアイテムに部門と担当者を付与する", func(t *testing.T) {
+		assignmentRepo := new(MockKnowledgeAssignmentRepository)
+		projectRepo := new(MockProjectRepository)
+		departmentRepo := new(MockDepartmentRepository)
+		usecase := NewAssignmentUseCase(assignmentRepo, new(MockKnowledgeRepository), projectRepo, departmentRepo)
+
+		departmentID := 3
+		assignee := " 山田 "
+		projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1}, nil)
+		departmentRepo.On("GetByID", 3).Return(&domain.Department{ID: 3, Name: "情報システム部", IsActive: true}, nil)
+		assignmentRepo.On("BulkAssign", 1,
+			domain.AssignmentTarget{KnowledgeIDs: []int{10, 11}},
+			mock.MatchedBy(func(c domain.AssignmentChange) bool {
+				return *c.DepartmentID == 3 && *c.Assignee == "山田" && c.AssignmentDueAt == nil
+			}),
+			"佐藤",
+		).Return([]*domain.KnowledgeItem{{ID: 10}, {ID: 11}}, nil)
+
+		items, err := usecase.BulkAssign(1,
+			domain.AssignmentTarget{KnowledgeIDs: []int{10, 11, 10}},
+			domain.AssignmentChange{DepartmentID: &departmentID, Assignee: &assignee},
+			"佐藤",
+		)
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+		assignmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("無効化された部門は付与できない", func(t *testing.T) {
+		assignmentRepo := new(MockKnowledgeAssignmentRepository)
+		projectRepo := new(MockProjectRepository)
+		departmentRepo := new(MockDepartmentRepository)
+		usecase := NewAssignmentUseCase(assignmentRepo, new(MockKnowledgeRepository), projectRepo, departmentRepo)
+
+		departmentID := 4
+		projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1}, nil)
+		departmentRepo.On("GetByID", 4).Return(&domain.Department{ID: 4, Name: "旧総務部", IsActive: false}, nil)
+
+		_, err := usecase.BulkAssign(1, domain.AssignmentTarget{KnowledgeIDs: []int{10}}, domain.AssignmentChange{DepartmentID: &departmentID}, "佐藤")
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assignmentRepo.AssertNotCalled(t, "BulkAssign", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("対象の指定がない場合はエラー", func(t *testing.T) {
+		assignmentRepo := new(MockKnowledgeAssignmentRepository)
+		projectRepo := new(MockProjectRepository)
+		usecase := NewAssignmentUseCase(assignmentRepo, new(MockKnowledgeRepository), projectRepo, new(MockDepartmentRepository))
+
+		assignee := "山田"
+		projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1}, nil)
+
+		_, err := usecase.BulkAssign(1, domain.AssignmentTarget{}, domain.AssignmentChange{Assignee: &assignee}, "佐藤")
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestAssignmentUseCase_UpdateTask(t *testing.T) {
+	assignmentRepo := new(MockKnowledgeAssignmentRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewAssignmentUseCase(assignmentRepo, knowledgeRepo, new(MockProjectRepository), new(MockDepartmentRepository))
+
+	dueAt := time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC)
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10, Version: 3, TaskState: domain.TaskTodo}, nil)
+	assignmentRepo.On("UpdateTask", mock.AnythingOfType("*domain.KnowledgeItem")).Return(nil)
+
+	item, err := usecase.UpdateTask(10, "山田", &dueAt, domain.TaskInProgress)
+	require.NoError(t, err)
+
+	assert.Equal(t, "山田", item.Assignee)
+	assert.Equal(t, &dueAt, item.AssignmentDueAt)
+	assert.Equal(t, domain.TaskInProgress, item.TaskState)
+	// 割り当ては版を進めない
+	assert.Equal(t, 3, item.Version)
+}
+
+func TestAssignmentUseCase_GetProgress(t *testing.T) {
+	assignmentRepo := new(MockKnowledgeAssignmentRepository)
+	projectRepo := new(MockProjectRepository)
+	usecase := NewAssignmentUseCase(assignmentRepo, new(MockKnowledgeRepository), projectRepo, new(MockDepartmentRepository))
+
+	departmentID := 3
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1}, nil)
+	assignmentRepo.On("GetProgress", 1, mock.AnythingOfType("time.Time")).Return([]*domain.DepartmentProgress{
+		{DepartmentID: &departmentID, DepartmentName: "情報システム部", ProgressCounts: domain.ProgressCounts{Total: 4, Answered: 3, Approved: 2}},
+		{ProgressCounts: domain.ProgressCounts{Total: 2, Answered: 1}},
+	}, nil)
+
+	progress, err := usecase.GetProgress(1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, progress.Total)
+	assert.Equal(t, 4, progress.Answered)
+	assert.Equal(t, 2, progress.Unanswered)
+	assert.Equal(t, 2, progress.Approved)
+	require.Len(t, progress.Departments, 2)
+	assert.Equal(t, domain.UnassignedDepartmentName, progress.Departments[1].DepartmentName)
+}
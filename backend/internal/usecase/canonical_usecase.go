@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// CanonicalUseCase は標準質問ライブラリに関するビジネスロジックを提供する
+type CanonicalUseCase interface {
+	CreateCanonicalQuestion(cq *domain.CanonicalQuestion) error
+	GetCanonicalQuestion(id int) (*domain.CanonicalQuestion, error)
+	ListCanonicalQuestions(departmentID int) ([]*domain.CanonicalQuestion, error)
+	UpdateCanonicalQuestion(cq *domain.CanonicalQuestion) error
+	DeleteCanonicalQuestion(id int) error
+	ListVariants(id int) ([]*domain.KnowledgeItem, error)
+	// LinkKnowledge とUnlinkKnowledge はexpectedVersionに0を指定した場合は版の確認を行わない
+	LinkKnowledge(knowledgeID int, canonicalQuestionID int, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+	UnlinkKnowledge(knowledgeID int, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+}
+
+// CanonicalUseCaseImpl はCanonicalUseCaseの実装
+type CanonicalUseCaseImpl struct {
+	canonicalRepo domain.CanonicalQuestionRepository
+	knowledgeRepo domain.KnowledgeRepository
+}
+
+// NewCanonicalUseCase は新しいCanonicalUseCaseを生成する
+func NewCanonicalUseCase(
+	canonicalRepo domain.CanonicalQuestionRepository,
+	knowledgeRepo domain.KnowledgeRepository,
+) CanonicalUseCase {
+	return &CanonicalUseCaseImpl{
+		canonicalRepo: canonicalRepo,
+		knowledgeRepo: knowledgeRepo,
+	}
+}
+
+// CreateCanonicalQuestion は標準質問を作成する
+func (u *CanonicalUseCaseImpl) CreateCanonicalQuestion(cq *domain.CanonicalQuestion) error {
+	if err := cq.Validate(); err != nil {
+		return err
+	}
+
+	return u.canonicalRepo.Create(cq)
+}
+
+// GetCanonicalQuestion は標準質問を取得する
+func (u *CanonicalUseCaseImpl) GetCanonicalQuestion(id int) (*domain.CanonicalQuestion, error) {
+	return u.canonicalRepo.GetByID(id)
+}
+
+// ListCanonicalQuestions は標準質問の一覧を取得する
+func (u *CanonicalUseCaseImpl) ListCanonicalQuestions(departmentID int) ([]*domain.CanonicalQuestion, error) {
+	return u.canonicalRepo.GetAll(departmentID)
+}
+
+// UpdateCanonicalQuestion は標準質問を更新する
+// 標準回答が変わった場合、紐づくアイテムのうち回答が異なるものに乖離フラグが付く
+func (u *CanonicalUseCaseImpl) UpdateCanonicalQuestion(cq *domain.CanonicalQuestion) error {
+	current, err := u.canonicalRepo.GetByID(cq.ID)
+	if err != nil {
+		return fmt.Errorf("標準質問が存在しません: %w", err)
+	}
+
+	if err := cq.Validate(); err != nil {
+		return err
+	}
+
+	cq.CreatedBy = current.CreatedBy
+	cq.CreatedAt = current.CreatedAt
+	cq.UpdatedAt = time.Now()
+
+	return u.canonicalRepo.Update(cq)
+}
+
+// DeleteCanonicalQuestion は標準質問を削除する。紐づくアイテムは紐づけが解除される
+func (u *CanonicalUseCaseImpl) DeleteCanonicalQuestion(id int) error {
+	// 存在確認
+	if _, err := u.canonicalRepo.GetByID(id); err != nil {
+		return fmt.Errorf("標準質問が存在しません: %w", err)
+	}
+
+	return u.canonicalRepo.Delete(id)
+}
+
+// ListVariants は標準質問に紐づく案件ごとの表現（ナレッジアイテム）を取得する
+func (u *CanonicalUseCaseImpl) ListVariants(id int) ([]*domain.KnowledgeItem, error) {
+	// 存在確認
+	if _, err := u.canonicalRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("標準質問が存在しません: %w", err)
+	}
+
+	return u.canonicalRepo.GetVariants(id)
+}
+
+// LinkKnowledge はナレッジアイテムを標準質問に紐づける
+func (u *CanonicalUseCaseImpl) LinkKnowledge(knowledgeID int, canonicalQuestionID int, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	if _, err := u.canonicalRepo.GetByID(canonicalQuestionID); err != nil {
+		return nil, fmt.Errorf("標準質問が存在しません: %w", err)
+	}
+
+	return u.updateLink(knowledgeID, expectedVersion, func(item *domain.KnowledgeItem) {
+		item.LinkCanonicalQuestion(canonicalQuestionID, actor)
+	})
+}
+
+// UnlinkKnowledge はナレッジアイテムと標準質問の紐づけを解除する
+func (u *CanonicalUseCaseImpl) UnlinkKnowledge(knowledgeID int, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	return u.updateLink(knowledgeID, expectedVersion, func(item *domain.KnowledgeItem) {
+		item.UnlinkCanonicalQuestion(actor)
+	})
+}
+
+// updateLink はナレッジアイテムの紐づけを変更して保存する
+func (u *CanonicalUseCaseImpl) updateLink(knowledgeID int, expectedVersion int, change func(item *domain.KnowledgeItem)) (*domain.KnowledgeItem, error) {
+	item, err := u.knowledgeRepo.GetByID(knowledgeID)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+	if expectedVersion != 0 && item.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{Current: item}
+	}
+
+	loadedVersion := item.Version
+	change(item)
+
+	if err := u.knowledgeRepo.Update(item, loadedVersion); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			current, getErr := u.knowledgeRepo.GetByID(knowledgeID)
+			if getErr != nil {
+				return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", getErr)
+			}
+			return nil, &domain.VersionConflictError{Current: current}
+		}
+		return nil, fmt.Errorf("標準質問の紐づけに失敗しました: %w", err)
+	}
+
+	return item, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCanonicalQuestionRepository はCanonicalQuestionRepositoryのモック
+type MockCanonicalQuestionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCanonicalQuestionRepository) Create(cq *domain.CanonicalQuestion) error {
+	args := m.Called(cq)
+	return args.Error(0)
+}
+
+func (m *MockCanonicalQuestionRepository) GetByID(id int) (*domain.CanonicalQuestion, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CanonicalQuestion), args.Error(1)
+}
+
+func (m *MockCanonicalQuestionRepository) GetAll(departmentID int) ([]*domain.CanonicalQuestion, error) {
+	args := m.Called(departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CanonicalQuestion), args.Error(1)
+}
+
+func (m *MockCanonicalQuestionRepository) Update(cq *domain.CanonicalQuestion) error {
+	args := m.Called(cq)
+	return args.Error(0)
+}
+
+func (m *MockCanonicalQuestionRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCanonicalQuestionRepository) GetVariants(id int) ([]*domain.KnowledgeItem, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeItem), args.Error(1)
+}
+
+func TestCanonicalUseCase_CreateCanonicalQuestion_ValidationError(t *testing.T) {
+	canonicalRepo := new(MockCanonicalQuestionRepository)
+	usecase := NewCanonicalUseCase(canonicalRepo, new(MockKnowledgeRepository))
+
+	var validationErr *domain.ValidationError
+	err := usecase.CreateCanonicalQuestion(domain.NewCanonicalQuestion("  ", "はい", nil, "山田太郎"))
+	assert.ErrorAs(t, err, &validationErr)
+	canonicalRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCanonicalUseCase_UpdateCanonicalQuestion(t *testing.T) {
+	canonicalRepo := new(MockCanonicalQuestionRepository)
+	usecase := NewCanonicalUseCase(canonicalRepo, new(MockKnowledgeRepository))
+
+	current := domain.NewCanonicalQuestion("多要素認証を強制していますか？", "はい", nil, "佐藤花子")
+	current.ID = 4
+	canonicalRepo.On("GetByID", 4).Return(current, nil)
+	canonicalRepo.On("Update", mock.AnythingOfType("*domain.CanonicalQuestion")).Return(nil)
+
+	cq := &domain.CanonicalQuestion{ID: 4, Question: "多要素認証を強制していますか？", MasterAnswer: "はい。全従業員に強制しています", UpdatedBy: "山田太郎"}
+	require.NoError(t, usecase.UpdateCanonicalQuestion(cq))
+	// 作成者は変更されない
+	assert.Equal(t, "佐藤花子", cq.CreatedBy)
+	canonicalRepo.AssertExpectations(t)
+}
+
+func TestCanonicalUseCase_LinkKnowledge(t *testing.T) {
+	canonicalRepo := new(MockCanonicalQuestionRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewCanonicalUseCase(canonicalRepo, knowledgeRepo)
+
+	item := &domain.KnowledgeItem{ID: 10, ProjectID: 1, Question: "MFAを使用していますか？", Version: 2}
+	canonicalRepo.On("GetByID", 4).Return(&domain.CanonicalQuestion{ID: 4}, nil)
+	knowledgeRepo.On("GetByID", 10).Return(item, nil)
+	knowledgeRepo.On("Update", item, 2).Return(nil)
+
+	linked, err := usecase.LinkKnowledge(10, 4, "山田太郎", 2)
+	require.NoError(t, err)
+	require.NotNil(t, linked.CanonicalQuestionID)
+	assert.Equal(t, 4, *linked.CanonicalQuestionID)
+	assert.Equal(t, 3, linked.Version)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestCanonicalUseCase_LinkKnowledge_Errors(t *testing.T) {
+	canonicalRepo := new(MockCanonicalQuestionRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewCanonicalUseCase(canonicalRepo, knowledgeRepo)
+
+	canonicalRepo.On("GetByID", 99).Return(nil, sql.ErrNoRows)
+	_, err := usecase.LinkKnowledge(10, 99, "山田太郎", 0)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	canonicalRepo.On("GetByID", 4).Return(&domain.CanonicalQuestion{ID: 4}, nil)
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10, Version: 3}, nil)
+	var conflictErr *domain.VersionConflictError
+	_, err = usecase.LinkKnowledge(10, 4, "山田太郎", 2)
+	assert.ErrorAs(t, err, &conflictErr)
+
+	knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCanonicalUseCase_UnlinkKnowledge(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewCanonicalUseCase(new(MockCanonicalQuestionRepository), knowledgeRepo)
+
+	canonicalID := 4
+	item := &domain.KnowledgeItem{ID: 10, ProjectID: 1, CanonicalQuestionID: &canonicalID, AnswerDrifted: true, Version: 2}
+	knowledgeRepo.On("GetByID", 10).Return(item, nil)
+	knowledgeRepo.On("Update", item, 2).Return(nil)
+
+	unlinked, err := usecase.UnlinkKnowledge(10, "山田太郎", 0)
+	require.NoError(t, err)
+	assert.Nil(t, unlinked.CanonicalQuestionID)
+	knowledgeRepo.AssertExpectations(t)
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// CommentUseCase はナレッジアイテムのコメントスレッドに関するビジネスロジックを提供する
+type CommentUseCase interface {
+	// AddComment はコメントを投稿する。解決済みのスレッドは未解決に戻る
+	AddComment(knowledgeID int, author string, body string) (*domain.KnowledgeComment, error)
+	// EditComment は投稿者によるコメントの編集を行う
+	EditComment(id int, actor string, body string) (*domain.KnowledgeComment, error)
+	// DeleteComment は投稿者によるコメントの削除を行う
+	DeleteComment(id int, actor string) error
+	GetThread(knowledgeID int) (*domain.CommentThread, error)
+	ResolveThread(knowledgeID int, actor string) (*domain.CommentThread, error)
+	ReopenThread(knowledgeID int) (*domain.CommentThread, error)
+	// ListOpenThreads は案件内の未解決のスレッドを取得する
+	ListOpenThreads(projectID int, filter domain.OpenThreadFilter) ([]*domain.OpenThread, error)
+}
+
+// CommentUseCaseImpl はCommentUseCaseの実装
+type CommentUseCaseImpl struct {
+	commentRepo    domain.CommentRepository
+	knowledgeRepo  domain.KnowledgeRepository
+	projectRepo    domain.ProjectRepository
+	departmentRepo domain.DepartmentRepository
+}
+
+// NewCommentUseCase は新しいCommentUseCaseを生成する
+func NewCommentUseCase(
+	commentRepo domain.CommentRepository,
+	knowledgeRepo domain.KnowledgeRepository,
+	projectRepo domain.ProjectRepository,
+	departmentRepo domain.DepartmentRepository,
+) CommentUseCase {
+	return &CommentUseCaseImpl{
+		commentRepo:    commentRepo,
+		knowledgeRepo:  knowledgeRepo,
+		projectRepo:    projectRepo,
+		departmentRepo: departmentRepo,
+	}
+}
+
+// AddComment はコメントを投稿する
+func (u *CommentUseCaseImpl) AddComment(knowledgeID int, author string, body string) (*domain.KnowledgeComment, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	comment := domain.NewKnowledgeComment(knowledgeID, author, body)
+	if err := comment.Validate(); err != nil {
+		return nil, err
+	}
+	if err := u.parseMentions(comment); err != nil {
+		return nil, err
+	}
+
+	if err := u.commentRepo.Create(comment); err != nil {
+		return nil, fmt.Errorf("コメントの保存に失敗しました: %w", err)
+	}
+
+	return comment, nil
+}
+
+// EditComment は投稿者によるコメントの編集を行い、メンションを付け直す
+func (u *CommentUseCaseImpl) EditComment(id int, actor string, body string) (*domain.KnowledgeComment, error) {
+	comment, err := u.commentRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("コメントが存在しません: %w", err)
+	}
+
+	if err := comment.Edit(actor, body); err != nil {
+		return nil, err
+	}
+	if err := u.parseMentions(comment); err != nil {
+		return nil, err
+	}
+
+	if err := u.commentRepo.Update(comment); err != nil {
+		return nil, fmt.Errorf("コメントの更新に失敗しました: %w", err)
+	}
+
+	return comment, nil
+}
+
+// DeleteComment は投稿者によるコメントの削除を行う
+func (u *CommentUseCaseImpl) DeleteComment(id int, actor string) error {
+	comment, err := u.commentRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("コメントが存在しません: %w", err)
+	}
+
+	if err := comment.CanDelete(actor); err != nil {
+		return err
+	}
+
+	if err := u.commentRepo.Delete(id); err != nil {
+		return fmt.Errorf("コメントの削除に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// GetThread はアイテムのコメントスレッドを取得する
+func (u *CommentUseCaseImpl) GetThread(knowledgeID int) (*domain.CommentThread, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	return u.commentRepo.GetThread(knowledgeID)
+}
+
+// ResolveThread はスレッドを解決済みにする。コメントのないスレッドは解決できない
+func (u *CommentUseCaseImpl) ResolveThread(knowledgeID int, actor string) (*domain.CommentThread, error) {
+	thread, err := u.GetThread(knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(thread.Comments) == 0 {
+		return nil, &domain.ValidationError{Field: "comments", Message: "コメントのないスレッドは解決できません"}
+	}
+
+	if err := u.commentRepo.SetResolved(knowledgeID, true, actor, time.Now()); err != nil {
+		return nil, fmt.Errorf("スレッドの更新に失敗しました: %w", err)
+	}
+
+	return u.commentRepo.GetThread(knowledgeID)
+}
+
+// ReopenThread は解決済みのスレッドを未解決に戻す
+func (u *CommentUseCaseImpl) ReopenThread(knowledgeID int) (*domain.CommentThread, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	if err := u.commentRepo.SetResolved(knowledgeID, false, "", time.Now()); err != nil {
+		return nil, fmt.Errorf("スレッドの更新に失敗しました: %w", err)
+	}
+
+	return u.commentRepo.GetThread(knowledgeID)
+}
+
+// ListOpenThreads は案件内の未解決のスレッドを取得する
+func (u *CommentUseCaseImpl) ListOpenThreads(projectID int, filter domain.OpenThreadFilter) ([]*domain.OpenThread, error) {
+	// 案件の存在確認
+	if _, err := u.projectRepo.GetByID(projectID); err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	return u.commentRepo.GetOpenThreads(projectID, filter)
+}
+
+// parseMentions は本文からメンションを取り出してコメントに設定する
+func (u *CommentUseCaseImpl) parseMentions(comment *domain.KnowledgeComment) error {
+	departments, err := u.departmentRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("部門の取得に失敗しました: %w", err)
+	}
+
+	comment.Mentions = domain.ParseMentions(comment.Body, departments)
+	return nil
+}
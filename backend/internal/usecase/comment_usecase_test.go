@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCommentRepository はCommentRepositoryのモック
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(comment *domain.KnowledgeComment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetByID(id int) (*domain.KnowledgeComment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.KnowledgeComment), args.Error(1)
+}
+
+func (m *MockCommentRepository) Update(comment *domain.KnowledgeComment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetThread(knowledgeID int) (*domain.CommentThread, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CommentThread), args.Error(1)
+}
+
+func (m *MockCommentRepository) SetResolved(knowledgeID int, resolved bool, actor string, at time.Time) error {
+	args := m.Called(knowledgeID, resolved, actor, at)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetOpenThreads(projectID int, filter domain.OpenThreadFilter) ([]*domain.OpenThread, error) {
+	args := m.Called(projectID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.OpenThread), args.Error(1)
+}
+
+func TestCommentUseCase_AddComment(t *testing.T) {
+	commentRepo := new(MockCommentRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	departmentRepo := new(MockDepartmentRepository)
+	usecase := NewCommentUseCase(commentRepo, knowledgeRepo, new(MockProjectRepository), departmentRepo)
+
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	departmentRepo.On("GetAll").Return([]*domain.Department{{ID: 3, Name: "情報システム部"}}, nil)
+	commentRepo.On("Create", mock.AnythingOfType("*domain.KnowledgeComment")).Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.KnowledgeComment).ID = 1
+	}).Return(nil)
+
+	comment, err := usecase.AddComment(10, "山田", "@情報システム部 @佐藤 ログの保管期間を確認してください")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, comment.ID)
+	assert.Equal(t, "山田", comment.Author)
+	require.Len(t, comment.Mentions, 2)
+	assert.Equal(t, domain.MentionDepartment, comment.Mentions[0].Type)
+	assert.Equal(t, 3, *comment.Mentions[0].DepartmentID)
+	assert.Equal(t, domain.MentionUser, comment.Mentions[1].Type)
+	assert.Equal(t, "佐藤", comment.Mentions[1].UserName)
+	commentRepo.AssertExpectations(t)
+}
+
+func TestCommentUseCase_EditComment(t *testing.T) {
+	t.Run("投稿者はメンションを付け直して編集できる", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		departmentRepo := new(MockDepartmentRepository)
+		usecase := NewCommentUseCase(commentRepo, new(MockKnowledgeRepository), new(MockProjectRepository), departmentRepo)
+
+		existing := domain.NewKnowledgeComment(10, "山田", "@佐藤 確認してください")
+		existing.ID = 1
+		existing.Mentions = []*domain.CommentMention{{Type: domain.MentionUser, UserName: "佐藤"}}
+		commentRepo.On("GetByID", 1).Return(existing, nil)
+		departmentRepo.On("GetAll").Return([]*domain.Department{}, nil)
+		commentRepo.On("Update", existing).Return(nil)
+
+		comment, err := usecase.EditComment(1, "山田", "@鈴木 確認してください")
+		require.NoError(t, err)
+
+		assert.True(t, comment.Edited)
+		require.Len(t, comment.Mentions, 1)
+		assert.Equal(t, "鈴木", comment.Mentions[0].UserName)
+	})
+
+	t.Run("投稿者以外は編集できない", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		usecase := NewCommentUseCase(commentRepo, new(MockKnowledgeRepository), new(MockProjectRepository), new(MockDepartmentRepository))
+
+		commentRepo.On("GetByID", 1).Return(&domain.KnowledgeComment{ID: 1, Author: "山田", Body: "確認中"}, nil)
+
+		_, err := usecase.EditComment(1, "佐藤", "確認しました")
+		assert.ErrorIs(t, err, domain.ErrNotCommentAuthor)
+		commentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestCommentUseCase_DeleteComment_NotAuthor(t *testing.T) {
+	commentRepo := new(MockCommentRepository)
+	usecase := NewCommentUseCase(commentRepo, new(MockKnowledgeRepository), new(MockProjectRepository), new(MockDepartmentRepository))
+
+	commentRepo.On("GetByID", 1).Return(&domain.KnowledgeComment{ID: 1, Author: "山田"}, nil)
+
+	err := usecase.DeleteComment(1, "anonymous")
+	assert.ErrorIs(t, err, domain.ErrNotCommentAuthor)
+	commentRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestCommentUseCase_ResolveThread(t *testing.T) {
+	t.Run("コメントのあるスレッドを解決する", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		knowledgeRepo := new(MockKnowledgeRepository)
+		usecase := NewCommentUseCase(commentRepo, knowledgeRepo, new(MockProjectRepository), new(MockDepartmentRepository))
+
+		knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+		commentRepo.On("GetThread", 10).Return(&domain.CommentThread{
+			KnowledgeItemID: 10,
+			Comments:        []*domain.KnowledgeComment{{ID: 1}},
+		}, nil)
+		commentRepo.On("SetResolved", 10, true, "山田", mock.AnythingOfType("time.Time")).Return(nil)
+
+		_, err := usecase.ResolveThread(10, "山田")
+		require.NoError(t, err)
+		commentRepo.AssertExpectations(t)
+	})
+
+	t.Run("コメントのないスレッドは解決できない", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		knowledgeRepo := new(MockKnowledgeRepository)
+		usecase := NewCommentUseCase(commentRepo, knowledgeRepo, new(MockProjectRepository), new(MockDepartmentRepository))
+
+		knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+		commentRepo.On("GetThread", 10).Return(&domain.CommentThread{KnowledgeItemID: 10, Comments: []*domain.KnowledgeComment{}}, nil)
+
+		_, err := usecase.ResolveThread(10, "山田")
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		commentRepo.AssertNotCalled(t, "SetResolved", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCommentUseCase_ListOpenThreads_ProjectNotFound(t *testing.T) {
+	commentRepo := new(MockCommentRepository)
+	projectRepo := new(MockProjectRepository)
+	usecase := NewCommentUseCase(commentRepo, new(MockKnowledgeRepository), projectRepo, new(MockDepartmentRepository))
+
+	projectRepo.On("GetByID", 99).Return(nil, sql.ErrNoRows)
+
+	_, err := usecase.ListOpenThreads(99, domain.OpenThreadFilter{})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	commentRepo.AssertNotCalled(t, "GetOpenThreads", mock.Anything, mock.Anything)
+}
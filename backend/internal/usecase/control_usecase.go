@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// 統制カタログのファイル形式
+const (
+	CatalogFormatJSON = "json"
+	CatalogFormatCSV  = "csv"
+)
+
+// ControlUseCase は統制フレームワークとナレッジの紐づけに関するビジネスロジックを提供する
+type ControlUseCase interface {
+	// LoadCatalog はJSONまたはCSVのカタログを読み込み、フレームワークと統制項目を登録・更新する
+	LoadCatalog(r io.Reader, format string) ([]*domain.ControlFramework, error)
+	ListFrameworks() ([]*domain.ControlFramework, error)
+	ListControls(frameworkCode string) ([]*domain.Control, error)
+	// GetCoverage はprojectIDに0を指定した場合はすべての案件のアイテムを集計する
+	GetCoverage(frameworkCode string, prefix string, projectID int) (*domain.ControlCoverageReport, error)
+	GetKnowledgeControls(knowledgeID int) ([]*domain.KnowledgeControl, error)
+	SetKnowledgeControls(knowledgeID int, controlIDs []int, actor string) ([]*domain.KnowledgeControl, error)
+	GetQuestionGroupControls(questionGroup string) ([]*domain.Control, error)
+	SetQuestionGroupControls(questionGroup string, controlIDs []int, actor string) ([]*domain.Control, error)
+}
+
+// ControlUseCaseImpl はControlUseCaseの実装
+type ControlUseCaseImpl struct {
+	controlRepo   domain.ControlRepository
+	knowledgeRepo domain.KnowledgeRepository
+	projectRepo   domain.ProjectRepository
+}
+
+// NewControlUseCase は新しいControlUseCaseを生成する
+func NewControlUseCase(
+	controlRepo domain.ControlRepository,
+	knowledgeRepo domain.KnowledgeRepository,
+	projectRepo domain.ProjectRepository,
+) ControlUseCase {
+	return &ControlUseCaseImpl{
+		controlRepo:   controlRepo,
+		knowledgeRepo: knowledgeRepo,
+		projectRepo:   projectRepo,
+	}
+}
+
+// LoadCatalog はカタログを読み込み、フレームワークと統制項目を登録・更新する
+func (u *ControlUseCaseImpl) LoadCatalog(r io.Reader, format string) ([]*domain.ControlFramework, error) {
+	var frameworks []*domain.ControlFramework
+	var err error
+	switch format {
+	case CatalogFormatJSON:
+		frameworks, err = parseControlCatalogJSON(r)
+	case CatalogFormatCSV:
+		frameworks, err = parseControlCatalogCSV(r)
+	default:
+		return nil, &domain.ValidationError{Field: "file", Message: "カタログはJSONまたはCSVで指定してください"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(frameworks) == 0 {
+		return nil, &domain.ValidationError{Field: "file", Message: "カタログにフレームワークがありません"}
+	}
+	for _, f := range frameworks {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.controlRepo.UpsertCatalog(frameworks); err != nil {
+		return nil, fmt.Errorf("統制カタログの登録に失敗しました: %w", err)
+	}
+
+	return frameworks, nil
+}
+
+// ListFrameworks はフレームワークの一覧を取得する
+func (u *ControlUseCaseImpl) ListFrameworks() ([]*domain.ControlFramework, error) {
+	return u.controlRepo.GetFrameworks()
+}
+
+// ListControls はフレームワークの統制項目を取得する
+func (u *ControlUseCaseImpl) ListControls(frameworkCode string) ([]*domain.Control, error) {
+	framework, err := u.controlRepo.GetFrameworkByCode(frameworkCode)
+	if err != nil {
+		return nil, fmt.Errorf("フレームワークが存在しません: %w", err)
+	}
+
+	return u.controlRepo.GetControls(framework.ID)
+}
+
+// GetCoverage はフレームワークの統制項目に承認済み・公開済みの回答があるかを集計する
+func (u *ControlUseCaseImpl) GetCoverage(frameworkCode string, prefix string, projectID int) (*domain.ControlCoverageReport, error) {
+	framework, err := u.controlRepo.GetFrameworkByCode(frameworkCode)
+	if err != nil {
+		return nil, fmt.Errorf("フレームワークが存在しません: %w", err)
+	}
+
+	if projectID != 0 {
+		// 案件の存在確認
+		if _, err := u.projectRepo.GetByID(projectID); err != nil {
+			return nil, fmt.Errorf("案件が存在しません: %w", err)
+		}
+	}
+
+	// 区切り単位で一致させるため、末尾の区切り（A.9.など）は取り除く
+	prefix = strings.TrimRight(strings.TrimSpace(prefix), ".")
+	coverage, err := u.controlRepo.GetCoverage(framework.ID, prefix, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("統制項目の集計に失敗しました: %w", err)
+	}
+
+	return domain.NewControlCoverageReport(framework, prefix, projectID, coverage), nil
+}
+
+// GetKnowledgeControls はナレッジアイテムに紐づく統制項目を取得する
+func (u *ControlUseCaseImpl) GetKnowledgeControls(knowledgeID int) ([]*domain.KnowledgeControl, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	return u.controlRepo.GetByKnowledgeID(knowledgeID)
+}
+
+// SetKnowledgeControls はナレッジアイテムに直接紐づける統制項目を置き換える
+func (u *ControlUseCaseImpl) SetKnowledgeControls(knowledgeID int, controlIDs []int, actor string) ([]*domain.KnowledgeControl, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	ids, err := u.validateControlIDs(controlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.controlRepo.ReplaceKnowledgeControls(knowledgeID, ids, actor); err != nil {
+		return nil, fmt.Errorf("統制項目の紐づけに失敗しました: %w", err)
+	}
+
+	return u.controlRepo.GetByKnowledgeID(knowledgeID)
+}
+
+// GetQuestionGroupControls は質問グループに紐づく統制項目を取得する
+func (u *ControlUseCaseImpl) GetQuestionGroupControls(questionGroup string) ([]*domain.Control, error) {
+	questionGroup = strings.TrimSpace(questionGroup)
+	if questionGroup == "" {
+		return nil, &domain.ValidationError{Field: "question_group", Message: "質問グループは必須です"}
+	}
+
+	return u.controlRepo.GetByQuestionGroup(questionGroup)
+}
+
+// SetQuestionGroupControls は質問グループに紐づける統制項目を置き換える
+// 紐づけた統制項目は同じ質問グループのすべてのアイテムに適用される
+func (u *ControlUseCaseImpl) SetQuestionGroupControls(questionGroup string, controlIDs []int, actor string) ([]*domain.Control, error) {
+	questionGroup = strings.TrimSpace(questionGroup)
+	if questionGroup == "" {
+		return nil, &domain.ValidationError{Field: "question_group", Message: "質問グループは必須です"}
+	}
+
+	ids, err := u.validateControlIDs(controlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.controlRepo.ReplaceQuestionGroupControls(questionGroup, ids, actor); err != nil {
+		return nil, fmt.Errorf("統制項目の紐づけに失敗しました: %w", err)
+	}
+
+	return u.controlRepo.GetByQuestionGroup(questionGroup)
+}
+
+// validateControlIDs は統制項目IDの重複を除き、すべて存在することを確認する
+func (u *ControlUseCaseImpl) validateControlIDs(controlIDs []int) ([]int, error) {
+	ids := uniqueInts(controlIDs)
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	count, err := u.controlRepo.CountControls(ids)
+	if err != nil {
+		return nil, fmt.Errorf("統制項目の確認に失敗しました: %w", err)
+	}
+	if count != len(ids) {
+		return nil, &domain.ValidationError{Field: "control_ids", Message: "存在しない統制項目が含まれています"}
+	}
+
+	return ids, nil
+}
+
+// catalogFramework はJSONカタログのフレームワーク
+type catalogFramework struct {
+	Code     string           `json:"code"`
+	Name     string           `json:"name"`
+	Version  string           `json:"version"`
+	Controls []catalogControl `json:"controls"`
+}
+
+// catalogControl はJSONカタログの統制項目
+type catalogControl struct {
+	ControlID   string `json:"control_id"`
+	Title       string `json:"title"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+}
+
+// parseControlCatalogJSON はJSONのカタログを読み取る
+func parseControlCatalogJSON(r io.Reader) ([]*domain.ControlFramework, error) {
+	var entries []catalogFramework
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("JSONの読み取りに失敗しました: %v", err)}
+	}
+
+	frameworks := make([]*domain.ControlFramework, 0, len(entries))
+	for _, e := range entries {
+		f := &domain.ControlFramework{
+			Code:     strings.TrimSpace(e.Code),
+			Name:     strings.TrimSpace(e.Name),
+			Version:  strings.TrimSpace(e.Version),
+			Controls: make([]*domain.Control, 0, len(e.Controls)),
+		}
+		for _, c := range e.Controls {
+			f.Controls = append(f.Controls, &domain.Control{
+				ControlID:   strings.TrimSpace(c.ControlID),
+				Title:       strings.TrimSpace(c.Title),
+				Category:    strings.TrimSpace(c.Category),
+				Description: strings.TrimSpace(c.Description),
+			})
+		}
+		frameworks = append(frameworks, f)
+	}
+
+	return frameworks, nil
+}
+
+// parseControlCatalogCSV はCSVのカタログを読み取る（1行に1統制項目、列はヘッダー名で対応付ける）
+func parseControlCatalogCSV(r io.Reader) ([]*domain.ControlFramework, error) {
+	table, err := newCSVTable(r, "framework_code", "control_id")
+	if err != nil {
+		return nil, err
+	}
+
+	// フレームワークは最初に現れた順に並べる
+	frameworks := []*domain.ControlFramework{}
+	byCode := map[string]*domain.ControlFramework{}
+	for {
+		row, err := table.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		code := row.value("framework_code")
+		f, ok := byCode[code]
+		if !ok {
+			f = &domain.ControlFramework{Code: code}
+			byCode[code] = f
+			frameworks = append(frameworks, f)
+		}
+		// 名称・版は空でない最初の値を使う
+		if f.Name == "" {
+			f.Name = row.value("framework_name")
+		}
+		if f.Version == "" {
+			f.Version = row.value("framework_version")
+		}
+
+		f.Controls = append(f.Controls, &domain.Control{
+			ControlID:   row.value("control_id"),
+			Title:       row.value("title"),
+			Category:    row.value("category"),
+			Description: row.value("description"),
+		})
+	}
+
+	return frameworks, nil
+}
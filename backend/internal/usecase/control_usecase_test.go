@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockControlRepository はControlRepositoryのモック
+type MockControlRepository struct {
+	mock.Mock
+}
+
+func (m *MockControlRepository) UpsertCatalog(frameworks []*domain.ControlFramework) error {
+	args := m.Called(frameworks)
+	return args.Error(0)
+}
+
+func (m *MockControlRepository) GetFrameworks() ([]*domain.ControlFramework, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ControlFramework), args.Error(1)
+}
+
+func (m *MockControlRepository) GetFrameworkByCode(code string) (*domain.ControlFramework, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ControlFramework), args.Error(1)
+}
+
+func (m *MockControlRepository) GetControls(frameworkID int) ([]*domain.Control, error) {
+	args := m.Called(frameworkID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Control), args.Error(1)
+}
+
+func (m *MockControlRepository) CountControls(ids []int) (int, error) {
+	args := m.Called(ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockControlRepository) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeControl, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeControl), args.Error(1)
+}
+
+func (m *MockControlRepository) ReplaceKnowledgeControls(knowledgeID int, controlIDs []int, actor string) error {
+	args := m.Called(knowledgeID, controlIDs, actor)
+	return args.Error(0)
+}
+
+func (m *MockControlRepository) GetByQuestionGroup(questionGroup string) ([]*domain.Control, error) {
+	args := m.Called(questionGroup)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Control), args.Error(1)
+}
+
+func (m *MockControlRepository) ReplaceQuestionGroupControls(questionGroup string, controlIDs []int, actor string) error {
+	args := m.Called(questionGroup, controlIDs, actor)
+	return args.Error(0)
+}
+
+func (m *MockControlRepository) GetCoverage(frameworkID int, prefix string, projectID int) ([]*domain.ControlCoverage, error) {
+	args := m.Called(frameworkID, prefix, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ControlCoverage), args.Error(1)
+}
+
+func TestControlUseCase_LoadCatalog_JSON(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	usecase := NewControlUseCase(controlRepo, new(MockKnowledgeRepository), new(MockProjectRepository))
+
+	controlRepo.On("UpsertCatalog", mock.AnythingOfType("[]*domain.ControlFramework")).Return(nil)
+
+	input := `[{"code": "ISO27001", "name": "ISO/IEC 27001", "version": "2013", "controls": [
+		{"control_id": "A.9.1.1", "title": "アクセス制御方針", "category": "A.9 アクセス制御"},
+		{"control_id": " A.9.1.2 ", "title": "ネットワークへのアクセス"}
+	]}]`
+	frameworks, err := usecase.LoadCatalog(strings.NewReader(input), CatalogFormatJSON)
+	require.NoError(t, err)
+	require.Len(t, frameworks, 1)
+	assert.Equal(t, "ISO27001", frameworks[0].Code)
+	require.Len(t, frameworks[0].Controls, 2)
+	assert.Equal(t, "A.9.1.2", frameworks[0].Controls[1].ControlID)
+	controlRepo.AssertExpectations(t)
+}
+
+func TestControlUseCase_LoadCatalog_CSV(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	usecase := NewControlUseCase(controlRepo, new(MockKnowledgeRepository), new(MockProjectRepository))
+
+	controlRepo.On("UpsertCatalog", mock.AnythingOfType("[]*domain.ControlFramework")).Return(nil)
+
+	input := "\xEF\xBB\xBFframework_code,framework_name,framework_version,control_id,title,category\n" +
+		"SOC2,SOC 2,2017,CC6.1,論理アクセスのセキュリティ,CC6\n" +
+		"ISO27001,ISO/IEC 27001,2013,A.9.1.1,アクセス制御方針,A.9\n" +
+		"SOC2,,,CC6.2,利用者の登録と認可,CC6\n"
+	frameworks, err := usecase.LoadCatalog(strings.NewReader(input), CatalogFormatCSV)
+	require.NoError(t, err)
+	require.Len(t, frameworks, 2)
+	// フレームワークは最初に現れた順に並び、同じコードの行はまとめられる
+	assert.Equal(t, "SOC2", frameworks[0].Code)
+	assert.Equal(t, "SOC 2", frameworks[0].Name)
+	require.Len(t, frameworks[0].Controls, 2)
+	assert.Equal(t, "CC6.2", frameworks[0].Controls[1].ControlID)
+	assert.Equal(t, "ISO27001", frameworks[1].Code)
+}
+
+func TestControlUseCase_LoadCatalog_Invalid(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	usecase := NewControlUseCase(controlRepo, new(MockKnowledgeRepository), new(MockProjectRepository))
+
+	var validationErr *domain.ValidationError
+
+	// 統制番号の重複
+	input := `[{"code": "SOC2", "name": "SOC 2", "controls": [{"control_id": "CC6.1"}, {"control_id": "CC6.1"}]}]`
+	_, err := usecase.LoadCatalog(strings.NewReader(input), CatalogFormatJSON)
+	assert.ErrorAs(t, err, &validationErr)
+
+	// 必須列の欠落
+	_, err = usecase.LoadCatalog(strings.NewReader("framework_code,title\nSOC2,x\n"), CatalogFormatCSV)
+	assert.ErrorAs(t, err, &validationErr)
+
+	// 未対応の形式
+	_, err = usecase.LoadCatalog(strings.NewReader(""), "xml")
+	assert.ErrorAs(t, err, &validationErr)
+
+	controlRepo.AssertNotCalled(t, "UpsertCatalog", mock.Anything)
+}
+
+func TestControlUseCase_GetCoverage(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	projectRepo := new(MockProjectRepository)
+	usecase := NewControlUseCase(controlRepo, new(MockKnowledgeRepository), projectRepo)
+
+	framework := &domain.ControlFramework{ID: 1, Code: "ISO27001"}
+	controlRepo.On("GetFrameworkByCode", "ISO27001").Return(framework, nil)
+	projectRepo.On("GetByID", 3).Return(&domain.Project{ID: 3}, nil)
+	controlRepo.On("GetCoverage", 1, "A.9", 3).Return([]*domain.ControlCoverage{
+		{Control: &domain.Control{ControlID: "A.9.1.1"}, ItemCount: 2, ApprovedCount: 1},
+		{Control: &domain.Control{ControlID: "A.9.1.2"}, ItemCount: 1, ApprovedCount: 0},
+		{Control: &domain.Control{ControlID: "A.9.2.1"}},
+		{Control: &domain.Control{ControlID: "A.9.2.2"}, ItemCount: 3, ApprovedCount: 3},
+	}, nil)
+
+	report, err := usecase.GetCoverage("ISO27001", " A.9. ", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.Covered)
+	assert.InDelta(t, 0.5, report.Rate, 0.0001)
+	// 紐づくアイテムがあっても承認済み・公開済みでなければ未回答とする
+	assert.False(t, report.Controls[1].Covered)
+	assert.True(t, report.Controls[3].Covered)
+}
+
+func TestControlUseCase_GetCoverage_UnknownFramework(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	usecase := NewControlUseCase(controlRepo, new(MockKnowledgeRepository), new(MockProjectRepository))
+
+	controlRepo.On("GetFrameworkByCode", "NIST").Return(nil, sql.ErrNoRows)
+
+	_, err := usecase.GetCoverage("NIST", "", 0)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestControlUseCase_SetKnowledgeControls(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewControlUseCase(controlRepo, knowledgeRepo, new(MockProjectRepository))
+
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	// 重複したIDは1つにまとめられる
+	controlRepo.On("CountControls", []int{5, 6}).Return(2, nil)
+	controlRepo.On("ReplaceKnowledgeControls", 10, []int{5, 6}, "山田太郎").Return(nil)
+	controlRepo.On("GetByKnowledgeID", 10).Return([]*domain.KnowledgeControl{
+		{Control: &domain.Control{ID: 5}, Source: domain.ControlSourceItem},
+		{Control: &domain.Control{ID: 6}, Source: domain.ControlSourceItem},
+	}, nil)
+
+	controls, err := usecase.SetKnowledgeControls(10, []int{5, 6, 5}, "山田太郎")
+	require.NoError(t, err)
+	assert.Len(t, controls, 2)
+	controlRepo.AssertExpectations(t)
+}
+
+func TestControlUseCase_SetKnowledgeControls_UnknownControl(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewControlUseCase(controlRepo, knowledgeRepo, new(MockProjectRepository))
+
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	controlRepo.On("CountControls", []int{5, 999}).Return(1, nil)
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.SetKnowledgeControls(10, []int{5, 999}, "山田太郎")
+	assert.ErrorAs(t, err, &validationErr)
+	controlRepo.AssertNotCalled(t, "ReplaceKnowledgeControls", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestControlUseCase_SetQuestionGroupControls_RequiresGroup(t *testing.T) {
+	controlRepo := new(MockControlRepository)
+	usecase := NewControlUseCase(controlRepo, new(MockKnowledgeRepository), new(MockProjectRepository))
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.SetQuestionGroupControls("  ", []int{5}, "山田太郎")
+	assert.ErrorAs(t, err, &validationErr)
+	controlRepo.AssertNotCalled(t, "ReplaceQuestionGroupControls", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// DuplicateUseCase は案件内の重複したナレッジの検出・解消に関するビジネスロジックを提供する
+type DuplicateUseCase interface {
+	FindDuplicates(projectID int, threshold float64) ([]*domain.DuplicateGroup, error)
+	// ResolveDuplicates はexpectedVersionに0を指定した場合は残すアイテムの版の確認を行わない
+	ResolveDuplicates(projectID int, resolution domain.DuplicateResolution, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+}
+
+// DuplicateUseCaseImpl はDuplicateUseCaseの実装
+type DuplicateUseCaseImpl struct {
+	knowledgeRepo domain.KnowledgeRepository
+	projectRepo   domain.ProjectRepository
+	duplicateRepo domain.KnowledgeDuplicateRepository
+}
+
+// NewDuplicateUseCase は新しいDuplicateUseCaseを生成する
+func NewDuplicateUseCase(
+	knowledgeRepo domain.KnowledgeRepository,
+	projectRepo domain.ProjectRepository,
+	duplicateRepo domain.KnowledgeDuplicateRepository,
+) DuplicateUseCase {
+	return &DuplicateUseCaseImpl{
+		knowledgeRepo: knowledgeRepo,
+		projectRepo:   projectRepo,
+		duplicateRepo: duplicateRepo,
+	}
+}
+
+// FindDuplicates は案件内のナレッジを、正規化した質問の一致または類似度で重複のグループにまとめる
+func (u *DuplicateUseCaseImpl) FindDuplicates(projectID int, threshold float64) ([]*domain.DuplicateGroup, error) {
+	// 案件の存在確認
+	if _, err := u.projectRepo.GetByID(projectID); err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	if threshold == 0 {
+		threshold = domain.DefaultDuplicateThreshold
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, &domain.ValidationError{Field: "threshold", Message: "thresholdは0より大きく1以下である必要があります"}
+	}
+
+	pairs, err := u.duplicateRepo.FindDuplicatePairs(projectID, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("重複の検出に失敗しました: %w", err)
+	}
+	if len(pairs) == 0 {
+		return []*domain.DuplicateGroup{}, nil
+	}
+
+	items, _, err := u.knowledgeRepo.GetByProjectID(projectID, domain.PageRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジの取得に失敗しました: %w", err)
+	}
+
+	return domain.ClusterDuplicates(items, pairs), nil
+}
+
+// ResolveDuplicates は残すアイテムを1つ選び、他の重複を統合または削除する
+func (u *DuplicateUseCaseImpl) ResolveDuplicates(projectID int, resolution domain.DuplicateResolution, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	if err := resolution.Validate(); err != nil {
+		return nil, err
+	}
+
+	keep, err := u.knowledgeRepo.GetByID(resolution.KeepID)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません (ID: %d): %w", resolution.KeepID, err)
+	}
+	if keep.ProjectID != projectID {
+		return nil, &domain.ValidationError{Field: "keep_id", Message: fmt.Sprintf("指定された案件のナレッジではありません: %d", keep.ID)}
+	}
+	if expectedVersion != 0 && keep.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{Current: keep}
+	}
+
+	duplicates := make([]*domain.KnowledgeItem, 0, len(resolution.DuplicateIDs))
+	for _, id := range resolution.DuplicateIDs {
+		d, err := u.knowledgeRepo.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("ナレッジアイテムが存在しません (ID: %d): %w", id, err)
+		}
+		if d.ProjectID != projectID {
+			return nil, &domain.ValidationError{Field: "duplicate_ids", Message: fmt.Sprintf("指定された案件のナレッジではありません: %d", id)}
+		}
+		duplicates = append(duplicates, d)
+	}
+
+	loadedVersion := keep.Version
+	if resolution.Action == domain.DuplicateResolveMerge {
+		keep.AbsorbDuplicates(duplicates, actor)
+	}
+
+	if err := u.duplicateRepo.Resolve(resolution.Action, keep, loadedVersion, duplicates, actor); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return nil, fmt.Errorf("重複の解消中に他の更新が行われました。再読み込みしてください: %w", err)
+		}
+		return nil, fmt.Errorf("重複の解消に失敗しました: %w", err)
+	}
+
+	return keep, nil
+}
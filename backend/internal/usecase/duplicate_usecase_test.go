@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKnowledgeDuplicateRepository はKnowledgeDuplicateRepositoryのモック
+type MockKnowledgeDuplicateRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeDuplicateRepository) FindDuplicatePairs(projectID int, threshold float64) ([]*domain.DuplicatePair, error) {
+	args := m.Called(projectID, threshold)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DuplicatePair), args.Error(1)
+}
+
+func (m *MockKnowledgeDuplicateRepository) Resolve(action string, keep *domain.KnowledgeItem, expectedVersion int, duplicates []*domain.KnowledgeItem, actor string) error {
+	args := m.Called(action, keep, expectedVersion, duplicates, actor)
+	return args.Error(0)
+}
+
+func TestDuplicateUseCase_FindDuplicates(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	duplicateRepo := new(MockKnowledgeDuplicateRepository)
+	usecase := NewDuplicateUseCase(knowledgeRepo, projectRepo, duplicateRepo)
+
+	items := []*domain.KnowledgeItem{
+		{ID: 1, ProjectID: 5, Question: "パスワードの最小文字数は？"},
+		{ID: 2, ProjectID: 5, Question: "パスワードの最小文字数は?"},
+		{ID: 3, ProjectID: 5, Question: "多要素認証を使用していますか？"},
+	}
+	pairs := []*domain.DuplicatePair{{ItemID: 1, OtherID: 2, Exact: true, Score: 1}}
+
+	projectRepo.On("GetByID", 5).Return(&domain.Project{ID: 5}, nil)
+	duplicateRepo.On("FindDuplicatePairs", 5, domain.DefaultDuplicateThreshold).Return(pairs, nil)
+	knowledgeRepo.On("GetByProjectID", 5, domain.PageRequest{}).Return(items, 3, nil)
+
+	groups, err := usecase.FindDuplicates(5, 0)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []*domain.KnowledgeItem{items[0], items[1]}, groups[0].Items)
+	assert.True(t, groups[0].Exact)
+}
+
+func TestDuplicateUseCase_ResolveDuplicates_Merge(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	duplicateRepo := new(MockKnowledgeDuplicateRepository)
+	usecase := NewDuplicateUseCase(knowledgeRepo, new(MockProjectRepository), duplicateRepo)
+
+	keep := &domain.KnowledgeItem{ID: 1, ProjectID: 5, Question: "パスワードの最小文字数は？", Version: 2}
+	duplicate := &domain.KnowledgeItem{ID: 2, ProjectID: 5, Question: "パスワードの最小文字数は?", Answer: "12文字", Version: 1}
+	knowledgeRepo.On("GetByID", 1).Return(keep, nil)
+	knowledgeRepo.On("GetByID", 2).Return(duplicate, nil)
+	duplicateRepo.On("Resolve", domain.DuplicateResolveMerge, keep, 2, []*domain.KnowledgeItem{duplicate}, "山田太郎").Return(nil)
+
+	item, err := usecase.ResolveDuplicates(5, domain.DuplicateResolution{KeepID: 1, DuplicateIDs: []int{2}, Action: domain.DuplicateResolveMerge}, "山田太郎", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "12文字", item.Answer)
+	assert.Equal(t, 3, item.Version)
+	duplicateRepo.AssertExpectations(t)
+}
+
+func TestDuplicateUseCase_ResolveDuplicates_Errors(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	duplicateRepo := new(MockKnowledgeDuplicateRepository)
+	usecase := NewDuplicateUseCase(knowledgeRepo, new(MockProjectRepository), duplicateRepo)
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 5, Version: 2}, nil)
+	knowledgeRepo.On("GetByID", 3).Return(&domain.KnowledgeItem{ID: 3, ProjectID: 6, Version: 1}, nil)
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.ResolveDuplicates(5, domain.DuplicateResolution{KeepID: 1, DuplicateIDs: []int{2}, Action: "archive"}, "山田太郎", 0)
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = usecase.ResolveDuplicates(5, domain.DuplicateResolution{KeepID: 1, DuplicateIDs: []int{1}, Action: domain.DuplicateResolveDelete}, "山田太郎", 0)
+	assert.ErrorAs(t, err, &validationErr)
+
+	// 他の案件のアイテムは解消の対象にできない
+	_, err = usecase.ResolveDuplicates(5, domain.DuplicateResolution{KeepID: 1, DuplicateIDs: []int{3}, Action: domain.DuplicateResolveDelete}, "山田太郎", 0)
+	assert.ErrorAs(t, err, &validationErr)
+
+	var conflictErr *domain.VersionConflictError
+	_, err = usecase.ResolveDuplicates(5, domain.DuplicateResolution{KeepID: 1, DuplicateIDs: []int{3}, Action: domain.DuplicateResolveDelete}, "山田太郎", 1)
+	assert.ErrorAs(t, err, &conflictErr)
+
+	duplicateRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// evidenceDir はアップロード先のうちエビデンスを保存するディレクトリ
+const evidenceDir = "evidence"
+
+// EvidenceUseCase はナレッジアイテムに紐づくエビデンスに関するビジネスロジックを提供する
+type EvidenceUseCase interface {
+	// UploadEvidence はエビデンスを保存し、ナレッジアイテムに紐づける
+	UploadEvidence(knowledgeID int, fileHeader *multipart.FileHeader, description string, expiresAt *time.Time, actor string) (*domain.EvidenceFile, error)
+	// LinkEvidence は登録済みのエビデンスをナレッジアイテムに紐づける
+	LinkEvidence(knowledgeID int, evidenceID int, actor string) ([]*domain.KnowledgeEvidence, error)
+	// UnlinkEvidence は紐づけを解除する。どのアイテムからも参照されなくなったエビデンスはファイルごと削除する
+	UnlinkEvidence(knowledgeID int, evidenceID int) error
+	ListKnowledgeEvidence(knowledgeID int) ([]*domain.KnowledgeEvidence, error)
+	ListEvidence() ([]*domain.EvidenceFile, error)
+	GetEvidence(id int) (*domain.EvidenceFile, error)
+	// UpdateEvidence は説明と有効期限を更新する
+	UpdateEvidence(id int, description string, expiresAt *time.Time) (*domain.EvidenceFile, error)
+}
+
+// EvidenceUseCaseImpl はEvidenceUseCaseの実装
+type EvidenceUseCaseImpl struct {
+	evidenceRepo   domain.EvidenceRepository
+	knowledgeRepo  domain.KnowledgeRepository
+	uploadBasePath string
+}
+
+// NewEvidenceUseCase は新しいEvidenceUseCaseを生成する
+// エビデンスは案件のファイルと同じアップロード先の、案件に属さないディレクトリに保存する
+func NewEvidenceUseCase(
+	evidenceRepo domain.EvidenceRepository,
+	knowledgeRepo domain.KnowledgeRepository,
+	uploadBasePath string,
+) EvidenceUseCase {
+	return &EvidenceUseCaseImpl{
+		evidenceRepo:   evidenceRepo,
+		knowledgeRepo:  knowledgeRepo,
+		uploadBasePath: uploadBasePath,
+	}
+}
+
+// UploadEvidence はエビデンスを保存し、ナレッジアイテムに紐づける
+func (u *EvidenceUseCaseImpl) UploadEvidence(knowledgeID int, fileHeader *multipart.FileHeader, description string, expiresAt *time.Time, actor string) (*domain.EvidenceFile, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	filePath, err := saveUpload(filepath.Join(u.uploadBasePath, evidenceDir), fileHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	file := domain.NewEvidenceFile(
+		fileHeader.Filename,
+		filePath,
+		fileHeader.Size,
+		fileHeader.Header.Get("Content-Type"),
+		description,
+		expiresAt,
+		actor,
+	)
+
+	if err := file.Validate(); err != nil {
+		// バリデーションエラーの場合はファイルを削除
+		os.Remove(filePath)
+		return nil, err
+	}
+
+	if err := u.evidenceRepo.CreateAndLink(file, knowledgeID, actor); err != nil {
+		// DB保存エラーの場合はファイルを削除
+		os.Remove(filePath)
+		return nil, fmt.Errorf("エビデンスの保存に失敗しました: %w", err)
+	}
+	file.ItemCount = 1
+
+	return file, nil
+}
+
+// LinkEvidence は登録済みのエビデンスをナレッジアイテムに紐づける
+func (u *EvidenceUseCaseImpl) LinkEvidence(knowledgeID int, evidenceID int, actor string) ([]*domain.KnowledgeEvidence, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+	if _, err := u.evidenceRepo.GetByID(evidenceID); err != nil {
+		return nil, fmt.Errorf("エビデンスが存在しません: %w", err)
+	}
+
+	if err := u.evidenceRepo.Link(knowledgeID, evidenceID, actor); err != nil {
+		return nil, fmt.Errorf("エビデンスの紐づけに失敗しました: %w", err)
+	}
+
+	return u.evidenceRepo.GetByKnowledgeID(knowledgeID)
+}
+
+// UnlinkEvidence は紐づけを解除し、参照されなくなったエビデンスを削除する
+func (u *EvidenceUseCaseImpl) UnlinkEvidence(knowledgeID int, evidenceID int) error {
+	file, err := u.evidenceRepo.GetByID(evidenceID)
+	if err != nil {
+		return fmt.Errorf("エビデンスが存在しません: %w", err)
+	}
+
+	deleted, err := u.evidenceRepo.Unlink(knowledgeID, evidenceID)
+	if err != nil {
+		return fmt.Errorf("エビデンスの紐づけが存在しません: %w", err)
+	}
+	// 保存先のファイルはエビデンスの行を削除した場合のみ削除する
+	if !deleted {
+		return nil
+	}
+	return removeStoredFile(file.FilePath)
+}
+
+// ListKnowledgeEvidence はナレッジアイテムに紐づくエビデンスを取得する
+func (u *EvidenceUseCaseImpl) ListKnowledgeEvidence(knowledgeID int) ([]*domain.KnowledgeEvidence, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	return u.evidenceRepo.GetByKnowledgeID(knowledgeID)
+}
+
+// ListEvidence はすべてのエビデンスを取得する
+func (u *EvidenceUseCaseImpl) ListEvidence() ([]*domain.EvidenceFile, error) {
+	return u.evidenceRepo.GetAll()
+}
+
+// GetEvidence はエビデンスを取得する
+func (u *EvidenceUseCaseImpl) GetEvidence(id int) (*domain.EvidenceFile, error) {
+	file, err := u.evidenceRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("エビデンスが存在しません: %w", err)
+	}
+	return file, nil
+}
+
+// UpdateEvidence は説明と有効期限を更新する
+func (u *EvidenceUseCaseImpl) UpdateEvidence(id int, description string, expiresAt *time.Time) (*domain.EvidenceFile, error) {
+	file, err := u.evidenceRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("エビデンスが存在しません: %w", err)
+	}
+
+	file.Description = strings.TrimSpace(description)
+	file.ExpiresAt = expiresAt
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.evidenceRepo.Update(file); err != nil {
+		return nil, fmt.Errorf("エビデンスの更新に失敗しました: %w", err)
+	}
+
+	return file, nil
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEvidenceRepository はEvidenceRepositoryのモック
+type MockEvidenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockEvidenceRepository) CreateAndLink(file *domain.EvidenceFile, knowledgeID int, actor string) error {
+	args := m.Called(file, knowledgeID, actor)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceRepository) GetByID(id int) (*domain.EvidenceFile, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.EvidenceFile), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) GetAll() ([]*domain.EvidenceFile, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.EvidenceFile), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) Update(file *domain.EvidenceFile) error {
+	args := m.Called(file)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceRepository) Link(knowledgeID int, evidenceID int, actor string) error {
+	args := m.Called(knowledgeID, evidenceID, actor)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceRepository) Unlink(knowledgeID int, evidenceID int) (bool, error) {
+	args := m.Called(knowledgeID, evidenceID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeEvidence, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeEvidence), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) GetByKnowledgeIDs(knowledgeIDs []int) (map[int][]*domain.EvidenceFile, error) {
+	args := m.Called(knowledgeIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]*domain.EvidenceFile), args.Error(1)
+}
+
+// newTestFileHeader はマルチパートフォームのファイルを生成する
+func newTestFileHeader(t *testing.T, fileName string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func TestEvidenceUseCase_UploadEvidence(t *testing.T) {
+	evidenceRepo := new(MockEvidenceRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	baseDir := t.TempDir()
+	usecase := NewEvidenceUseCase(evidenceRepo, knowledgeRepo, baseDir)
+
+	expiresAt := time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	evidenceRepo.On("CreateAndLink", mock.AnythingOfType("*domain.EvidenceFile"), 10, "山田太郎").Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.EvidenceFile).ID = 3
+	}).Return(nil)
+
+	file, err := usecase.UploadEvidence(10, newTestFileHeader(t, "iso27001.pdf", []byte("%PDF")), " 認証書 ", &expiresAt, "山田太郎")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, file.ID)
+	assert.Equal(t, "iso27001.pdf", file.FileName)
+	assert.Equal(t, "認証書", file.Description)
+	assert.Equal(t, &expiresAt, file.ExpiresAt)
+	assert.Equal(t, 1, file.ItemCount)
+
+	// 案件に属さないエビデンス用のディレクトリに保存される
+	assert.Equal(t, filepath.Join(baseDir, evidenceDir), filepath.Dir(file.FilePath))
+	content, err := os.ReadFile(file.FilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF", string(content))
+	evidenceRepo.AssertExpectations(t)
+}
+
+func TestEvidenceUseCase_UploadEvidence_SameName(t *testing.T) {
+	evidenceRepo := new(MockEvidenceRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewEvidenceUseCase(evidenceRepo, knowledgeRepo, t.TempDir())
+
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	evidenceRepo.On("CreateAndLink", mock.AnythingOfType("*domain.EvidenceFile"), 10, "山田太郎").Return(nil)
+
+	// 同じ秒に同名のファイルをアップロードしても別のパスに保存される
+	first, err := usecase.UploadEvidence(10, newTestFileHeader(t, "policy.pdf", []byte("v1")), "", nil, "山田太郎")
+	require.NoError(t, err)
+	second, err := usecase.UploadEvidence(10, newTestFileHeader(t, "policy.pdf", []byte("v2")), "", nil, "山田太郎")
+	require.NoError(t, err)
+	assert.NotEqual(t, first.FilePath, second.FilePath)
+
+	content, err := os.ReadFile(first.FilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(content))
+}
+
+func TestEvidenceUseCase_UploadEvidence_SaveError(t *testing.T) {
+	evidenceRepo := new(MockEvidenceRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	baseDir := t.TempDir()
+	usecase := NewEvidenceUseCase(evidenceRepo, knowledgeRepo, baseDir)
+
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	evidenceRepo.On("CreateAndLink", mock.AnythingOfType("*domain.EvidenceFile"), 10, "山田太郎").Return(errors.New("connection reset"))
+
+	_, err := usecase.UploadEvidence(10, newTestFileHeader(t, "policy.pdf", []byte("%PDF")), "", nil, "山田太郎")
+	require.Error(t, err)
+
+	// 保存したファイルは削除される
+	entries, err := os.ReadDir(filepath.Join(baseDir, evidenceDir))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestEvidenceUseCase_UnlinkEvidence(t *testing.T) {
+	t.Run("他のアイテムから参照されている場合は紐づけのみ解除する", func(t *testing.T) {
+		evidenceRepo := new(MockEvidenceRepository)
+		usecase := NewEvidenceUseCase(evidenceRepo, new(MockKnowledgeRepository), t.TempDir())
+
+		filePath := filepath.Join(t.TempDir(), "policy.pdf")
+		require.NoError(t, os.WriteFile(filePath, []byte("%PDF"), 0644))
+
+		evidenceRepo.On("GetByID", 3).Return(&domain.EvidenceFile{ID: 3, FilePath: filePath}, nil)
+		evidenceRepo.On("Unlink", 10, 3).Return(false, nil)
+
+		require.NoError(t, usecase.UnlinkEvidence(10, 3))
+		// エビデンスの行が残っている場合はファイルも残す
+		_, err := os.Stat(filePath)
+		assert.NoError(t, err)
+	})
+
+	t.Run("参照されなくなったエビデンスはファイルごと削除する", func(t *testing.T) {
+		evidenceRepo := new(MockEvidenceRepository)
+		usecase := NewEvidenceUseCase(evidenceRepo, new(MockKnowledgeRepository), t.TempDir())
+
+		filePath := filepath.Join(t.TempDir(), "policy.pdf")
+		require.NoError(t, os.WriteFile(filePath, []byte("%PDF"), 0644))
+
+		evidenceRepo.On("GetByID", 3).Return(&domain.EvidenceFile{ID: 3, FilePath: filePath}, nil)
+		evidenceRepo.On("Unlink", 10, 3).Return(true, nil)
+
+		require.NoError(t, usecase.UnlinkEvidence(10, 3))
+		_, err := os.Stat(filePath)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("紐づいていない場合はエラー", func(t *testing.T) {
+		evidenceRepo := new(MockEvidenceRepository)
+		usecase := NewEvidenceUseCase(evidenceRepo, new(MockKnowledgeRepository), t.TempDir())
+
+		evidenceRepo.On("GetByID", 3).Return(&domain.EvidenceFile{ID: 3}, nil)
+		evidenceRepo.On("Unlink", 11, 3).Return(false, sql.ErrNoRows)
+
+		err := usecase.UnlinkEvidence(11, 3)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestEvidenceUseCase_LinkEvidence_NotFound(t *testing.T) {
+	evidenceRepo := new(MockEvidenceRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewEvidenceUseCase(evidenceRepo, knowledgeRepo, t.TempDir())
+
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	evidenceRepo.On("GetByID", 99).Return(nil, sql.ErrNoRows)
+
+	_, err := usecase.LinkEvidence(10, 99, "山田太郎")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	evidenceRepo.AssertNotCalled(t, "Link", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// CSVエンコーディング
+const (
+	EncodingUTF8BOM = "utf-8-bom"
+	EncodingUTF8    = "utf-8"
+)
+
+// utf8BOM はExcelにUTF-8と認識させるためのバイトオーダーマーク
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// csvFormulaEscape はExcelで数式として解釈されないようセルの先頭に付ける文字
+const csvFormulaEscape = "'"
+
+// csvFormulaTriggers はExcelがセルの先頭にあると数式として解釈しうる文字（タブ・改行を含む）
+// 元から'で始まる値を取り込み直したときに区別できるよう、csvFormulaEscape自体も含める
+const csvFormulaTriggers = "=+-@\t\r" + csvFormulaEscape
+
+// escapeCSVFormula はcsvFormulaTriggersの文字で始まるセルの先頭にcsvFormulaEscapeを付ける
+// 質問や回答に細工された数式がExcelで開いたときに実行されるのを防ぐ
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaTriggers, rune(value[0])) {
+		return csvFormulaEscape + value
+	}
+	return value
+}
+
+// unescapeCSVFormula はescapeCSVFormulaで付けた先頭の文字を1つだけ取り除く（エクスポートしたCSVを取り込み直すため）
+// 取り除いた後の値がエスケープの対象でない場合は、手入力の'とみなしてそのまま残す
+func unescapeCSVFormula(value string) string {
+	if rest := strings.TrimPrefix(value, csvFormulaEscape); rest != value && escapeCSVFormula(rest) != rest {
+		return rest
+	}
+	return value
+}
+
+// knowledgeCSVHeader はナレッジCSVのヘッダー（要件3.3のスキーマ）
+var knowledgeCSVHeader = []string{
+	"question",
+	"answer",
+	"department",
+	"source_file_name",
+	"sheet_name",
+	"source_range",
+	"customer_name",
+	"question_group",
+	"status",
+	"created_by",
+	"created_at",
+	"updated_at",
+	"version",
+}
+
+// エビデンス付きエクスポートのzip内のパス
+const (
+	exportZipCSVName     = "knowledge.csv"
+	exportZipEvidenceDir = "evidence"
+)
+
+// ExportOptions はCSVエクスポートのオプション
+type ExportOptions struct {
+	Encoding string
+	// WithEvidence がtrueの場合、CSVにevidence列を追加し、紐づくエビデンスのファイルとともにzipで出力する
+	WithEvidence bool
+}
+
+// Validate はエクスポートオプションの妥当性を検証する
+func (o ExportOptions) Validate() error {
+	switch o.Encoding {
+	case "", EncodingUTF8BOM, EncodingUTF8:
+		return nil
+	}
+	return &domain.ValidationError{Field: "encoding", Message: "encodingはutf-8-bom, utf-8のいずれかである必要があります"}
+}
+
+// KnowledgeExportRow はCSVの1行分のデータ
+type KnowledgeExportRow struct {
+	Question       string
+	Answer         string
+	Department     string
+	SourceFileName string
+	SheetName      string
+	SourceRange    string
+	CustomerName   string
+	QuestionGroup  string
+	Status         string
+	CreatedBy      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Version        int
+	// Evidence はzip内のエビデンスのパス（有効期限切れのものは同梱せず、ファイル名と期限のみ）
+	Evidence []string
+}
+
+// ExportUseCase はナレッジのエクスポートに関するビジネスロジックを提供する
+type ExportUseCase interface {
+	ExportProjectKnowledge(projectID int, w io.Writer, opts ExportOptions) error
+	ExportSearchKnowledge(query string, filter domain.KnowledgeSearchFilter, w io.Writer, opts ExportOptions) error
+}
+
+// ExportUseCaseImpl はExportUseCaseの実装
+type ExportUseCaseImpl struct {
+	knowledgeRepo  domain.KnowledgeRepository
+	projectRepo    domain.ProjectRepository
+	fileRepo       domain.FileRepository
+	departmentRepo domain.DepartmentRepository
+	evidenceRepo   domain.EvidenceRepository
+}
+
+// NewExportUseCase は新しいExportUseCaseを生成する
+func NewExportUseCase(
+	knowledgeRepo domain.KnowledgeRepository,
+	projectRepo domain.ProjectRepository,
+	fileRepo domain.FileRepository,
+	departmentRepo domain.DepartmentRepository,
+	evidenceRepo domain.EvidenceRepository,
+) ExportUseCase {
+	return &ExportUseCaseImpl{
+		knowledgeRepo:  knowledgeRepo,
+		projectRepo:    projectRepo,
+		fileRepo:       fileRepo,
+		departmentRepo: departmentRepo,
+		evidenceRepo:   evidenceRepo,
+	}
+}
+
+// ExportProjectKnowledge は案件に紐づくナレッジをCSVで出力する
+func (u *ExportUseCaseImpl) ExportProjectKnowledge(projectID int, w io.Writer, opts ExportOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	// 案件の存在確認
+	if _, err := u.projectRepo.GetByID(projectID); err != nil {
+		return fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	items, _, err := u.knowledgeRepo.GetByProjectID(projectID, domain.PageRequest{})
+	if err != nil {
+		return fmt.Errorf("ナレッジの取得に失敗しました: %w", err)
+	}
+
+	rows, err := u.buildRows(items)
+	if err != nil {
+		return err
+	}
+
+	return u.write(w, items, rows, opts)
+}
+
+// ExportSearchKnowledge は検索結果のナレッジをCSVで出力する
+func (u *ExportUseCaseImpl) ExportSearchKnowledge(query string, filter domain.KnowledgeSearchFilter, w io.Writer, opts ExportOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	items, _, err := u.knowledgeRepo.Search(query, filter, domain.PageRequest{})
+	if err != nil {
+		return fmt.Errorf("ナレッジの検索に失敗しました: %w", err)
+	}
+
+	rows, err := u.buildRows(items)
+	if err != nil {
+		return err
+	}
+
+	return u.write(w, items, rows, opts)
+}
+
+// buildRows はナレッジアイテムの部門・ファイル・案件を名称に解決し、回答のプレースホルダーを置き換えてCSV行に変換する
+func (u *ExportUseCaseImpl) buildRows(items []*domain.KnowledgeItem) ([]KnowledgeExportRow, error) {
+	departments := map[int]string{}
+	files := map[int]string{}
+	projects := map[int]*domain.Project{}
+
+	rows := make([]KnowledgeExportRow, 0, len(items))
+	for _, item := range items {
+		row := KnowledgeExportRow{
+			Question:      item.Question,
+			Answer:        item.Answer,
+			SheetName:     item.SheetName,
+			SourceRange:   item.SourceRange,
+			QuestionGroup: item.QuestionGroup,
+			Status:        item.Status,
+			CreatedBy:     item.CreatedBy,
+			CreatedAt:     item.CreatedAt,
+			UpdatedAt:     item.UpdatedAt,
+			Version:       item.Version,
+		}
+
+		if item.DepartmentID != nil {
+			name, ok := departments[*item.DepartmentID]
+			if !ok {
+				dept, err := u.departmentRepo.GetByID(*item.DepartmentID)
+				if err != nil {
+					return nil, fmt.Errorf("部門の取得に失敗しました (ID: %d): %w", *item.DepartmentID, err)
+				}
+				name = dept.Name
+				departments[*item.DepartmentID] = name
+			}
+			row.Department = name
+		}
+
+		if item.FileID != nil {
+			name, ok := files[*item.FileID]
+			if !ok {
+				// 取込元のファイルがゴミ箱にあってもファイル名は出力する
+				file, err := u.fileRepo.GetByIDIncludingTrash(*item.FileID)
+				if err != nil {
+					return nil, fmt.Errorf("ファイルの取得に失敗しました (ID: %d): %w", *item.FileID, err)
+				}
+				name = file.FileName
+				files[*item.FileID] = name
+			}
+			row.SourceFileName = name
+		}
+
+		project, ok := projects[item.ProjectID]
+		if !ok {
+			p, err := u.projectRepo.GetByID(item.ProjectID)
+			if err != nil {
+				return nil, fmt.Errorf("案件の取得に失敗しました (ID: %d): %w", item.ProjectID, err)
+			}
+			project = p
+			projects[item.ProjectID] = project
+		}
+		row.CustomerName = project.CustomerName
+
+		// 回答のプレースホルダーを案件の値で置き換える（解決できないものはそのまま出力する）
+		if domain.HasPlaceholders(item.Answer) {
+			if rendered, err := domain.RenderAnswer(item.Answer, project); err == nil {
+				row.Answer = rendered.Rendered
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// write はオプションに応じてCSVまたはエビデンス付きのzipを書き出す（rowsはitemsと同じ並び）
+func (u *ExportUseCaseImpl) write(w io.Writer, items []*domain.KnowledgeItem, rows []KnowledgeExportRow, opts ExportOptions) error {
+	if !opts.WithEvidence {
+		return writeKnowledgeCSV(w, rows, opts)
+	}
+
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	evidence, err := u.evidenceRepo.GetByKnowledgeIDs(ids)
+	if err != nil {
+		return fmt.Errorf("エビデンスの取得に失敗しました: %w", err)
+	}
+
+	// 複数のアイテムから参照されるエビデンスは1つだけ同梱する
+	now := time.Now()
+	bundled := []*domain.EvidenceFile{}
+	entries := map[int]string{}
+	for i, item := range items {
+		for _, file := range evidence[item.ID] {
+			if file.IsExpired(now) {
+				rows[i].Evidence = append(rows[i].Evidence, fmt.Sprintf("%s（期限切れ: %s）", file.FileName, file.ExpiresAt.Format("2006-01-02")))
+				continue
+			}
+			entry, ok := entries[file.ID]
+			if !ok {
+				entry = evidenceZipPath(file)
+				entries[file.ID] = entry
+				bundled = append(bundled, file)
+			}
+			rows[i].Evidence = append(rows[i].Evidence, entry)
+		}
+	}
+
+	return writeKnowledgeZip(w, rows, bundled, entries, opts)
+}
+
+// evidenceZipPath はzip内のエビデンスのパスを返す（同名のファイルを区別するためIDを前置する）
+func evidenceZipPath(file *domain.EvidenceFile) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(file.FileName)
+	return path.Join(exportZipEvidenceDir, fmt.Sprintf("%d_%s", file.ID, name))
+}
+
+// writeKnowledgeZip はCSVとエビデンスのファイルをzipで書き出す
+// 書き出しの途中で失敗して壊れたzipを返さないよう、同梱するファイルをすべて開いてから書き出しを始める
+func writeKnowledgeZip(w io.Writer, rows []KnowledgeExportRow, files []*domain.EvidenceFile, entries map[int]string, opts ExportOptions) error {
+	sources := make([]*os.File, 0, len(files))
+	defer func() {
+		for _, src := range sources {
+			src.Close()
+		}
+	}()
+	for _, file := range files {
+		src, err := openZipSource(file.FilePath)
+		if err != nil {
+			return fmt.Errorf("エビデンスのファイルを読み込めません (ID: %d): %w", file.ID, err)
+		}
+		sources = append(sources, src)
+	}
+
+	zw := zip.NewWriter(w)
+
+	csvWriter, err := zw.Create(exportZipCSVName)
+	if err != nil {
+		return err
+	}
+	if err := writeKnowledgeCSV(csvWriter, rows, opts); err != nil {
+		return err
+	}
+
+	for i, file := range files {
+		if err := addZipFile(zw, entries[file.ID], sources[i]); err != nil {
+			return fmt.Errorf("エビデンスの書き出しに失敗しました (ID: %d): %w", file.ID, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// openZipSource はzipに同梱するファイルを開き、通常のファイルであることを確認する
+func openZipSource(filePath string) (*os.File, error) {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := src.Stat()
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		src.Close()
+		return nil, fmt.Errorf("通常のファイルではありません: %s", filePath)
+	}
+	return src, nil
+}
+
+// addZipFile は開いたファイルをzipに追加する
+func addZipFile(zw *zip.Writer, name string, src io.Reader) error {
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// writeKnowledgeCSV はCSV行を書き出す
+func writeKnowledgeCSV(w io.Writer, rows []KnowledgeExportRow, opts ExportOptions) error {
+	if opts.Encoding == "" || opts.Encoding == EncodingUTF8BOM {
+		if _, err := w.Write(utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	// Excelで開くことを想定して改行はCRLFとする
+	writer.UseCRLF = true
+
+	header := knowledgeCSVHeader
+	if opts.WithEvidence {
+		header = append(append([]string{}, knowledgeCSVHeader...), "evidence")
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := []string{
+			row.Question,
+			row.Answer,
+			row.Department,
+			row.SourceFileName,
+			row.SheetName,
+			row.SourceRange,
+			row.CustomerName,
+			row.QuestionGroup,
+			row.Status,
+			row.CreatedBy,
+			row.CreatedAt.Format(time.RFC3339),
+			row.UpdatedAt.Format(time.RFC3339),
+			strconv.Itoa(row.Version),
+		}
+		if opts.WithEvidence {
+			record = append(record, strings.Join(row.Evidence, "\n"))
+		}
+		for i := range record {
+			record[i] = escapeCSVFormula(record[i])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteKnowledgeCSV_WithBOM(t *testing.T) {
+	createdAt := time.Date(2026, 1, 11, 6, 0, 0, 0, time.UTC)
+	rows := []KnowledgeExportRow{
+		{
+			Question:       "アクセス制御は適切に設定されていますか？",
+			Answer:         "はい、\n多要素認証を実施しています",
+			Department:     "セキュリティ",
+			SourceFileName: "check.xlsx",
+			SheetName:      "セキュリティチェック",
+			SourceRange:    "A1:D10",
+			CustomerName:   "テスト株式会社",
+			Status:         "draft",
+			CreatedBy:      "山田太郎",
+			CreatedAt:      createdAt,
+			UpdatedAt:      createdAt,
+			Version:        2,
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeKnowledgeCSV(&buf, rows, ExportOptions{})
+	require.NoError(t, err)
+
+	// 既定ではBOMが付与される
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), utf8BOM))
+
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes()[len(utf8BOM):])).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, knowledgeCSVHeader, records[0])
+	assert.Equal(t, "はい、\n多要素認証を実施しています", records[1][1])
+	assert.Equal(t, "セキュリティ", records[1][2])
+	assert.Equal(t, "2026-01-11T06:00:00Z", records[1][10])
+	assert.Equal(t, "2", records[1][12])
+}
+
+func TestWriteKnowledgeCSV_WithoutBOM(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeKnowledgeCSV(&buf, nil, ExportOptions{Encoding: EncodingUTF8})
+	require.NoError(t, err)
+
+	assert.False(t, bytes.HasPrefix(buf.Bytes(), utf8BOM))
+	assert.Equal(t, "question,answer,department,source_file_name,sheet_name,source_range,customer_name,question_group,status,created_by,created_at,updated_at,version\r\n", buf.String())
+}
+
+func TestWriteKnowledgeCSV_EscapesFormulas(t *testing.T) {
+	rows := []KnowledgeExportRow{
+		{
+			Question:  "=HYPERLINK(\"http://example.com\")",
+			Answer:    "+1 営業日以内に対応します",
+			SheetName: "-",
+			CreatedBy: "@admin",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeKnowledgeCSV(&buf, rows, ExportOptions{Encoding: EncodingUTF8}))
+	content := buf.String()
+
+	records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "'=HYPERLINK(\"http://example.com\")", records[1][0])
+	assert.Equal(t, "'+1 営業日以内に対応します", records[1][1])
+	assert.Equal(t, "'-", records[1][4])
+	assert.Equal(t, "'@admin", records[1][9])
+	assert.Equal(t, "", records[1][2])
+
+	// 取り込み直すと元の値に戻る
+	imported, err := parseKnowledgeCSV(strings.NewReader(content))
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+	assert.Equal(t, "=HYPERLINK(\"http://example.com\")", imported[0].Question)
+	assert.Equal(t, "+1 営業日以内に対応します", imported[0].Answer)
+}
+
+func TestEscapeCSVFormula_RoundTrip(t *testing.T) {
+	tests := []struct {
+		value   string
+		escaped string
+	}{
+		{value: "\t=cmd", escaped: "'\t=cmd"},
+		{value: "\r=cmd", escaped: "'\r=cmd"},
+		// 元から'で始まる値も取り込み直したときに区別できるようエスケープする
+		{value: "'=1+1", escaped: "''=1+1"},
+		{value: "'引用'", escaped: "''引用'"},
+		{value: "'", escaped: "''"},
+		{value: "はい", escaped: "はい"},
+		{value: "", escaped: ""},
+	}
+
+	for _, tt := range tests {
+		escaped := escapeCSVFormula(tt.value)
+		assert.Equal(t, tt.escaped, escaped)
+		assert.Equal(t, tt.value, unescapeCSVFormula(escaped))
+	}
+
+	// エスケープの対象でない値の先頭の'は手入力の値として残す
+	assert.Equal(t, "'引用", unescapeCSVFormula("'引用"))
+}
+
+func TestExportOptions_Validate(t *testing.T) {
+	assert.NoError(t, ExportOptions{}.Validate())
+	assert.NoError(t, ExportOptions{Encoding: EncodingUTF8BOM}.Validate())
+	assert.Error(t, ExportOptions{Encoding: "shift_jis"}.Validate())
+}
+
+func TestExportUseCase_ExportProjectKnowledge_WithEvidence(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	evidenceRepo := new(MockEvidenceRepository)
+	usecase := NewExportUseCase(knowledgeRepo, projectRepo, nil, nil, evidenceRepo)
+
+	policyPath := filepath.Join(t.TempDir(), "20260101_000000_policy.pdf")
+	require.NoError(t, os.WriteFile(policyPath, []byte("%PDF"), 0644))
+	expired := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := &domain.EvidenceFile{ID: 3, FileName: "policy.pdf", FilePath: policyPath}
+	oldCert := &domain.EvidenceFile{ID: 4, FileName: "cert.pdf", FilePath: "/nonexistent/cert.pdf", ExpiresAt: &expired}
+
+	items := []*domain.KnowledgeItem{
+		{ID: 1, ProjectID: 5, Question: "情報セキュリティ方針はありますか？", Answer: "はい"},
+		{ID: 2, ProjectID: 5, Question: "第三者認証を取得していますか？", Answer: "はい"},
+	}
+	projectRepo.On("GetByID", 5).Return(&domain.Project{ID: 5, CustomerName: "テスト株式会社"}, nil)
+	knowledgeRepo.On("GetByProjectID", 5, domain.PageRequest{}).Return(items, 2, nil)
+	evidenceRepo.On("GetByKnowledgeIDs", []int{1, 2}).Return(map[int][]*domain.EvidenceFile{
+		1: {policy},
+		2: {policy, oldCert},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := usecase.ExportProjectKnowledge(5, &buf, ExportOptions{Encoding: EncodingUTF8, WithEvidence: true})
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	// 複数のアイテムから参照されるエビデンスは1つだけ同梱し、期限切れのものは同梱しない
+	require.Len(t, zr.File, 2)
+	assert.Equal(t, "knowledge.csv", zr.File[0].Name)
+	assert.Equal(t, "evidence/3_policy.pdf", zr.File[1].Name)
+
+	f, err := zr.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "evidence", records[0][len(records[0])-1])
+	assert.Equal(t, "evidence/3_policy.pdf", records[1][13])
+	assert.Equal(t, "evidence/3_policy.pdf\ncert.pdf（期限切れ: 2020-01-01）", records[2][13])
+}
+
+func TestExportUseCase_ExportProjectKnowledge_WithMissingEvidenceFile(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	evidenceRepo := new(MockEvidenceRepository)
+	usecase := NewExportUseCase(knowledgeRepo, projectRepo, nil, nil, evidenceRepo)
+
+	policyPath := filepath.Join(t.TempDir(), "20260101_000000_policy.pdf")
+	require.NoError(t, os.WriteFile(policyPath, []byte("%PDF"), 0644))
+	policy := &domain.EvidenceFile{ID: 3, FileName: "policy.pdf", FilePath: policyPath}
+	missing := &domain.EvidenceFile{ID: 4, FileName: "cert.pdf", FilePath: filepath.Join(t.TempDir(), "cert.pdf")}
+
+	items := []*domain.KnowledgeItem{
+		{ID: 1, ProjectID: 5, Question: "情報セキュリティ方針はありますか？", Answer: "はい"},
+	}
+	projectRepo.On("GetByID", 5).Return(&domain.Project{ID: 5, CustomerName: "テスト株式会社"}, nil)
+	knowledgeRepo.On("GetByProjectID", 5, domain.PageRequest{}).Return(items, 1, nil)
+	evidenceRepo.On("GetByKnowledgeIDs", []int{1}).Return(map[int][]*domain.EvidenceFile{
+		1: {policy, missing},
+	}, nil)
+
+	// 開けないファイルがある場合は何も書き出さずにエラーを返す
+	var buf bytes.Buffer
+	err := usecase.ExportProjectKnowledge(5, &buf, ExportOptions{Encoding: EncodingUTF8, WithEvidence: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ID: 4")
+	assert.Zero(t, buf.Len())
+}
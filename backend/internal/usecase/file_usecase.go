@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -15,7 +17,7 @@ import (
 type FileUseCase interface {
 	UploadFile(projectID int, fileHeader *multipart.FileHeader, uploadedBy string) (*domain.UploadedFile, error)
 	GetFile(id int) (*domain.UploadedFile, error)
-	GetFilesByProject(projectID int) ([]*domain.UploadedFile, error)
+	GetFilesByProject(projectID int, page domain.PageRequest) (*domain.Page[*domain.UploadedFile], error)
 	DeleteFile(id int) error
 }
 
@@ -43,32 +45,9 @@ func (u *FileUseCaseImpl) UploadFile(projectID int, fileHeader *multipart.FileHe
 		return nil, fmt.Errorf("案件が存在しません: %w", err)
 	}
 
-	// アップロードディレクトリの作成
-	uploadDir := filepath.Join(u.uploadBasePath, fmt.Sprintf("project_%d", projectID))
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return nil, fmt.Errorf("アップロードディレクトリの作成に失敗しました: %w", err)
-	}
-
-	// ファイル名の重複を避けるためにタイムスタンプを付与
-	timestamp := time.Now().Format("20060102_150405")
-	fileName := fmt.Sprintf("%s_%s", timestamp, fileHeader.Filename)
-	filePath := filepath.Join(uploadDir, fileName)
-
-	// ファイルの保存
-	src, err := fileHeader.Open()
-	if err != nil {
-		return nil, fmt.Errorf("ファイルのオープンに失敗しました: %w", err)
-	}
-	defer src.Close()
-
-	dst, err := os.Create(filePath)
+	filePath, err := saveUpload(filepath.Join(u.uploadBasePath, fmt.Sprintf("project_%d", projectID)), fileHeader)
 	if err != nil {
-		return nil, fmt.Errorf("ファイルの作成に失敗しました: %w", err)
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, src); err != nil {
-		return nil, fmt.Errorf("ファイルのコピーに失敗しました: %w", err)
+		return nil, err
 	}
 
 	// ファイル情報をDBに保存
@@ -100,31 +79,80 @@ func (u *FileUseCaseImpl) GetFile(id int) (*domain.UploadedFile, error) {
 	return u.fileRepo.GetByID(id)
 }
 
-// GetFilesByProject は指定された案件のすべてのファイルを取得する
-func (u *FileUseCaseImpl) GetFilesByProject(projectID int) ([]*domain.UploadedFile, error) {
-	return u.fileRepo.GetByProjectID(projectID)
+// GetFilesByProject は指定された案件のファイル一覧をページ単位で取得する
+func (u *FileUseCaseImpl) GetFilesByProject(projectID int, page domain.PageRequest) (*domain.Page[*domain.UploadedFile], error) {
+	if err := page.Normalize(domain.FileSortFields); err != nil {
+		return nil, err
+	}
+
+	files, total, err := u.fileRepo.GetByProjectID(projectID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPage(files, total, page), nil
 }
 
-// DeleteFile はファイルを削除する
+// DeleteFile はファイルをゴミ箱に移す
+// 復元できるよう物理ファイルは残し、保持期間の経過後にTrashUseCase.PurgeExpiredで削除する
 func (u *FileUseCaseImpl) DeleteFile(id int) error {
-	// ファイル情報を取得
-	file, err := u.fileRepo.GetByID(id)
-	if err != nil {
+	// 存在確認
+	if _, err := u.fileRepo.GetByID(id); err != nil {
 		return fmt.Errorf("ファイルが見つかりません: %w", err)
 	}
 
-	// DBから削除
 	if err := u.fileRepo.Delete(id); err != nil {
 		return fmt.Errorf("ファイル情報の削除に失敗しました: %w", err)
 	}
 
-	// 物理ファイルを削除
-	if err := os.Remove(file.FilePath); err != nil {
+	return nil
+}
+
+// saveUpload はアップロードされたファイルをuploadDirに保存し、保存先のパスを返す
+// 案件のファイルとエビデンスで同じ保存方式を使う
+func saveUpload(uploadDir string, fileHeader *multipart.FileHeader) (string, error) {
+	// アップロードディレクトリの作成
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return "", fmt.Errorf("アップロードディレクトリの作成に失敗しました: %w", err)
+	}
+
+	// 同じ秒に同名のファイルがアップロードされても保存先が重ならないよう、タイムスタンプとランダムな値を付与
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("ファイル名の生成に失敗しました: %w", err)
+	}
+	timestamp := time.Now().Format("20060102_150405")
+	fileName := fmt.Sprintf("%s_%s_%s", timestamp, hex.EncodeToString(suffix), filepath.Base(fileHeader.Filename))
+	filePath := filepath.Join(uploadDir, fileName)
+
+	// ファイルの保存
+	src, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("ファイルのオープンに失敗しました: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("ファイルの作成に失敗しました: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(filePath)
+		return "", fmt.Errorf("ファイルのコピーに失敗しました: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// removeStoredFile は保存済みのファイルを削除する（既に存在しない場合は何もしない）
+func removeStoredFile(filePath string) error {
+	if err := os.Remove(filePath); err != nil {
 		// ファイルが既に存在しない場合はエラーを無視
 		if !os.IsNotExist(err) {
 			return fmt.Errorf("物理ファイルの削除に失敗しました: %w", err)
 		}
 	}
-
 	return nil
 }
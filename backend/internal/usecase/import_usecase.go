@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// インポート行の処理結果
+const (
+	ImportRowOK        = "ok"
+	ImportRowCreated   = "created"
+	ImportRowDuplicate = "duplicate"
+	ImportRowError     = "error"
+)
+
+// ImportOptions はCSVインポートのオプション
+type ImportOptions struct {
+	ProjectID       int
+	CreatedBy       string
+	DryRun          bool
+	AllowDuplicates bool
+}
+
+// ImportRowResult はインポート1行分の処理結果
+type ImportRowResult struct {
+	Row         int    `json:"row"`
+	Question    string `json:"question"`
+	Result      string `json:"result"`
+	Error       string `json:"error,omitempty"`
+	ID          int    `json:"id,omitempty"`
+	DuplicateOf *int   `json:"duplicate_of,omitempty"`
+}
+
+// ImportResult はインポート全体の処理結果
+type ImportResult struct {
+	DryRun     bool              `json:"dry_run"`
+	Total      int               `json:"total"`
+	Created    int               `json:"created"`
+	Duplicates int               `json:"duplicates"`
+	Failed     int               `json:"failed"`
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// importRecord はCSV・Excelから読み取った1行分のデータ
+type importRecord struct {
+	Row           int
+	Question      string
+	Answer        string
+	Department    string
+	SheetName     string
+	SourceRange   string
+	QuestionGroup string
+	CreatedBy     string
+}
+
+// SheetReader はExcelファイルのシートをセルの値の行列として読み取る
+type SheetReader interface {
+	ReadSheetRows(filePath string, sheetName *string) ([][]string, error)
+}
+
+// ImportUseCase はナレッジのインポートに関するビジネスロジックを提供する
+type ImportUseCase interface {
+	ImportKnowledge(r io.Reader, opts ImportOptions) (*ImportResult, error)
+	ImportKnowledgeXLSX(r io.Reader, opts ImportOptions) (*ImportResult, error)
+}
+
+// ImportUseCaseImpl はImportUseCaseの実装
+type ImportUseCaseImpl struct {
+	knowledgeUseCase KnowledgeUseCase
+	departmentRepo   domain.DepartmentRepository
+	sheetReader      SheetReader
+	uploadBasePath   string
+}
+
+// NewImportUseCase は新しいImportUseCaseを生成する
+// ExcelファイルはExcel処理APIから読めるよう、uploadBasePathに一時的に保存してから読み取る
+func NewImportUseCase(knowledgeUseCase KnowledgeUseCase, departmentRepo domain.DepartmentRepository, sheetReader SheetReader, uploadBasePath string) ImportUseCase {
+	return &ImportUseCaseImpl{
+		knowledgeUseCase: knowledgeUseCase,
+		departmentRepo:   departmentRepo,
+		sheetReader:      sheetReader,
+		uploadBasePath:   uploadBasePath,
+	}
+}
+
+// ImportKnowledge はエクスポートと同じスキーマのCSVからナレッジを取り込む
+func (u *ImportUseCaseImpl) ImportKnowledge(r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	if opts.ProjectID == 0 {
+		return nil, &domain.ValidationError{Field: "project_id", Message: "取込先の案件IDは必須です"}
+	}
+
+	records, err := parseKnowledgeCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.importRecords(records, opts)
+}
+
+// ImportKnowledgeXLSX はエクスポートしたCSVと同じ列を持つExcelファイル（最初のシート）からナレッジを取り込む
+func (u *ImportUseCaseImpl) ImportKnowledgeXLSX(r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	if opts.ProjectID == 0 {
+		return nil, &domain.ValidationError{Field: "project_id", Message: "取込先の案件IDは必須です"}
+	}
+
+	if err := os.MkdirAll(u.uploadBasePath, 0755); err != nil {
+		return nil, fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+	tmp, err := os.CreateTemp(u.uploadBasePath, "import_*.xlsx")
+	if err != nil {
+		return nil, fmt.Errorf("一時ファイルの作成に失敗しました: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("一時ファイルの保存に失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("一時ファイルの保存に失敗しました: %w", err)
+	}
+
+	rows, err := u.sheetReader.ReadSheetRows(tmp.Name(), nil)
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("Excelファイルの読み取りに失敗しました: %v", err)}
+	}
+
+	records, err := parseKnowledgeSheet(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.importRecords(records, opts)
+}
+
+// importRecords は読み取った行を検証し、重複していないものをナレッジとして登録する
+func (u *ImportUseCaseImpl) importRecords(records []importRecord, opts ImportOptions) (*ImportResult, error) {
+	// 取込先案件の既存質問（案件の存在確認を兼ねる）
+	existing, err := u.knowledgeUseCase.GetKnowledgeByProject(opts.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	// 表記揺れ（全角・半角、空白や改行の違い）を同じ質問として扱う
+	questions := make(map[string]int, len(existing))
+	for _, item := range existing {
+		questions[domain.NormalizeForSearch(item.Question)] = item.ID
+	}
+
+	// 部門名から部門IDへの対応表
+	departments, err := u.departmentRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("部門の取得に失敗しました: %w", err)
+	}
+	departmentIDs := make(map[string]int, len(departments))
+	for _, dept := range departments {
+		departmentIDs[dept.Name] = dept.ID
+	}
+
+	result := &ImportResult{
+		DryRun: opts.DryRun,
+		Total:  len(records),
+		Rows:   make([]ImportRowResult, 0, len(records)),
+	}
+	// ファイル内での重複検出用
+	seen := make(map[string]bool)
+
+	for _, rec := range records {
+		row := ImportRowResult{Row: rec.Row, Question: rec.Question}
+
+		item, err := rec.toKnowledgeItem(opts, departmentIDs)
+		if err == nil {
+			err = item.Validate()
+		}
+		if err != nil {
+			row.Result = ImportRowError
+			row.Error = err.Error()
+			result.Failed++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+
+		key := domain.NormalizeForSearch(item.Question)
+		if !opts.AllowDuplicates {
+			if id, ok := questions[key]; ok {
+				row.Result = ImportRowDuplicate
+				row.DuplicateOf = &id
+				result.Duplicates++
+				result.Rows = append(result.Rows, row)
+				continue
+			}
+			if seen[key] {
+				row.Result = ImportRowDuplicate
+				row.Error = "ファイル内で質問が重複しています"
+				result.Duplicates++
+				result.Rows = append(result.Rows, row)
+				continue
+			}
+		}
+
+		if opts.DryRun {
+			row.Result = ImportRowOK
+			seen[key] = true
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+
+		if err := u.knowledgeUseCase.CreateKnowledge(item); err != nil {
+			row.Result = ImportRowError
+			row.Error = err.Error()
+			result.Failed++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+
+		row.Result = ImportRowCreated
+		row.ID = item.ID
+		seen[key] = true
+		result.Created++
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// toKnowledgeItem はCSVの行をナレッジアイテムに変換する
+// 取り込んだアイテムは承認フローを経るため、CSVのstatus列に関わらず下書きとして作成する
+func (rec importRecord) toKnowledgeItem(opts ImportOptions, departmentIDs map[string]int) (*domain.KnowledgeItem, error) {
+	var departmentID *int
+	if rec.Department != "" {
+		id, ok := departmentIDs[rec.Department]
+		if !ok {
+			return nil, fmt.Errorf("部門が見つかりません: %s", rec.Department)
+		}
+		departmentID = &id
+	}
+
+	createdBy := rec.CreatedBy
+	if createdBy == "" {
+		createdBy = opts.CreatedBy
+	}
+
+	item := domain.NewKnowledgeItem(
+		opts.ProjectID,
+		nil,
+		rec.SheetName,
+		rec.SourceRange,
+		rec.Question,
+		rec.Answer,
+		departmentID,
+		createdBy,
+	)
+	item.QuestionGroup = rec.QuestionGroup
+	item.Status = domain.StatusDraft
+
+	return item, nil
+}
+
+// parseKnowledgeCSV はナレッジCSVを読み取る（列はヘッダー名で対応付ける）
+func parseKnowledgeCSV(r io.Reader) ([]importRecord, error) {
+	table, err := newCSVTable(r, "question")
+	if err != nil {
+		return nil, err
+	}
+
+	records := []importRecord{}
+	for {
+		row, err := table.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, newImportRecord(row.line, func(name string) string {
+			return unescapeCSVFormula(row.value(name))
+		}))
+	}
+
+	return records, nil
+}
+
+// parseKnowledgeSheet はExcelのシートをナレッジCSVと同じ列名で読み取る（1行目をヘッダーとする）
+// Excelは数式の無効化に付けた先頭の'を値に含めないため、CSVと異なり値はそのまま使う
+func parseKnowledgeSheet(rows [][]string) ([]importRecord, error) {
+	if len(rows) == 0 {
+		return nil, &domain.ValidationError{Field: "file", Message: "シートが空です"}
+	}
+
+	columns, err := columnIndex(rows[0], "question")
+	if err != nil {
+		return nil, err
+	}
+
+	records := []importRecord{}
+	for i, fields := range rows[1:] {
+		row := tableRow{line: i + 2, fields: fields, columns: columns}
+		// 書式だけが残った空行は読み飛ばす
+		if row.isBlank() {
+			continue
+		}
+		records = append(records, newImportRecord(row.line, row.value))
+	}
+
+	return records, nil
+}
+
+// newImportRecord は列名から値を取り出す関数を使って1行分のデータを組み立てる
+func newImportRecord(line int, value func(name string) string) importRecord {
+	return importRecord{
+		Row:           line,
+		Question:      value("question"),
+		Answer:        value("answer"),
+		Department:    value("department"),
+		SheetName:     value("sheet_name"),
+		SourceRange:   value("source_range"),
+		QuestionGroup: value("question_group"),
+		CreatedBy:     value("created_by"),
+	}
+}
+
+// csvTable はヘッダー行の列名で各行の値を取り出すCSVリーダー
+type csvTable struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// tableRow はヘッダー行の列名で値を取り出せる1行分のデータ
+type tableRow struct {
+	line    int
+	fields  []string
+	columns map[string]int
+}
+
+// newCSVTable はCSVのヘッダー行を読み取り、必須の列があることを確認する（BOM付きUTF-8にも対応する）
+func newCSVTable(r io.Reader, required ...string) (*csvTable, error) {
+	br := bufio.NewReader(r)
+	if head, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(head, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &domain.ValidationError{Field: "file", Message: "CSVが空です"}
+		}
+		return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("CSVの読み取りに失敗しました: %v", err)}
+	}
+
+	columns, err := columnIndex(header, required...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvTable{reader: reader, columns: columns}, nil
+}
+
+// columnIndex はヘッダー行から列名と列番号の対応表を作り、必須の列があることを確認する
+// 列名は大文字・小文字と前後の空白を区別しない
+func columnIndex(header []string, required ...string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("ヘッダー行に%s列がありません", name)}
+		}
+	}
+
+	return columns, nil
+}
+
+// next は次の行を読み取る。最後の行を読み終えた後はio.EOFを返す
+func (t *csvTable) next() (tableRow, error) {
+	fields, err := t.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return tableRow{}, err
+		}
+		return tableRow{}, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("CSVの読み取りに失敗しました: %v", err)}
+	}
+
+	// セル内改行を含む場合も元ファイルの行番号を報告する
+	line, _ := t.reader.FieldPos(0)
+	return tableRow{line: line, fields: fields, columns: t.columns}, nil
+}
+
+// value は列名に対応する値を前後の空白を除いて返す。列がない場合は空文字を返す
+func (r tableRow) value(name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+// isBlank はすべての値が空の行かどうかを判定する
+func (r tableRow) isBlank() bool {
+	for _, field := range r.fields {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
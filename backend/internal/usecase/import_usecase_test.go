@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSheetReader はSheetReaderのモック
+type MockSheetReader struct {
+	mock.Mock
+}
+
+func (m *MockSheetReader) ReadSheetRows(filePath string, sheetName *string) ([][]string, error) {
+	args := m.Called(filePath, sheetName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]string), args.Error(1)
+}
+
+func TestParseKnowledgeCSV(t *testing.T) {
+	input := "\xEF\xBB\xBFquestion,answer,department,sheet_name,source_range,status\r\n" +
+		"アクセス制御は適切ですか？,\"はい、\n実施しています\",セキュリティ,シート1,A1:B2,draft\r\n" +
+		"バックアップは取得していますか？,はい,,シート1,A3:B3,\r\n"
+
+	records, err := parseKnowledgeCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, 2, records[0].Row)
+	assert.Equal(t, "アクセス制御は適切ですか？", records[0].Question)
+	assert.Equal(t, "はい、\n実施しています", records[0].Answer)
+	assert.Equal(t, "セキュリティ", records[0].Department)
+	assert.Equal(t, "A1:B2", records[0].SourceRange)
+
+	// セル内改行があっても元ファイルの行番号になる
+	assert.Equal(t, 4, records[1].Row)
+	assert.Equal(t, "", records[1].Department)
+}
+
+func TestParseKnowledgeCSV_MissingQuestionColumn(t *testing.T) {
+	_, err := parseKnowledgeCSV(strings.NewReader("answer,department\r\nはい,法務\r\n"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "question列がありません")
+}
+
+func TestParseKnowledgeCSV_Empty(t *testing.T) {
+	_, err := parseKnowledgeCSV(strings.NewReader(""))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "CSVが空です")
+}
+
+func TestImportRecord_ToKnowledgeItem_AlwaysDraft(t *testing.T) {
+	input := "question,answer,status\r\n" +
+		"暗号化していますか？,はい,published\r\n"
+
+	records, err := parseKnowledgeCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	// status列があっても承認フローを経ずに公開されないよう下書きとして取り込む
+	item, err := records[0].toKnowledgeItem(ImportOptions{ProjectID: 1, CreatedBy: "山田太郎"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusDraft, item.Status)
+}
+
+func TestImportUseCase_ImportKnowledge_NormalizedDuplicates(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	departmentRepo := new(MockDepartmentRepository)
+	knowledgeUseCase := NewKnowledgeUseCase(knowledgeRepo, projectRepo, answerRepo, departmentRepo)
+	usecase := NewImportUseCase(knowledgeUseCase, departmentRepo, new(MockSheetReader), t.TempDir())
+
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1, CustomerName: "テスト株式会社"}, nil)
+	knowledgeRepo.On("GetByProjectID", 1, domain.PageRequest{}).Return([]*domain.KnowledgeItem{
+		{ID: 10, ProjectID: 1, Question: "ＰＣの暗号化を\n実施していますか？"},
+	}, 1, nil)
+	answerRepo.On("GetByKnowledgeIDs", []int{10}).Return(map[int][]*domain.KnowledgeAnswer{}, nil)
+	departmentRepo.On("GetAll").Return([]*domain.Department{}, nil)
+
+	// 全角・半角や改行・空白の違いだけの質問は既存・ファイル内のどちらでも重複とみなす
+	input := "question,answer\r\n" +
+		"PCの暗号化を 実施していますか？,はい\r\n" +
+		"バックアップを  取得していますか？,はい\r\n" +
+		"\"バックアップを　\n取得していますか？\",はい\r\n"
+
+	result, err := usecase.ImportKnowledge(strings.NewReader(input), ImportOptions{ProjectID: 1, CreatedBy: "山田太郎", DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 3)
+	assert.Equal(t, ImportRowDuplicate, result.Rows[0].Result)
+	require.NotNil(t, result.Rows[0].DuplicateOf)
+	assert.Equal(t, 10, *result.Rows[0].DuplicateOf)
+	assert.Equal(t, ImportRowOK, result.Rows[1].Result)
+	assert.Equal(t, ImportRowDuplicate, result.Rows[2].Result)
+	assert.Equal(t, 2, result.Duplicates)
+}
+
+func TestParseKnowledgeSheet(t *testing.T) {
+	rows := [][]string{
+		{"Question", "answer", "department"},
+		{"アクセス制御は適切ですか？", "'=はい", "セキュリティ"},
+		{"", "", ""},
+		{"バックアップは取得していますか？", "はい", ""},
+	}
+
+	records, err := parseKnowledgeSheet(rows)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, 2, records[0].Row)
+	assert.Equal(t, "アクセス制御は適切ですか？", records[0].Question)
+	// Excelのセルの値はCSVの数式エスケープを解除しない
+	assert.Equal(t, "'=はい", records[0].Answer)
+	assert.Equal(t, "セキュリティ", records[0].Department)
+
+	// 空行を読み飛ばしてもシートの行番号を報告する
+	assert.Equal(t, 4, records[1].Row)
+}
+
+func TestParseKnowledgeSheet_MissingQuestionColumn(t *testing.T) {
+	_, err := parseKnowledgeSheet([][]string{{"answer"}, {"はい"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "question列がありません")
+}
+
+func TestImportUseCase_ImportKnowledgeXLSX(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	departmentRepo := new(MockDepartmentRepository)
+	sheetReader := new(MockSheetReader)
+	uploadDir := t.TempDir()
+	knowledgeUseCase := NewKnowledgeUseCase(knowledgeRepo, projectRepo, answerRepo, departmentRepo)
+	usecase := NewImportUseCase(knowledgeUseCase, departmentRepo, sheetReader, uploadDir)
+
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1, CustomerName: "テスト株式会社"}, nil)
+	knowledgeRepo.On("GetByProjectID", 1, domain.PageRequest{}).Return([]*domain.KnowledgeItem{}, 0, nil)
+	answerRepo.On("GetByKnowledgeIDs", []int{}).Return(map[int][]*domain.KnowledgeAnswer{}, nil)
+	departmentRepo.On("GetAll").Return([]*domain.Department{}, nil)
+
+	var savedPath string
+	sheetReader.On("ReadSheetRows", mock.MatchedBy(func(path string) bool {
+		// Excel処理APIから読めるようアップロード先に保存したファイルを渡す
+		data, err := os.ReadFile(path)
+		savedPath = path
+		return err == nil && string(data) == "xlsx" && strings.HasPrefix(path, uploadDir)
+	}), (*string)(nil)).Return([][]string{
+		{"question", "answer"},
+		{"暗号化していますか？", "はい"},
+	}, nil)
+
+	result, err := usecase.ImportKnowledgeXLSX(strings.NewReader("xlsx"), ImportOptions{ProjectID: 1, CreatedBy: "山田太郎", DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, ImportRowOK, result.Rows[0].Result)
+	assert.Equal(t, "暗号化していますか？", result.Rows[0].Question)
+
+	// 読み取り後は一時ファイルを残さない
+	_, err = os.Stat(savedPath)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -1,7 +1,9 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/security-checksheets/backend/internal/domain"
 )
@@ -11,52 +13,136 @@ type KnowledgeUseCase interface {
 	CreateKnowledge(item *domain.KnowledgeItem) error
 	GetKnowledge(id int) (*domain.KnowledgeItem, error)
 	GetKnowledgeByProject(projectID int) ([]*domain.KnowledgeItem, error)
-	UpdateKnowledge(item *domain.KnowledgeItem) error
+	ListKnowledgeByProject(projectID int, page domain.PageRequest) (*domain.Page[*domain.KnowledgeItem], error)
+	// UpdateKnowledge は指定された項目だけを更新し、更新後のアイテムを返す
+	UpdateKnowledge(id int, patch domain.KnowledgePatch, expectedVersion int) (*domain.KnowledgeItem, error)
 	DeleteKnowledge(id int) error
-	SearchKnowledge(query string, filters map[string]interface{}) ([]*domain.KnowledgeItem, error)
-	BulkCreateKnowledge(items []*domain.KnowledgeItem) error
+	SearchKnowledge(query string, filter domain.KnowledgeSearchFilter, page domain.PageRequest) (*domain.KnowledgeSearchResult, error)
+	FindSimilarKnowledge(text string, opts domain.SimilarSearchOptions) ([]*domain.ScoredKnowledgeItem, error)
+	BulkCreateKnowledge(items []*domain.KnowledgeItem, mode string) (*BulkCreateResult, error)
+	// BulkUpdateKnowledge は複数のアイテムに同じ変更を1トランザクションで反映する
+	BulkUpdateKnowledge(ids []int, patch domain.KnowledgeBulkPatch, mode string, actor string) (*BulkResult, error)
+	// BulkDeleteKnowledge は複数のアイテムを1トランザクションで削除する
+	BulkDeleteKnowledge(ids []int, mode string) (*BulkResult, error)
+}
+
+// 一括作成のモード
+const (
+	// BulkModeAllOrNothing は1件でも失敗した場合に全件を取り消す
+	BulkModeAllOrNothing = "all_or_nothing"
+	// BulkModeBestEffort は失敗した項目を除いて作成する
+	BulkModeBestEffort = "best_effort"
+)
+
+// 一括処理の項目ごとの結果
+const (
+	BulkItemCreated = "created"
+	BulkItemUpdated = "updated"
+	BulkItemDeleted = "deleted"
+	BulkItemFailed  = "failed"
+	BulkItemSkipped = "skipped"
+)
+
+// BulkItemResult は一括作成の項目ごとの結果
+type BulkItemResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkCreateResult は一括作成全体の結果
+type BulkCreateResult struct {
+	Mode    string           `json:"mode"`
+	Created int              `json:"created"`
+	Failed  int              `json:"failed"`
+	Results []BulkItemResult `json:"results"`
+}
+
+// BulkIDResult は既存のアイテムに対する一括処理のIDごとの結果
+type BulkIDResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	// Version は更新後の版（更新した場合のみ）
+	Version int    `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkResult は既存のアイテムに対する一括更新・一括削除全体の結果
+type BulkResult struct {
+	Mode      string         `json:"mode"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Results   []BulkIDResult `json:"results"`
 }
 
 // KnowledgeUseCaseImpl はKnowledgeUseCaseの実装
 type KnowledgeUseCaseImpl struct {
-	knowledgeRepo domain.KnowledgeRepository
-	projectRepo   domain.ProjectRepository
+	knowledgeRepo  domain.KnowledgeRepository
+	projectRepo    domain.ProjectRepository
+	answerRepo     domain.KnowledgeAnswerRepository
+	departmentRepo domain.DepartmentRepository
 }
 
 // NewKnowledgeUseCase は新しいKnowledgeUseCaseを生成する
 func NewKnowledgeUseCase(
 	knowledgeRepo domain.KnowledgeRepository,
 	projectRepo domain.ProjectRepository,
+	answerRepo domain.KnowledgeAnswerRepository,
+	departmentRepo domain.DepartmentRepository,
 ) KnowledgeUseCase {
 	return &KnowledgeUseCaseImpl{
-		knowledgeRepo: knowledgeRepo,
-		projectRepo:   projectRepo,
+		knowledgeRepo:  knowledgeRepo,
+		projectRepo:    projectRepo,
+		answerRepo:     answerRepo,
+		departmentRepo: departmentRepo,
 	}
 }
 
 // CreateKnowledge はナレッジアイテムを作成する
 func (u *KnowledgeUseCaseImpl) CreateKnowledge(item *domain.KnowledgeItem) error {
 	// 案件の存在確認
-	_, err := u.projectRepo.GetByID(item.ProjectID)
+	project, err := u.projectRepo.GetByID(item.ProjectID)
 	if err != nil {
 		return fmt.Errorf("案件が存在しません: %w", err)
 	}
 
+	// 回答バリエーションがあれば主回答を合成する
+	if len(item.Answers) > 0 {
+		if err := item.SetAnswers(item.Answers); err != nil {
+			return err
+		}
+	}
+
 	// バリデーション
 	if err := item.Validate(); err != nil {
 		return err
 	}
+	if err := item.ValidatePlaceholders(project); err != nil {
+		return err
+	}
 
 	// 作成
 	return u.knowledgeRepo.Create(item)
 }
 
-// GetKnowledge はナレッジアイテムを取得する
+// GetKnowledge はナレッジアイテムを回答バリエーションとともに取得する
 func (u *KnowledgeUseCaseImpl) GetKnowledge(id int) (*domain.KnowledgeItem, error) {
-	return u.knowledgeRepo.GetByID(id)
+	item, err := u.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := u.answerRepo.GetByKnowledgeID(id)
+	if err != nil {
+		return nil, fmt.Errorf("回答バリエーションの取得に失敗しました: %w", err)
+	}
+	item.Answers = answers
+
+	return item, nil
 }
 
-// GetKnowledgeByProject は案件に紐づくナレッジアイテムを取得する
+// GetKnowledgeByProject は案件に紐づくすべてのナレッジアイテムを取得する
 func (u *KnowledgeUseCaseImpl) GetKnowledgeByProject(projectID int) ([]*domain.KnowledgeItem, error) {
 	// 案件の存在確認
 	_, err := u.projectRepo.GetByID(projectID)
@@ -64,24 +150,131 @@ func (u *KnowledgeUseCaseImpl) GetKnowledgeByProject(projectID int) ([]*domain.K
 		return nil, fmt.Errorf("案件が存在しません: %w", err)
 	}
 
-	return u.knowledgeRepo.GetByProjectID(projectID)
+	items, _, err := u.knowledgeRepo.GetByProjectID(projectID, domain.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attachAnswers(items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
 }
 
-// UpdateKnowledge はナレッジアイテムを更新する
-func (u *KnowledgeUseCaseImpl) UpdateKnowledge(item *domain.KnowledgeItem) error {
+// ListKnowledgeByProject は案件に紐づくナレッジアイテムをページ単位で取得する
+func (u *KnowledgeUseCaseImpl) ListKnowledgeByProject(projectID int, page domain.PageRequest) (*domain.Page[*domain.KnowledgeItem], error) {
+	if err := page.Normalize(domain.KnowledgeSortFields); err != nil {
+		return nil, err
+	}
+
+	// 案件の存在確認
+	if _, err := u.projectRepo.GetByID(projectID); err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	items, total, err := u.knowledgeRepo.GetByProjectID(projectID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attachAnswers(items); err != nil {
+		return nil, err
+	}
+
+	return domain.NewPage(items, total, page), nil
+}
+
+// attachAnswers は複数のナレッジアイテムに回答バリエーションをまとめて読み込む
+func (u *KnowledgeUseCaseImpl) attachAnswers(items []*domain.KnowledgeItem) error {
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	answers, err := u.answerRepo.GetByKnowledgeIDs(ids)
+	if err != nil {
+		return fmt.Errorf("回答バリエーションの取得に失敗しました: %w", err)
+	}
+
+	for _, item := range items {
+		item.Answers = answers[item.ID]
+	}
+
+	return nil
+}
+
+// UpdateKnowledge はナレッジアイテムの指定された項目だけを更新し、更新後のアイテムを返す
+// expectedVersionはクライアントが取得した時点の版で、サーバー上の版と異なる場合は
+// 最新の内容を含むdomain.VersionConflictErrorを返す。版はサーバー側で進める
+func (u *KnowledgeUseCaseImpl) UpdateKnowledge(id int, patch domain.KnowledgePatch, expectedVersion int) (*domain.KnowledgeItem, error) {
 	// 存在確認
-	_, err := u.knowledgeRepo.GetByID(item.ID)
+	item, err := u.knowledgeRepo.GetByID(id)
 	if err != nil {
-		return fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	if item.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{Current: item}
+	}
+
+	// 回答バリエーション: answersが指定されれば置き換え、なければ現在の内容を維持する
+	replaceAnswers := patch.Answers != nil
+	if !replaceAnswers {
+		answers, err := u.answerRepo.GetByKnowledgeID(id)
+		if err != nil {
+			return nil, fmt.Errorf("回答バリエーションの取得に失敗しました: %w", err)
+		}
+		// バリエーションがある場合、主回答はバリエーションから合成されるため直接は変更できない
+		if len(answers) > 0 && patch.Answer != nil && *patch.Answer != item.Answer {
+			return nil, &domain.ValidationError{Field: "answer", Message: "回答バリエーションがあるため、answersで回答を編集してください"}
+		}
+		item.Answers = answers
+	}
+
+	if err := item.ApplyPatch(patch); err != nil {
+		return nil, err
 	}
 
 	// バリデーション
 	if err := item.Validate(); err != nil {
-		return err
+		return nil, err
+	}
+	// プレースホルダーは案件の項目・案件変数で解決できるものに限る
+	if domain.HasPlaceholders(item.Answer) {
+		project, err := u.projectRepo.GetByID(item.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("案件が存在しません: %w", err)
+		}
+		if err := item.ValidatePlaceholders(project); err != nil {
+			return nil, err
+		}
 	}
 
 	// 更新
-	return u.knowledgeRepo.Update(item)
+	item.Version = expectedVersion + 1
+	if replaceAnswers {
+		err = u.answerRepo.Replace(item, expectedVersion)
+	} else {
+		err = u.knowledgeRepo.Update(item, expectedVersion)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return nil, u.versionConflict(id)
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// versionConflict は最新の内容を取得して版の競合エラーを返す
+func (u *KnowledgeUseCaseImpl) versionConflict(id int) error {
+	current, err := u.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+	return &domain.VersionConflictError{Current: current}
 }
 
 // DeleteKnowledge はナレッジアイテムを削除する
@@ -95,24 +288,325 @@ func (u *KnowledgeUseCaseImpl) DeleteKnowledge(id int) error {
 	return u.knowledgeRepo.Delete(id)
 }
 
-// SearchKnowledge はナレッジアイテムを検索する
-func (u *KnowledgeUseCaseImpl) SearchKnowledge(query string, filters map[string]interface{}) ([]*domain.KnowledgeItem, error) {
-	return u.knowledgeRepo.Search(query, filters)
+// SearchKnowledge はナレッジアイテムをページ単位で検索し、ファセットごとの件数を集計する
+func (u *KnowledgeUseCaseImpl) SearchKnowledge(query string, filter domain.KnowledgeSearchFilter, page domain.PageRequest) (*domain.KnowledgeSearchResult, error) {
+	if err := page.Normalize(domain.KnowledgeSortFields); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	items, total, err := u.knowledgeRepo.Search(query, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジの検索に失敗しました: %w", err)
+	}
+
+	facets, err := u.knowledgeRepo.SearchFacets(query, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ファセットの集計に失敗しました: %w", err)
+	}
+
+	return &domain.KnowledgeSearchResult{
+		Page:   domain.NewPage(items, total, page),
+		Facets: facets,
+	}, nil
+}
+
+// FindSimilarKnowledge は指定されたテキストに似た質問を持つナレッジアイテムを類似度の高い順に取得する
+func (u *KnowledgeUseCaseImpl) FindSimilarKnowledge(text string, opts domain.SimilarSearchOptions) ([]*domain.ScoredKnowledgeItem, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, &domain.ValidationError{Field: "text", Message: "textは必須です"}
+	}
+
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return u.knowledgeRepo.FindSimilar(text, opts)
 }
 
-// BulkCreateKnowledge は複数のナレッジアイテムを一括作成する
-func (u *KnowledgeUseCaseImpl) BulkCreateKnowledge(items []*domain.KnowledgeItem) error {
-	for _, item := range items {
-		// バリデーション
-		if err := item.Validate(); err != nil {
-			return fmt.Errorf("バリデーションエラー (項目: %s): %w", item.Question, err)
+// BulkCreateKnowledge は複数のナレッジアイテムを1トランザクションで一括作成する
+func (u *KnowledgeUseCaseImpl) BulkCreateKnowledge(items []*domain.KnowledgeItem, mode string) (*BulkCreateResult, error) {
+	mode, err := normalizeBulkMode(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkCreateResult{
+		Mode:    mode,
+		Results: make([]BulkItemResult, len(items)),
+	}
+
+	// 事前検証（案件の存在確認とバリデーション）
+	projects := map[int]*domain.Project{}
+	projectErrs := map[int]error{}
+	valid := make([]*domain.KnowledgeItem, 0, len(items))
+	validIndexes := make([]int, 0, len(items))
+	for i, item := range items {
+		result.Results[i] = BulkItemResult{Index: i}
+
+		project, ok := projects[item.ProjectID]
+		if !ok {
+			p, err := u.projectRepo.GetByID(item.ProjectID)
+			if err != nil {
+				projectErrs[item.ProjectID] = fmt.Errorf("案件が存在しません (ID: %d)", item.ProjectID)
+			}
+			project = p
+			projects[item.ProjectID] = project
 		}
 
-		// 作成
-		if err := u.knowledgeRepo.Create(item); err != nil {
-			return fmt.Errorf("作成エラー (項目: %s): %w", item.Question, err)
+		err := projectErrs[item.ProjectID]
+		if err == nil && len(item.Answers) > 0 {
+			err = item.SetAnswers(item.Answers)
 		}
+		if err == nil {
+			err = item.Validate()
+		}
+		if err == nil {
+			err = item.ValidatePlaceholders(project)
+		}
+		if err != nil {
+			result.Results[i].Status = BulkItemFailed
+			result.Results[i].Error = err.Error()
+			result.Failed++
+			continue
+		}
+
+		valid = append(valid, item)
+		validIndexes = append(validIndexes, i)
 	}
 
-	return nil
+	// 全件モードで検証エラーがあれば何も作成しない
+	if mode == BulkModeAllOrNothing && result.Failed > 0 {
+		for _, i := range validIndexes {
+			result.Results[i].Status = BulkItemSkipped
+		}
+		return result, nil
+	}
+
+	itemErrs, err := u.knowledgeRepo.CreateBatch(valid, mode == BulkModeBestEffort)
+	if err != nil && mode == BulkModeBestEffort {
+		return nil, fmt.Errorf("一括作成に失敗しました: %w", err)
+	}
+
+	for j, i := range validIndexes {
+		switch {
+		case itemErrs[j] != nil:
+			result.Results[i].Status = BulkItemFailed
+			result.Results[i].Error = itemErrs[j].Error()
+			result.Failed++
+		case err != nil:
+			// 他の項目の失敗によりロールバックされた
+			valid[j].ID = 0
+			result.Results[i].Status = BulkItemSkipped
+		default:
+			result.Results[i].Status = BulkItemCreated
+			result.Results[i].ID = valid[j].ID
+			result.Created++
+		}
+	}
+
+	// 項目に起因しない失敗（コミット失敗など）
+	if err != nil && result.Failed == 0 {
+		return nil, fmt.Errorf("一括作成に失敗しました: %w", err)
+	}
+
+	return result, nil
+}
+
+// normalizeBulkMode は一括処理のモードを検証する（省略時はall_or_nothing）
+func normalizeBulkMode(mode string) (string, error) {
+	if mode == "" {
+		return BulkModeAllOrNothing, nil
+	}
+	if mode != BulkModeAllOrNothing && mode != BulkModeBestEffort {
+		return "", &domain.ValidationError{Field: "mode", Message: "modeはall_or_nothing, best_effortのいずれかである必要があります"}
+	}
+	return mode, nil
+}
+
+// newBulkResult はIDごとの結果を用意した一括処理の結果を生成する
+func newBulkResult(mode string, ids []int) *BulkResult {
+	result := &BulkResult{Mode: mode, Results: make([]BulkIDResult, len(ids))}
+	for i, id := range ids {
+		result.Results[i] = BulkIDResult{ID: id}
+	}
+	return result
+}
+
+// fail はi番目のIDを失敗として記録する
+func (r *BulkResult) fail(i int, err error) {
+	r.Results[i].Status = BulkItemFailed
+	r.Results[i].Error = err.Error()
+	r.Failed++
+}
+
+// applyBatchErrors はリポジトリの一括処理の結果をIDごとの結果に反映する
+// indexesはリポジトリに渡した項目のResultsでの位置、batchErrはトランザクション全体のエラー
+func (r *BulkResult) applyBatchErrors(indexes []int, itemErrs []error, batchErr error, succeeded string) {
+	for j, i := range indexes {
+		switch {
+		case itemErrs[j] != nil:
+			r.fail(i, itemErrs[j])
+		case batchErr != nil:
+			// 他の項目の失敗によりロールバックされた
+			r.Results[i].Status = BulkItemSkipped
+		default:
+			r.Results[i].Status = succeeded
+			r.Succeeded++
+		}
+	}
+}
+
+// validateBulkIDs は一括処理の対象IDを重複を除いて検証する
+func validateBulkIDs(ids []int) ([]int, error) {
+	ids = uniqueInts(ids)
+	if len(ids) == 0 {
+		return nil, &domain.ValidationError{Field: "ids", Message: "idsを指定してください"}
+	}
+	return ids, nil
+}
+
+// BulkUpdateKnowledge は複数のアイテムに同じ変更を1トランザクションで反映する
+// ステータスはワークフローの遷移規則に従って変更し、遷移を記録する。版は読み込み時の版で確認する
+func (u *KnowledgeUseCaseImpl) BulkUpdateKnowledge(ids []int, patch domain.KnowledgeBulkPatch, mode string, actor string) (*BulkResult, error) {
+	mode, err := normalizeBulkMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	if ids, err = validateBulkIDs(ids); err != nil {
+		return nil, err
+	}
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 移動先の案件の存在確認
+	var target *domain.Project
+	if patch.ProjectID != nil {
+		if target, err = u.projectRepo.GetByID(*patch.ProjectID); err != nil {
+			return nil, fmt.Errorf("移動先の案件が存在しません: %w", err)
+		}
+	}
+
+	// 付与する部門は有効な部門に限る
+	if patch.DepartmentID != nil {
+		department, err := u.departmentRepo.GetByID(*patch.DepartmentID)
+		if err != nil {
+			return nil, fmt.Errorf("部門が存在しません: %w", err)
+		}
+		if !department.IsActive {
+			return nil, &domain.ValidationError{Field: "department_id", Message: fmt.Sprintf("無効化された部門は付与できません: %s", department.Name)}
+		}
+	}
+
+	// 事前検証（存在確認と変更の反映）
+	result := newBulkResult(mode, ids)
+	updates := make([]*domain.KnowledgeBatchUpdate, 0, len(ids))
+	indexes := make([]int, 0, len(ids))
+	for i, id := range ids {
+		update, err := u.prepareBulkUpdate(id, patch, target, actor)
+		if err != nil {
+			result.fail(i, err)
+			continue
+		}
+		updates = append(updates, update)
+		indexes = append(indexes, i)
+	}
+
+	// 全件モードで検証エラーがあれば何も更新しない
+	if mode == BulkModeAllOrNothing && result.Failed > 0 {
+		for _, i := range indexes {
+			result.Results[i].Status = BulkItemSkipped
+		}
+		return result, nil
+	}
+
+	itemErrs, err := u.knowledgeRepo.UpdateBatch(updates, mode == BulkModeBestEffort)
+	if err != nil && mode == BulkModeBestEffort {
+		return nil, fmt.Errorf("一括更新に失敗しました: %w", err)
+	}
+	result.applyBatchErrors(indexes, itemErrs, err, BulkItemUpdated)
+	for j, i := range indexes {
+		if result.Results[i].Status == BulkItemUpdated {
+			result.Results[i].Version = updates[j].Item.Version
+		}
+	}
+
+	// 項目に起因しない失敗（コミット失敗など）
+	if err != nil && result.Failed == 0 {
+		return nil, fmt.Errorf("一括更新に失敗しました: %w", err)
+	}
+
+	return result, nil
+}
+
+// prepareBulkUpdate はアイテムを読み込んで一括更新の内容を反映する
+func (u *KnowledgeUseCaseImpl) prepareBulkUpdate(id int, patch domain.KnowledgeBulkPatch, target *domain.Project, actor string) (*domain.KnowledgeBatchUpdate, error) {
+	item, err := u.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	expectedVersion := item.Version
+	transition, err := patch.Apply(item, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 移動先の案件で解決できないプレースホルダーを含む回答は移動できない
+	if target != nil && domain.HasPlaceholders(item.Answer) {
+		if err := item.ValidatePlaceholders(target); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.KnowledgeBatchUpdate{Item: item, ExpectedVersion: expectedVersion, Transition: transition}, nil
+}
+
+// BulkDeleteKnowledge は複数のアイテムを1トランザクションで削除する
+func (u *KnowledgeUseCaseImpl) BulkDeleteKnowledge(ids []int, mode string) (*BulkResult, error) {
+	mode, err := normalizeBulkMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	if ids, err = validateBulkIDs(ids); err != nil {
+		return nil, err
+	}
+
+	// 事前検証（存在確認）
+	result := newBulkResult(mode, ids)
+	targets := make([]int, 0, len(ids))
+	indexes := make([]int, 0, len(ids))
+	for i, id := range ids {
+		if _, err := u.knowledgeRepo.GetByID(id); err != nil {
+			result.fail(i, fmt.Errorf("ナレッジアイテムが存在しません: %w", err))
+			continue
+		}
+		targets = append(targets, id)
+		indexes = append(indexes, i)
+	}
+
+	// 全件モードで存在しないIDがあれば何も削除しない
+	if mode == BulkModeAllOrNothing && result.Failed > 0 {
+		for _, i := range indexes {
+			result.Results[i].Status = BulkItemSkipped
+		}
+		return result, nil
+	}
+
+	itemErrs, err := u.knowledgeRepo.DeleteBatch(targets, mode == BulkModeBestEffort)
+	if err != nil && mode == BulkModeBestEffort {
+		return nil, fmt.Errorf("一括削除に失敗しました: %w", err)
+	}
+	result.applyBatchErrors(indexes, itemErrs, err, BulkItemDeleted)
+
+	// 項目に起因しない失敗（コミット失敗など）
+	if err != nil && result.Failed == 0 {
+		return nil, fmt.Errorf("一括削除に失敗しました: %w", err)
+	}
+
+	return result, nil
 }
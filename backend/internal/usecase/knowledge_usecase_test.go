@@ -0,0 +1,579 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKnowledgeRepository はKnowledgeRepositoryのモック
+type MockKnowledgeRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeRepository) Create(item *domain.KnowledgeItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeRepository) CreateBatch(items []*domain.KnowledgeItem, bestEffort bool) ([]error, error) {
+	args := m.Called(items, bestEffort)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockKnowledgeRepository) GetByID(id int) (*domain.KnowledgeItem, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.KnowledgeItem), args.Error(1)
+}
+
+func (m *MockKnowledgeRepository) GetByProjectID(projectID int, page domain.PageRequest) ([]*domain.KnowledgeItem, int, error) {
+	args := m.Called(projectID, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*domain.KnowledgeItem), args.Int(1), args.Error(2)
+}
+
+func (m *MockKnowledgeRepository) Update(item *domain.KnowledgeItem, expectedVersion int) error {
+	args := m.Called(item, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeRepository) UpdateBatch(updates []*domain.KnowledgeBatchUpdate, bestEffort bool) ([]error, error) {
+	args := m.Called(updates, bestEffort)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockKnowledgeRepository) DeleteBatch(ids []int, bestEffort bool) ([]error, error) {
+	args := m.Called(ids, bestEffort)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockKnowledgeRepository) Search(query string, filter domain.KnowledgeSearchFilter, page domain.PageRequest) ([]*domain.KnowledgeItem, int, error) {
+	args := m.Called(query, filter, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*domain.KnowledgeItem), args.Int(1), args.Error(2)
+}
+
+func (m *MockKnowledgeRepository) SearchFacets(query string, filter domain.KnowledgeSearchFilter) (*domain.KnowledgeFacets, error) {
+	args := m.Called(query, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.KnowledgeFacets), args.Error(1)
+}
+
+func (m *MockKnowledgeRepository) FindSimilar(text string, opts domain.SimilarSearchOptions) ([]*domain.ScoredKnowledgeItem, error) {
+	args := m.Called(text, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ScoredKnowledgeItem), args.Error(1)
+}
+
+func (m *MockKnowledgeRepository) FindSimilarBatch(texts []string, opts domain.SimilarSearchOptions) ([][]*domain.ScoredKnowledgeItem, error) {
+	args := m.Called(texts, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]*domain.ScoredKnowledgeItem), args.Error(1)
+}
+
+// MockKnowledgeAnswerRepository はKnowledgeAnswerRepositoryのモック
+type MockKnowledgeAnswerRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeAnswerRepository) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeAnswer, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeAnswer), args.Error(1)
+}
+
+func (m *MockKnowledgeAnswerRepository) GetByKnowledgeIDs(knowledgeIDs []int) (map[int][]*domain.KnowledgeAnswer, error) {
+	args := m.Called(knowledgeIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]*domain.KnowledgeAnswer), args.Error(1)
+}
+
+func (m *MockKnowledgeAnswerRepository) Replace(item *domain.KnowledgeItem, expectedVersion int) error {
+	args := m.Called(item, expectedVersion)
+	return args.Error(0)
+}
+
+func newBulkItems() []*domain.KnowledgeItem {
+	return []*domain.KnowledgeItem{
+		domain.NewKnowledgeItem(1, nil, "シート1", "A1", "質問1", "回答1", nil, "山田太郎"),
+		domain.NewKnowledgeItem(1, nil, "シート1", "A2", "", "回答2", nil, "山田太郎"),
+		domain.NewKnowledgeItem(1, nil, "シート1", "A3", "質問3", "回答3", nil, "山田太郎"),
+	}
+}
+
+func TestKnowledgeUseCase_BulkCreateKnowledge_AllOrNothingValidationError(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, projectRepo, new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1}, nil).Once()
+
+	result, err := usecase.BulkCreateKnowledge(newBulkItems(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, BulkModeAllOrNothing, result.Mode)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, BulkItemSkipped, result.Results[0].Status)
+	assert.Equal(t, BulkItemFailed, result.Results[1].Status)
+	assert.Equal(t, "質問は必須です", result.Results[1].Error)
+	assert.Equal(t, BulkItemSkipped, result.Results[2].Status)
+
+	// 検証エラーがあればDBには書き込まない
+	knowledgeRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	projectRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_BulkCreateKnowledge_AllOrNothingDBError(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, projectRepo, new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	items := newBulkItems()
+	items[1].Question = "質問2"
+	dbErr := errors.New("duplicate key")
+
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1}, nil)
+	knowledgeRepo.On("CreateBatch", items, false).Run(func(args mock.Arguments) {
+		items[0].ID = 10
+	}).Return([]error{nil, dbErr, nil}, dbErr)
+
+	result, err := usecase.BulkCreateKnowledge(items, BulkModeAllOrNothing)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, BulkItemSkipped, result.Results[0].Status)
+	assert.Zero(t, items[0].ID, "ロールバックされた項目のIDはクリアされるべき")
+	assert.Equal(t, BulkItemFailed, result.Results[1].Status)
+	assert.Equal(t, "duplicate key", result.Results[1].Error)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_BulkCreateKnowledge_BestEffort(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, projectRepo, new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	items := newBulkItems()
+	items = append(items, domain.NewKnowledgeItem(2, nil, "シート1", "A4", "質問4", "回答4", nil, "山田太郎"))
+
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1}, nil)
+	projectRepo.On("GetByID", 2).Return(nil, errors.New("not found"))
+	knowledgeRepo.On("CreateBatch", []*domain.KnowledgeItem{items[0], items[2]}, true).Run(func(args mock.Arguments) {
+		items[0].ID = 10
+		items[2].ID = 11
+	}).Return([]error{nil, nil}, nil)
+
+	result, err := usecase.BulkCreateKnowledge(items, BulkModeBestEffort)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, 10, result.Results[0].ID)
+	assert.Equal(t, BulkItemFailed, result.Results[1].Status)
+	assert.Equal(t, 11, result.Results[2].ID)
+	assert.Equal(t, "案件が存在しません (ID: 2)", result.Results[3].Error)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_BulkCreateKnowledge_InvalidMode(t *testing.T) {
+	usecase := NewKnowledgeUseCase(new(MockKnowledgeRepository), new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	_, err := usecase.BulkCreateKnowledge(newBulkItems(), "partial")
+	assert.Error(t, err)
+}
+
+func TestKnowledgeUseCase_BulkUpdateKnowledge_AllOrNothingInvalidTransition(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問1", Status: domain.StatusDraft, Version: 2}, nil)
+	knowledgeRepo.On("GetByID", 2).Return(&domain.KnowledgeItem{ID: 2, ProjectID: 1, Question: "質問2", Status: domain.StatusArchived, Version: 1}, nil)
+
+	status := domain.StatusInReview
+	result, err := usecase.BulkUpdateKnowledge([]int{1, 2}, domain.KnowledgeBulkPatch{Status: &status}, "", "山田太郎")
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, BulkItemSkipped, result.Results[0].Status)
+	assert.Equal(t, BulkItemFailed, result.Results[1].Status)
+	assert.Equal(t, "ステータスをarchivedからin_reviewに変更することはできません", result.Results[1].Error)
+	knowledgeRepo.AssertNotCalled(t, "UpdateBatch", mock.Anything, mock.Anything)
+}
+
+func TestKnowledgeUseCase_BulkUpdateKnowledge_BestEffort(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, projectRepo, new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	fileID := 5
+	projectRepo.On("GetByID", 2).Return(&domain.Project{ID: 2}, nil)
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, FileID: &fileID, Question: "質問1", Status: domain.StatusDraft, Version: 2}, nil)
+	knowledgeRepo.On("GetByID", 2).Return(&domain.KnowledgeItem{ID: 2, ProjectID: 1, Question: "質問2", Status: domain.StatusDraft, Version: 4}, nil)
+	knowledgeRepo.On("GetByID", 3).Return(nil, errors.New("not found"))
+	knowledgeRepo.On("UpdateBatch", mock.MatchedBy(func(updates []*domain.KnowledgeBatchUpdate) bool {
+		return len(updates) == 2 &&
+			updates[0].ExpectedVersion == 2 && updates[0].Item.ProjectID == 2 && updates[0].Item.FileID == nil &&
+			updates[0].Item.QuestionGroup == "アクセス管理" && updates[0].Transition == nil &&
+			updates[1].ExpectedVersion == 4
+	}), true).Return([]error{nil, domain.ErrVersionConflict}, nil)
+
+	projectID := 2
+	group := " アクセス管理 "
+	result, err := usecase.BulkUpdateKnowledge([]int{1, 2, 3, 1}, domain.KnowledgeBulkPatch{ProjectID: &projectID, QuestionGroup: &group}, BulkModeBestEffort, "山田太郎")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Results, 3, "重複したIDは1件として扱うべき")
+	assert.Equal(t, BulkItemUpdated, result.Results[0].Status)
+	assert.Equal(t, 3, result.Results[0].Version)
+	assert.Equal(t, domain.ErrVersionConflict.Error(), result.Results[1].Error)
+	assert.Equal(t, BulkItemFailed, result.Results[2].Status)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_BulkUpdateKnowledge_EmptyPatch(t *testing.T) {
+	usecase := NewKnowledgeUseCase(new(MockKnowledgeRepository), new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	_, err := usecase.BulkUpdateKnowledge([]int{1}, domain.KnowledgeBulkPatch{}, "", "山田太郎")
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestKnowledgeUseCase_BulkUpdateKnowledge_InactiveDepartment(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	departmentRepo := new(MockDepartmentRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), new(MockKnowledgeAnswerRepository), departmentRepo)
+
+	departmentRepo.On("GetByID", 3).Return(&domain.Department{ID: 3, Name: "旧情報システム部", IsActive: false}, nil)
+
+	departmentID := 3
+	_, err := usecase.BulkUpdateKnowledge([]int{1, 2}, domain.KnowledgeBulkPatch{DepartmentID: &departmentID}, "", "山田太郎")
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "department_id", validationErr.Field)
+	knowledgeRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+}
+
+func TestKnowledgeUseCase_BulkDeleteKnowledge(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1}, nil)
+	knowledgeRepo.On("GetByID", 2).Return(&domain.KnowledgeItem{ID: 2}, nil)
+	knowledgeRepo.On("DeleteBatch", []int{1, 2}, false).Return([]error{nil, nil}, nil)
+
+	result, err := usecase.BulkDeleteKnowledge([]int{1, 2}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, BulkItemDeleted, result.Results[0].Status)
+	assert.Equal(t, BulkItemDeleted, result.Results[1].Status)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	fileID := 7
+	departmentID := 2
+	current := &domain.KnowledgeItem{ID: 1, ProjectID: 1, FileID: &fileID, DepartmentID: &departmentID, Question: "質問", Status: domain.StatusDraft, Assignee: "佐藤", CreatedBy: "山田", Version: 3}
+
+	knowledgeRepo.On("GetByID", 1).Return(current, nil)
+	knowledgeRepo.On("Update", current, 3).Return(nil)
+
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+	answer := "回答"
+	item, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{Answer: &answer, UpdatedBy: "鈴木"}, 3)
+	require.NoError(t, err)
+	// 指定した項目だけを変更し、省略した項目は維持する
+	assert.Equal(t, "回答", item.Answer)
+	assert.Equal(t, "質問", item.Question)
+	assert.Equal(t, 1, item.ProjectID)
+	assert.Equal(t, &fileID, item.FileID)
+	assert.Equal(t, &departmentID, item.DepartmentID)
+	assert.Equal(t, "山田", item.CreatedBy)
+	assert.Equal(t, "佐藤", item.Assignee)
+	assert.Equal(t, "鈴木", item.UpdatedBy)
+	// 版はサーバー側で1つだけ進める
+	assert.Equal(t, 4, item.Version)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_ClearDepartment(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	departmentID := 2
+	current := &domain.KnowledgeItem{ID: 1, ProjectID: 1, DepartmentID: &departmentID, Question: "質問", Version: 3}
+	knowledgeRepo.On("GetByID", 1).Return(current, nil)
+	knowledgeRepo.On("Update", current, 3).Return(nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+
+	// nullを指定した項目は値を消す
+	item, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{DepartmentID: domain.Optional[int]{Set: true}}, 3)
+	require.NoError(t, err)
+	assert.Nil(t, item.DepartmentID)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_EmptyPatch(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Version: 3}, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+
+	_, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{UpdatedBy: "鈴木"}, 3)
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_UnknownPlaceholder(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, projectRepo, answerRepo, new(MockDepartmentRepository))
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Version: 3}, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1, CustomerName: "テスト株式会社", Variables: map[string]string{"product": "SecureBox"}}, nil)
+
+	var validationErr *domain.ValidationError
+	answer := "{{product}}のログは{{retention_days}}日間保持します"
+	_, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{Answer: &answer}, 3)
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Message, "retention_days")
+	knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_VersionMismatch(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	current := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "他の人の質問", Version: 4}
+	knowledgeRepo.On("GetByID", 1).Return(current, nil)
+
+	question := "質問"
+	_, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{Question: &question}, 3)
+
+	var conflictErr *domain.VersionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, current, conflictErr.Current)
+	knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_ConcurrentUpdate(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	before := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Version: 3}
+	after := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "同時に更新された質問", Version: 4}
+
+	// 読み取り後、更新までの間に他の更新が入った場合
+	knowledgeRepo.On("GetByID", 1).Return(before, nil).Once()
+	knowledgeRepo.On("Update", before, 3).Return(domain.ErrVersionConflict)
+	knowledgeRepo.On("GetByID", 1).Return(after, nil).Once()
+
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+	question := "質問"
+	_, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{Question: &question}, 3)
+
+	var conflictErr *domain.VersionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, after, conflictErr.Current)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_StatusChangeRejected(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	current := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: domain.StatusDraft, Version: 1}
+	knowledgeRepo.On("GetByID", 1).Return(current, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+
+	// 更新でステータスを直接変更することはできない
+	status := domain.StatusPublished
+	_, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{Status: &status}, 1)
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "status", validationErr.Field)
+	knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestKnowledgeUseCase_GetKnowledge_WithAnswers(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	answers := []*domain.KnowledgeAnswer{
+		{ID: 1, KnowledgeItemID: 1, Label: "SaaSプラン", Answer: "はい", DisplayOrder: 1},
+		{ID: 2, KnowledgeItemID: 1, Label: "オンプレミスプラン", Answer: "いいえ", DisplayOrder: 2},
+	}
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, Question: "質問"}, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return(answers, nil)
+
+	item, err := usecase.GetKnowledge(1)
+	require.NoError(t, err)
+	assert.Equal(t, answers, item.Answers)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_ReplaceAnswers(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	current := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Answer: "はい", Version: 2}
+	patch := domain.KnowledgePatch{
+		Answers: []*domain.KnowledgeAnswer{
+			{Label: "オンプレミスプラン", Answer: "いいえ", DisplayOrder: 2},
+			{ID: 5, Label: "SaaSプラン", Answer: "はい", DisplayOrder: 1},
+		},
+	}
+
+	knowledgeRepo.On("GetByID", 1).Return(current, nil)
+	answerRepo.On("Replace", current, 2).Return(nil)
+
+	item, err := usecase.UpdateKnowledge(1, patch, 2)
+	require.NoError(t, err)
+	// 主回答はバリエーションから表示順に合成される
+	assert.Equal(t, "【SaaSプラン】\nはい\n\n【オンプレミスプラン】\nいいえ", item.Answer)
+	assert.Equal(t, 3, item.Version)
+	knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	answerRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_UpdateKnowledge_AnswerWithVariants(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+
+	current := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Answer: "【SaaSプラン】\nはい", Version: 2}
+	knowledgeRepo.On("GetByID", 1).Return(current, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{
+		{ID: 5, KnowledgeItemID: 1, Label: "SaaSプラン", Answer: "はい", DisplayOrder: 1},
+	}, nil)
+
+	// バリエーションがある場合はanswerを直接書き換えられない
+	answer := "いいえ"
+	_, err := usecase.UpdateKnowledge(1, domain.KnowledgePatch{Answer: &answer}, 2)
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "answer", validationErr.Field)
+	knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestKnowledgeUseCase_FindSimilarKnowledge(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	expected := []*domain.ScoredKnowledgeItem{
+		{KnowledgeItem: &domain.KnowledgeItem{ID: 1, Question: "パスワードの最小文字数は？"}, Score: 0.82},
+	}
+	// 未指定の閾値・件数には既定値が使われる
+	opts := domain.SimilarSearchOptions{Threshold: domain.DefaultSimilarityThreshold, ExcludeProjectID: 3, Limit: domain.DefaultSimilarLimit}
+	knowledgeRepo.On("FindSimilar", "パスワードの最小文字数", opts).Return(expected, nil)
+
+	items, err := usecase.FindSimilarKnowledge(" パスワードの最小文字数 ", domain.SimilarSearchOptions{ExcludeProjectID: 3})
+	require.NoError(t, err)
+	assert.Equal(t, expected, items)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_FindSimilarKnowledge_InvalidOptions(t *testing.T) {
+	usecase := NewKnowledgeUseCase(new(MockKnowledgeRepository), new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.FindSimilarKnowledge("", domain.SimilarSearchOptions{})
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = usecase.FindSimilarKnowledge("質問", domain.SimilarSearchOptions{Threshold: 1.5})
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = usecase.FindSimilarKnowledge("質問", domain.SimilarSearchOptions{Limit: 500})
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestKnowledgeUseCase_SearchKnowledge_WithFacets(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	filter := domain.KnowledgeSearchFilter{DepartmentIDs: []int{1, 3}, Statuses: []string{domain.StatusDraft}, UnansweredOnly: true}
+	page := domain.PageRequest{Limit: domain.DefaultPageLimit, Sort: "created_at", Order: domain.SortDesc}
+	items := []*domain.KnowledgeItem{{ID: 1, Question: "パスワードポリシーは？"}}
+	facets := &domain.KnowledgeFacets{
+		Department: []domain.FacetCount{{Value: "1", Label: "情報システム", Count: 1}, {Value: "3", Label: "法務", Count: 2}},
+	}
+	knowledgeRepo.On("Search", "パスワード", filter, page).Return(items, 3, nil)
+	knowledgeRepo.On("SearchFacets", "パスワード", filter).Return(facets, nil)
+
+	result, err := usecase.SearchKnowledge("パスワード", filter, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, items, result.Items)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, facets, result.Facets)
+	knowledgeRepo.AssertExpectations(t)
+}
+
+func TestKnowledgeUseCase_SearchKnowledge_InvalidFilter(t *testing.T) {
+	usecase := NewKnowledgeUseCase(new(MockKnowledgeRepository), new(MockProjectRepository), new(MockKnowledgeAnswerRepository), new(MockDepartmentRepository))
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.SearchKnowledge("", domain.KnowledgeSearchFilter{Statuses: []string{"unknown"}}, domain.PageRequest{})
+	assert.ErrorAs(t, err, &validationErr)
+
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, err = usecase.SearchKnowledge("", domain.KnowledgeSearchFilter{CreatedFrom: &from, CreatedTo: &to}, domain.PageRequest{})
+	assert.ErrorAs(t, err, &validationErr)
+}
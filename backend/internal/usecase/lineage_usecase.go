@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// LineageUseCase はナレッジの分割・統合に関するビジネスロジックを提供する
+type LineageUseCase interface {
+	// Split はexpectedVersionに0を指定した場合は版の確認を行わない
+	Split(id int, segments []domain.SplitSegment, actor string, expectedVersion int) ([]*domain.KnowledgeItem, error)
+	Merge(ids []int, question string, answer string, actor string) (*domain.KnowledgeItem, error)
+	ListLineage(id int) ([]*domain.KnowledgeLineage, error)
+}
+
+// LineageUseCaseImpl はLineageUseCaseの実装
+type LineageUseCaseImpl struct {
+	knowledgeRepo domain.KnowledgeRepository
+	lineageRepo   domain.KnowledgeLineageRepository
+}
+
+// NewLineageUseCase は新しいLineageUseCaseを生成する
+func NewLineageUseCase(
+	knowledgeRepo domain.KnowledgeRepository,
+	lineageRepo domain.KnowledgeLineageRepository,
+) LineageUseCase {
+	return &LineageUseCaseImpl{
+		knowledgeRepo: knowledgeRepo,
+		lineageRepo:   lineageRepo,
+	}
+}
+
+// Split はナレッジアイテムをセグメントごとに分割する
+// 元のアイテムはゴミ箱に移され、分割後のアイテムには派生元として記録される
+func (u *LineageUseCaseImpl) Split(id int, segments []domain.SplitSegment, actor string, expectedVersion int) ([]*domain.KnowledgeItem, error) {
+	source, err := u.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	if expectedVersion != 0 && source.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{Current: source}
+	}
+
+	items, err := domain.SplitKnowledgeItem(source, segments, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.lineageRepo.Derive(domain.LineageSplit, []*domain.KnowledgeItem{source}, items, actor); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return nil, u.versionConflict(id)
+		}
+		return nil, fmt.Errorf("ナレッジの分割に失敗しました: %w", err)
+	}
+
+	return items, nil
+}
+
+// Merge は複数のナレッジアイテムを指定された順に1つへ統合する
+// 元のアイテムはゴミ箱に移され、統合後のアイテムには派生元として記録される
+func (u *LineageUseCaseImpl) Merge(ids []int, question string, answer string, actor string) (*domain.KnowledgeItem, error) {
+	sources := make([]*domain.KnowledgeItem, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, &domain.ValidationError{Field: "ids", Message: fmt.Sprintf("IDが重複しています: %d", id)}
+		}
+		seen[id] = true
+
+		source, err := u.knowledgeRepo.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("ナレッジアイテムが存在しません (ID: %d): %w", id, err)
+		}
+		sources = append(sources, source)
+	}
+
+	merged, err := domain.MergeKnowledgeItems(sources, question, answer, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.lineageRepo.Derive(domain.LineageMerge, sources, []*domain.KnowledgeItem{merged}, actor); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return nil, fmt.Errorf("統合中に他の更新が行われました。再読み込みしてください: %w", err)
+		}
+		return nil, fmt.Errorf("ナレッジの統合に失敗しました: %w", err)
+	}
+
+	return merged, nil
+}
+
+// ListLineage はナレッジアイテムの派生元を取得する
+func (u *LineageUseCaseImpl) ListLineage(id int) ([]*domain.KnowledgeLineage, error) {
+	// 存在確認
+	if _, err := u.knowledgeRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	return u.lineageRepo.GetByKnowledgeID(id)
+}
+
+// versionConflict は最新の内容を取得して版の競合エラーを返す
+func (u *LineageUseCaseImpl) versionConflict(id int) error {
+	current, err := u.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+	return &domain.VersionConflictError{Current: current}
+}
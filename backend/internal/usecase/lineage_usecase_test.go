@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKnowledgeLineageRepository はKnowledgeLineageRepositoryのモック
+type MockKnowledgeLineageRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeLineageRepository) Derive(operation string, sources []*domain.KnowledgeItem, derived []*domain.KnowledgeItem, actor string) error {
+	args := m.Called(operation, sources, derived, actor)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeLineageRepository) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeLineage, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeLineage), args.Error(1)
+}
+
+func TestLineageUseCase_Split(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	lineageRepo := new(MockKnowledgeLineageRepository)
+	usecase := NewLineageUseCase(knowledgeRepo, lineageRepo)
+
+	source := &domain.KnowledgeItem{ID: 10, ProjectID: 1, SheetName: "Sheet1", SourceRange: "B12", Question: "質問1 質問2", Version: 2}
+	segments := []domain.SplitSegment{{Question: "質問1"}, {Question: "質問2"}}
+
+	knowledgeRepo.On("GetByID", 10).Return(source, nil)
+	lineageRepo.On("Derive", domain.LineageSplit, []*domain.KnowledgeItem{source}, mock.Anything, "山田太郎").Return(nil)
+
+	items, err := usecase.Split(10, segments, "山田太郎", 2)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+	lineageRepo.AssertExpectations(t)
+}
+
+func TestLineageUseCase_Split_VersionMismatch(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	lineageRepo := new(MockKnowledgeLineageRepository)
+	usecase := NewLineageUseCase(knowledgeRepo, lineageRepo)
+
+	source := &domain.KnowledgeItem{ID: 10, ProjectID: 1, Question: "質問1 質問2", Version: 3}
+	knowledgeRepo.On("GetByID", 10).Return(source, nil)
+
+	_, err := usecase.Split(10, []domain.SplitSegment{{Question: "質問1"}, {Question: "質問2"}}, "山田太郎", 2)
+
+	var conflictErr *domain.VersionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	lineageRepo.AssertNotCalled(t, "Derive", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLineageUseCase_Merge(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	lineageRepo := new(MockKnowledgeLineageRepository)
+	usecase := NewLineageUseCase(knowledgeRepo, lineageRepo)
+
+	first := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問の前半", Version: 1}
+	second := &domain.KnowledgeItem{ID: 2, ProjectID: 1, Question: "質問の後半", Version: 1}
+	knowledgeRepo.On("GetByID", 1).Return(first, nil)
+	knowledgeRepo.On("GetByID", 2).Return(second, nil)
+	lineageRepo.On("Derive", domain.LineageMerge, []*domain.KnowledgeItem{first, second}, mock.Anything, "山田太郎").Return(nil)
+
+	merged, err := usecase.Merge([]int{1, 2}, "", "", "山田太郎")
+	require.NoError(t, err)
+	assert.Equal(t, "質問の前半\n質問の後半", merged.Question)
+	lineageRepo.AssertExpectations(t)
+}
+
+func TestLineageUseCase_Merge_Errors(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewLineageUseCase(knowledgeRepo, new(MockKnowledgeLineageRepository))
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問"}, nil)
+	knowledgeRepo.On("GetByID", 9).Return(nil, sql.ErrNoRows)
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.Merge([]int{1, 1}, "", "", "山田太郎")
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = usecase.Merge([]int{1, 9}, "", "", "山田太郎")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -1,13 +1,18 @@
 package usecase
 
-import "github.com/security-checksheets/backend/internal/domain"
+import (
+	"fmt"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
 
 // ProjectUseCase は案件に関するビジネスロジックを提供する
 type ProjectUseCase interface {
 	CreateProject(project *domain.Project) error
 	GetProject(id int) (*domain.Project, error)
-	ListProjects() ([]*domain.Project, error)
-	UpdateProject(project *domain.Project) error
+	ListProjects(page domain.PageRequest) (*domain.Page[*domain.Project], error)
+	// UpdateProject は指定された項目だけを更新し、更新後の案件を返す
+	UpdateProject(id int, patch domain.ProjectPatch) (*domain.Project, error)
 	DeleteProject(id int) error
 }
 
@@ -37,20 +42,39 @@ func (u *ProjectUseCaseImpl) GetProject(id int) (*domain.Project, error) {
 	return u.repo.GetByID(id)
 }
 
-// ListProjects はすべての案件を取得する
-func (u *ProjectUseCaseImpl) ListProjects() ([]*domain.Project, error) {
-	return u.repo.GetAll()
+// ListProjects は案件の一覧をページ単位で取得する
+func (u *ProjectUseCaseImpl) ListProjects(page domain.PageRequest) (*domain.Page[*domain.Project], error) {
+	if err := page.Normalize(domain.ProjectSortFields); err != nil {
+		return nil, err
+	}
+
+	projects, total, err := u.repo.GetAll(page)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPage(projects, total, page), nil
 }
 
-// UpdateProject は案件情報を更新する
-func (u *ProjectUseCaseImpl) UpdateProject(project *domain.Project) error {
-	// バリデーション
-	if err := project.Validate(); err != nil {
-		return err
+// UpdateProject は案件情報の指定された項目だけを更新する
+func (u *ProjectUseCaseImpl) UpdateProject(id int, patch domain.ProjectPatch) (*domain.Project, error) {
+	// 存在確認
+	project, err := u.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	// 反映とバリデーション
+	if err := project.ApplyPatch(patch); err != nil {
+		return nil, err
 	}
 
 	// リポジトリで更新
-	return u.repo.Update(project)
+	if err := u.repo.Update(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
 }
 
 // DeleteProject は案件を削除する
@@ -27,12 +27,12 @@ func (m *MockProjectRepository) GetByID(id int) (*domain.Project, error) {
 	return args.Get(0).(*domain.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) GetAll() ([]*domain.Project, error) {
-	args := m.Called()
+func (m *MockProjectRepository) GetAll(page domain.PageRequest) ([]*domain.Project, int, error) {
+	args := m.Called(page)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, 0, args.Error(2)
 	}
-	return args.Get(0).([]*domain.Project), args.Error(1)
+	return args.Get(0).([]*domain.Project), args.Int(1), args.Error(2)
 }
 
 func (m *MockProjectRepository) Update(project *domain.Project) error {
@@ -40,6 +40,11 @@ func (m *MockProjectRepository) Update(project *domain.Project) error {
 	return args.Error(0)
 }
 
+func (m *MockProjectRepository) UpdateVariables(id int, variables map[string]string) error {
+	args := m.Called(id, variables)
+	return args.Error(0)
+}
+
 func (m *MockProjectRepository) Delete(id int) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -118,30 +123,52 @@ func TestProjectUseCase_ListProjects(t *testing.T) {
 		{ID: 2, CustomerName: "テスト株式会社2", Status: "active"},
 	}
 
-	mockRepo.On("GetAll").Return(expectedProjects, nil)
+	// 未指定の条件には既定値が使われる
+	mockRepo.On("GetAll", domain.PageRequest{Limit: domain.DefaultPageLimit, Sort: "created_at", Order: domain.SortDesc}).
+		Return(expectedProjects, 2, nil)
 
-	projects, err := usecase.ListProjects()
+	page, err := usecase.ListProjects(domain.PageRequest{})
 	assert.NoError(t, err)
-	assert.Len(t, projects, 2)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, domain.DefaultPageLimit, page.Limit)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProjectUseCase_ListProjects_InvalidSort(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	usecase := NewProjectUseCase(mockRepo)
+
+	// 許可されていない項目での並び替えはできない
+	_, err := usecase.ListProjects(domain.PageRequest{Sort: "description; DROP TABLE projects"})
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	mockRepo.AssertNotCalled(t, "GetAll", mock.Anything)
+}
+
 func TestProjectUseCase_UpdateProject(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
 	usecase := NewProjectUseCase(mockRepo)
 
-	project := &domain.Project{
+	current := &domain.Project{
 		ID:           1,
-		CustomerName: "更新株式会社",
-		Description:  "更新された案件",
+		CustomerName: "テスト株式会社",
+		Description:  "テスト案件",
 		Owner:        "山田太郎",
-		Status:       "completed",
+		Status:       "active",
 	}
+	mockRepo.On("GetByID", 1).Return(current, nil)
+	mockRepo.On("Update", current).Return(nil)
 
-	mockRepo.On("Update", project).Return(nil)
-
-	err := usecase.UpdateProject(project)
+	// 指定した項目だけを変更し、省略した項目は維持する
+	status := "completed"
+	project, err := usecase.UpdateProject(1, domain.ProjectPatch{Status: &status})
 	assert.NoError(t, err)
+	assert.Equal(t, "completed", project.Status)
+	assert.Equal(t, "テスト株式会社", project.CustomerName)
+	assert.Equal(t, "テスト案件", project.Description)
+	assert.Equal(t, "山田太郎", project.Owner)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -149,15 +176,11 @@ func TestProjectUseCase_UpdateProject_ValidationError(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
 	usecase := NewProjectUseCase(mockRepo)
 
-	// 顧客名が空の案件（バリデーションエラー）
-	project := &domain.Project{
-		ID:           1,
-		CustomerName: "",
-		Description:  "テスト案件",
-		Owner:        "山田太郎",
-	}
+	mockRepo.On("GetByID", 1).Return(&domain.Project{ID: 1, CustomerName: "テスト株式会社"}, nil)
 
-	err := usecase.UpdateProject(project)
+	// 顧客名を空にする更新（バリデーションエラー）
+	customerName := ""
+	_, err := usecase.UpdateProject(1, domain.ProjectPatch{CustomerName: &customerName})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "顧客名は必須です")
 
@@ -165,6 +188,18 @@ func TestProjectUseCase_UpdateProject_ValidationError(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "Update")
 }
 
+func TestProjectUseCase_UpdateProject_NotFound(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	usecase := NewProjectUseCase(mockRepo)
+
+	mockRepo.On("GetByID", 999).Return(nil, errors.New("not found"))
+
+	owner := "山田太郎"
+	_, err := usecase.UpdateProject(999, domain.ProjectPatch{Owner: &owner})
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
 func TestProjectUseCase_DeleteProject(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
 	usecase := NewProjectUseCase(mockRepo)
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// RecommendationUseCase は未回答の質問への回答推薦に関するビジネスロジックを提供する
+type RecommendationUseCase interface {
+	GenerateRecommendations(projectID int, opts RecommendationOptions) (*RecommendationResult, error)
+	ListRecommendations(projectID int) ([]*domain.KnowledgeRecommendation, error)
+	// AcceptRecommendation はexpectedVersionに0を指定した場合は版の確認を行わない
+	AcceptRecommendation(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+}
+
+// RecommendationOptions は推薦の生成条件
+type RecommendationOptions struct {
+	// Limit はアイテムごとに保存する候補の最大数
+	Limit int `json:"limit"`
+	// Threshold はこの値以上の類似度の候補のみを保存する
+	Threshold float64 `json:"threshold"`
+}
+
+// RecommendationResult は推薦の生成結果
+type RecommendationResult struct {
+	ProjectID int `json:"project_id"`
+	// Unanswered は推薦の対象となった未回答のアイテム数（回答を編集できる下書き・差し戻しのもの）
+	Unanswered int `json:"unanswered"`
+	// Recommended は1件以上の候補が見つかったアイテム数
+	Recommended int `json:"recommended"`
+	Candidates  int `json:"candidates"`
+}
+
+// RecommendationUseCaseImpl はRecommendationUseCaseの実装
+type RecommendationUseCaseImpl struct {
+	knowledgeRepo      domain.KnowledgeRepository
+	projectRepo        domain.ProjectRepository
+	answerRepo         domain.KnowledgeAnswerRepository
+	recommendationRepo domain.KnowledgeRecommendationRepository
+}
+
+// NewRecommendationUseCase は新しいRecommendationUseCaseを生成する
+func NewRecommendationUseCase(
+	knowledgeRepo domain.KnowledgeRepository,
+	projectRepo domain.ProjectRepository,
+	answerRepo domain.KnowledgeAnswerRepository,
+	recommendationRepo domain.KnowledgeRecommendationRepository,
+) RecommendationUseCase {
+	return &RecommendationUseCaseImpl{
+		knowledgeRepo:      knowledgeRepo,
+		projectRepo:        projectRepo,
+		answerRepo:         answerRepo,
+		recommendationRepo: recommendationRepo,
+	}
+}
+
+// GenerateRecommendations は案件内の未回答のアイテムごとに、他案件の公開済みナレッジから
+// 類似度の高い候補を探して保存する。有効期限を過ぎた回答は有効な回答より後の順位にする。未処理の既存の候補は置き換える
+// 類似ナレッジはRecommendationBatchSize件ずつまとめて検索する
+func (u *RecommendationUseCaseImpl) GenerateRecommendations(projectID int, opts RecommendationOptions) (*RecommendationResult, error) {
+	// 案件の存在確認
+	if _, err := u.projectRepo.GetByID(projectID); err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = domain.DefaultRecommendationLimit
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = domain.DefaultRecommendationThreshold
+	}
+	now := time.Now()
+	searchOpts := domain.SimilarSearchOptions{
+		Threshold:        opts.Threshold,
+		ExcludeProjectID: projectID,
+		Status:           domain.StatusPublished,
+		PreferValidAt:    &now,
+		Limit:            opts.Limit,
+	}
+	if err := searchOpts.Normalize(); err != nil {
+		return nil, err
+	}
+
+	items, _, err := u.knowledgeRepo.GetByProjectID(projectID, domain.PageRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジの取得に失敗しました: %w", err)
+	}
+
+	// 回答バリエーションのあるアイテムは回答済みとして扱う
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	answers, err := u.answerRepo.GetByKnowledgeIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("回答バリエーションの取得に失敗しました: %w", err)
+	}
+
+	unanswered := []*domain.KnowledgeItem{}
+	for _, item := range items {
+		item.Answers = answers[item.ID]
+		// 回答をコピーできるのは下書き・差し戻しのアイテムに限る
+		if item.NeedsAnswer() && item.IsContentEditable() {
+			unanswered = append(unanswered, item)
+		}
+	}
+
+	result := &RecommendationResult{ProjectID: projectID, Unanswered: len(unanswered)}
+	for start := 0; start < len(unanswered); start += domain.RecommendationBatchSize {
+		batch := unanswered[start:min(start+domain.RecommendationBatchSize, len(unanswered))]
+		questions := make([]string, len(batch))
+		for i, item := range batch {
+			questions[i] = item.Question
+		}
+
+		similarByItem, err := u.knowledgeRepo.FindSimilarBatch(questions, searchOpts)
+		if err != nil {
+			return nil, fmt.Errorf("類似ナレッジの検索に失敗しました: %w", err)
+		}
+
+		for i, item := range batch {
+			recommendations := make([]*domain.KnowledgeRecommendation, len(similarByItem[i]))
+			for rank, s := range similarByItem[i] {
+				recommendations[rank] = &domain.KnowledgeRecommendation{
+					KnowledgeItemID: item.ID,
+					SourceItemID:    s.ID,
+					Rank:            rank + 1,
+					Score:           s.Score,
+					Status:          domain.RecommendationPending,
+				}
+			}
+
+			if err := u.recommendationRepo.ReplacePending(item.ID, recommendations); err != nil {
+				return nil, fmt.Errorf("推薦候補の保存に失敗しました (ID: %d): %w", item.ID, err)
+			}
+
+			if len(recommendations) > 0 {
+				result.Recommended++
+				result.Candidates += len(recommendations)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ListRecommendations は案件の推薦候補を取得する
+func (u *RecommendationUseCaseImpl) ListRecommendations(projectID int) ([]*domain.KnowledgeRecommendation, error) {
+	project, err := u.projectRepo.GetByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	recommendations, err := u.recommendationRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 推薦元の回答を推薦先の案件の値で置き換えてプレビューする（採用時は元の回答をそのままコピーする）
+	for _, r := range recommendations {
+		r.RenderedAnswer = r.SourceAnswer
+		if rendered, err := domain.RenderAnswer(r.SourceAnswer, project); err == nil {
+			r.RenderedAnswer = rendered.Rendered
+		}
+	}
+
+	return recommendations, nil
+}
+
+// AcceptRecommendation は推薦候補を採用し、推薦元の回答をアイテムにコピーする
+// 推薦の生成後にアイテムへ回答が入力された場合や、推薦元が公開済みでなくなった場合は採用できない
+func (u *RecommendationUseCaseImpl) AcceptRecommendation(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	recommendation, err := u.recommendationRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("推薦候補が存在しません: %w", err)
+	}
+
+	if err := recommendation.Accept(actor); err != nil {
+		return nil, err
+	}
+
+	item, err := u.knowledgeRepo.GetByID(recommendation.KnowledgeItemID)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+	if expectedVersion != 0 && item.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{Current: item}
+	}
+
+	answers, err := u.answerRepo.GetByKnowledgeID(item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("回答バリエーションの取得に失敗しました: %w", err)
+	}
+	item.Answers = answers
+	if !item.NeedsAnswer() {
+		return nil, &domain.ValidationError{Field: "knowledge_item_id", Message: "回答が既に入力されているため推薦候補を採用できません"}
+	}
+
+	source, err := u.knowledgeRepo.GetByID(recommendation.SourceItemID)
+	if err != nil {
+		return nil, fmt.Errorf("推薦元のナレッジアイテムが存在しません: %w", err)
+	}
+	if source.Status != domain.StatusPublished {
+		return nil, &domain.ValidationError{Field: "source_item_id", Message: "推薦元のナレッジが公開済みでないため推薦候補を採用できません"}
+	}
+
+	loadedVersion := item.Version
+	if err := item.AcceptRecommendation(source, actor); err != nil {
+		return nil, err
+	}
+
+	// 推薦元のプレースホルダーが推薦先の案件の項目・案件変数で解決できることを確認する
+	if domain.HasPlaceholders(item.Answer) {
+		project, err := u.projectRepo.GetByID(item.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("案件が存在しません: %w", err)
+		}
+		if err := item.ValidatePlaceholders(project); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.recommendationRepo.Accept(recommendation, item, loadedVersion); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			current, getErr := u.knowledgeRepo.GetByID(item.ID)
+			if getErr != nil {
+				return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", getErr)
+			}
+			return nil, &domain.VersionConflictError{Current: current}
+		}
+		return nil, fmt.Errorf("推薦候補の採用に失敗しました: %w", err)
+	}
+
+	return item, nil
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKnowledgeRecommendationRepository はKnowledgeRecommendationRepositoryのモック
+type MockKnowledgeRecommendationRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeRecommendationRepository) ReplacePending(knowledgeItemID int, recommendations []*domain.KnowledgeRecommendation) error {
+	args := m.Called(knowledgeItemID, recommendations)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeRecommendationRepository) GetByID(id int) (*domain.KnowledgeRecommendation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.KnowledgeRecommendation), args.Error(1)
+}
+
+func (m *MockKnowledgeRecommendationRepository) GetByProjectID(projectID int) ([]*domain.KnowledgeRecommendation, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeRecommendation), args.Error(1)
+}
+
+func (m *MockKnowledgeRecommendationRepository) Accept(recommendation *domain.KnowledgeRecommendation, item *domain.KnowledgeItem, expectedVersion int) error {
+	args := m.Called(recommendation, item, expectedVersion)
+	return args.Error(0)
+}
+
+func TestRecommendationUseCase_GenerateRecommendations(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	recommendationRepo := new(MockKnowledgeRecommendationRepository)
+	usecase := NewRecommendationUseCase(knowledgeRepo, projectRepo, answerRepo, recommendationRepo)
+
+	projectRepo.On("GetByID", 5).Return(&domain.Project{ID: 5}, nil)
+	knowledgeRepo.On("GetByProjectID", 5, domain.PageRequest{}).Return([]*domain.KnowledgeItem{
+		{ID: 1, ProjectID: 5, Question: "パスワードの最小文字数は？"},
+		{ID: 2, ProjectID: 5, Question: "回答済みの質問", Answer: "はい"},
+		{ID: 3, ProjectID: 5, Question: "該当のない質問", Answer: "  "},
+		{ID: 4, ProjectID: 5, Question: "プランごとに回答する質問"},
+	}, 4, nil)
+	// 回答バリエーションのあるアイテムは回答済みとして扱う
+	answerRepo.On("GetByKnowledgeIDs", []int{1, 2, 3, 4}).Return(map[int][]*domain.KnowledgeAnswer{
+		4: {{ID: 9, KnowledgeItemID: 4, Label: "SaaSプラン", Answer: "はい"}},
+	}, nil)
+
+	// 他案件の公開済みナレッジのみを対象にし、有効期限内の回答を優先する
+	opts := mock.MatchedBy(func(o domain.SimilarSearchOptions) bool {
+		return o.Threshold == domain.DefaultRecommendationThreshold &&
+			o.ExcludeProjectID == 5 &&
+			o.Status == domain.StatusPublished &&
+			o.Limit == domain.DefaultRecommendationLimit &&
+			o.PreferValidAt != nil
+	})
+	// 未回答のアイテムの類似ナレッジはまとめて検索する
+	knowledgeRepo.On("FindSimilarBatch", []string{"パスワードの最小文字数は？", "該当のない質問"}, opts).Return([][]*domain.ScoredKnowledgeItem{
+		{
+			{KnowledgeItem: &domain.KnowledgeItem{ID: 40}, Score: 0.9},
+			{KnowledgeItem: &domain.KnowledgeItem{ID: 41}, Score: 0.5},
+		},
+		{},
+	}, nil)
+
+	var saved []*domain.KnowledgeRecommendation
+	recommendationRepo.On("ReplacePending", 1, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).([]*domain.KnowledgeRecommendation)
+	}).Return(nil)
+	recommendationRepo.On("ReplacePending", 3, mock.Anything).Return(nil)
+
+	result, err := usecase.GenerateRecommendations(5, RecommendationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &RecommendationResult{ProjectID: 5, Unanswered: 2, Recommended: 1, Candidates: 2}, result)
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, 40, saved[0].SourceItemID)
+	assert.Equal(t, 1, saved[0].Rank)
+	assert.Equal(t, 0.5, saved[1].Score)
+	assert.Equal(t, domain.RecommendationPending, saved[1].Status)
+	knowledgeRepo.AssertExpectations(t)
+	recommendationRepo.AssertNotCalled(t, "ReplacePending", 4, mock.Anything)
+}
+
+func TestRecommendationUseCase_AcceptRecommendation(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	recommendationRepo := new(MockKnowledgeRecommendationRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	usecase := NewRecommendationUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, recommendationRepo)
+
+	deptID := 2
+	rec := &domain.KnowledgeRecommendation{ID: 7, KnowledgeItemID: 1, SourceItemID: 40, Status: domain.RecommendationPending}
+	item := &domain.KnowledgeItem{ID: 1, ProjectID: 5, Question: "パスワードの最小文字数は？", Version: 2}
+	source := &domain.KnowledgeItem{ID: 40, ProjectID: 3, Answer: "12文字以上です", DepartmentID: &deptID, Status: domain.StatusPublished}
+
+	recommendationRepo.On("GetByID", 7).Return(rec, nil)
+	knowledgeRepo.On("GetByID", 1).Return(item, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+	knowledgeRepo.On("GetByID", 40).Return(source, nil)
+	recommendationRepo.On("Accept", rec, item, 2).Return(nil)
+
+	updated, err := usecase.AcceptRecommendation(7, "山田太郎", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "12文字以上です", updated.Answer)
+	require.NotNil(t, updated.AnswerSourceID)
+	assert.Equal(t, 40, *updated.AnswerSourceID)
+	assert.Equal(t, &deptID, updated.DepartmentID)
+	assert.Equal(t, 3, updated.Version)
+	assert.Equal(t, domain.RecommendationAccepted, rec.Status)
+	assert.Equal(t, "山田太郎", rec.AcceptedBy)
+	recommendationRepo.AssertExpectations(t)
+}
+
+func TestRecommendationUseCase_AcceptRecommendation_AlreadyProcessed(t *testing.T) {
+	recommendationRepo := new(MockKnowledgeRecommendationRepository)
+	usecase := NewRecommendationUseCase(new(MockKnowledgeRepository), new(MockProjectRepository), new(MockKnowledgeAnswerRepository), recommendationRepo)
+
+	recommendationRepo.On("GetByID", 7).Return(&domain.KnowledgeRecommendation{ID: 7, Status: domain.RecommendationDismissed}, nil)
+
+	_, err := usecase.AcceptRecommendation(7, "山田太郎", 0)
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	recommendationRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecommendationUseCase_AcceptRecommendation_Stale(t *testing.T) {
+	t.Run("推薦の生成後に回答が入力された場合は採用できない", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		answerRepo := new(MockKnowledgeAnswerRepository)
+		recommendationRepo := new(MockKnowledgeRecommendationRepository)
+		usecase := NewRecommendationUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, recommendationRepo)
+
+		recommendationRepo.On("GetByID", 7).Return(&domain.KnowledgeRecommendation{ID: 7, KnowledgeItemID: 1, SourceItemID: 40, Status: domain.RecommendationPending}, nil)
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 5, Question: "質問", Version: 2}, nil)
+		answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{{ID: 9, Answer: "はい"}}, nil)
+
+		_, err := usecase.AcceptRecommendation(7, "山田太郎", 0)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		recommendationRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("推薦元が公開済みでなくなった場合は採用できない", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		answerRepo := new(MockKnowledgeAnswerRepository)
+		recommendationRepo := new(MockKnowledgeRecommendationRepository)
+		usecase := NewRecommendationUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, recommendationRepo)
+
+		recommendationRepo.On("GetByID", 7).Return(&domain.KnowledgeRecommendation{ID: 7, KnowledgeItemID: 1, SourceItemID: 40, Status: domain.RecommendationPending}, nil)
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 5, Question: "質問", Version: 2}, nil)
+		answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+		knowledgeRepo.On("GetByID", 40).Return(&domain.KnowledgeItem{ID: 40, ProjectID: 3, Answer: "はい", Status: domain.StatusArchived}, nil)
+
+		_, err := usecase.AcceptRecommendation(7, "山田太郎", 0)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		recommendationRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRecommendationUseCase_AcceptRecommendation_NotEditable(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	recommendationRepo := new(MockKnowledgeRecommendationRepository)
+	usecase := NewRecommendationUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, recommendationRepo)
+
+	// 承認済みのアイテムにレビューを経ない回答をコピーしない
+	recommendationRepo.On("GetByID", 7).Return(&domain.KnowledgeRecommendation{ID: 7, KnowledgeItemID: 1, SourceItemID: 40, Status: domain.RecommendationPending}, nil)
+	item := &domain.KnowledgeItem{ID: 1, ProjectID: 5, Question: "質問", Status: domain.StatusApproved, Version: 2}
+	knowledgeRepo.On("GetByID", 1).Return(item, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+	knowledgeRepo.On("GetByID", 40).Return(&domain.KnowledgeItem{ID: 40, ProjectID: 3, Answer: "はい", Status: domain.StatusPublished}, nil)
+
+	_, err := usecase.AcceptRecommendation(7, "山田太郎", 0)
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "status", validationErr.Field)
+	assert.Empty(t, item.Answer)
+	recommendationRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecommendationUseCase_AcceptRecommendation_UnknownPlaceholder(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	projectRepo := new(MockProjectRepository)
+	answerRepo := new(MockKnowledgeAnswerRepository)
+	recommendationRepo := new(MockKnowledgeRecommendationRepository)
+	usecase := NewRecommendationUseCase(knowledgeRepo, projectRepo, answerRepo, recommendationRepo)
+
+	recommendationRepo.On("GetByID", 7).Return(&domain.KnowledgeRecommendation{ID: 7, KnowledgeItemID: 1, SourceItemID: 40, Status: domain.RecommendationPending}, nil)
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 5, Question: "質問", Version: 2}, nil)
+	answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+	knowledgeRepo.On("GetByID", 40).Return(&domain.KnowledgeItem{ID: 40, ProjectID: 3, Answer: "ログは{{retention_days}}日間保持します", Status: domain.StatusPublished}, nil)
+	// 推薦先の案件にはretention_daysの案件変数がない
+	projectRepo.On("GetByID", 5).Return(&domain.Project{ID: 5, CustomerName: "テスト株式会社"}, nil)
+
+	_, err := usecase.AcceptRecommendation(7, "山田太郎", 0)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	recommendationRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// MaxReviewWithinDays は再レビュー対象の先読み日数の上限
+const MaxReviewWithinDays = 365
+
+// ReviewUseCase は回答の有効期限と定期レビューに関するビジネスロジックを提供する
+type ReviewUseCase interface {
+	// MarkDueForReview は期限を過ぎたapproved・publishedのアイテムをneeds_reviewにする（スケジューラーから定期的に呼ばれる）
+	MarkDueForReview(now time.Time) (*ReviewScanResult, error)
+	// ListDueForReview は再レビュー対象のアイテムを部門ごとに取得する
+	// withinDaysを指定すると、その日数以内に期限を迎えるアイテムも含める
+	ListDueForReview(withinDays int, departmentID *int) ([]*domain.DueForReviewGroup, error)
+}
+
+// ReviewScanResult は期限切れアイテムの確認結果
+type ReviewScanResult struct {
+	// Checked は期限を過ぎていたアイテム数
+	Checked int `json:"checked"`
+	// Marked はneeds_reviewにしたアイテム数
+	Marked int `json:"marked"`
+	// Skipped は確認中に他の更新と競合したアイテム数（次回の確認で再度対象になる）
+	Skipped int `json:"skipped"`
+}
+
+// ReviewUseCaseImpl はReviewUseCaseの実装
+type ReviewUseCaseImpl struct {
+	reviewRepo     domain.KnowledgeReviewRepository
+	workflowRepo   domain.KnowledgeWorkflowRepository
+	departmentRepo domain.DepartmentRepository
+}
+
+// NewReviewUseCase は新しいReviewUseCaseを生成する
+func NewReviewUseCase(
+	reviewRepo domain.KnowledgeReviewRepository,
+	workflowRepo domain.KnowledgeWorkflowRepository,
+	departmentRepo domain.DepartmentRepository,
+) ReviewUseCase {
+	return &ReviewUseCaseImpl{
+		reviewRepo:     reviewRepo,
+		workflowRepo:   workflowRepo,
+		departmentRepo: departmentRepo,
+	}
+}
+
+// MarkDueForReview は期限を過ぎたアイテムをneeds_reviewにし、遷移を記録する
+func (u *ReviewUseCaseImpl) MarkDueForReview(now time.Time) (*ReviewScanResult, error) {
+	items, err := u.reviewRepo.GetExpiring(now)
+	if err != nil {
+		return nil, fmt.Errorf("期限切れのナレッジの取得に失敗しました: %w", err)
+	}
+
+	result := &ReviewScanResult{Checked: len(items)}
+	for _, item := range items {
+		loadedVersion := item.Version
+		transition, err := item.MarkNeedsReview(domain.ReviewSchedulerActor, now)
+		if err != nil {
+			return result, err
+		}
+
+		if err := u.workflowRepo.Transition(item, loadedVersion, transition); err != nil {
+			// 取得後に編集・遷移されたアイテムは次回の確認に回す
+			if errors.Is(err, domain.ErrVersionConflict) {
+				result.Skipped++
+				continue
+			}
+			return result, fmt.Errorf("ステータスの変更に失敗しました (ID: %d): %w", item.ID, err)
+		}
+		result.Marked++
+	}
+
+	return result, nil
+}
+
+// ListDueForReview は再レビュー対象のアイテムを部門ごとにまとめて取得する
+func (u *ReviewUseCaseImpl) ListDueForReview(withinDays int, departmentID *int) ([]*domain.DueForReviewGroup, error) {
+	if withinDays < 0 || withinDays > MaxReviewWithinDays {
+		return nil, &domain.ValidationError{Field: "within_days", Message: fmt.Sprintf("within_daysは0以上%d以下である必要があります", MaxReviewWithinDays)}
+	}
+
+	before := time.Now().AddDate(0, 0, withinDays)
+	items, err := u.reviewRepo.GetDueForReview(before, departmentID)
+	if err != nil {
+		return nil, fmt.Errorf("再レビュー対象のナレッジの取得に失敗しました: %w", err)
+	}
+
+	departments, err := u.departmentRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("部門の取得に失敗しました: %w", err)
+	}
+
+	return domain.GroupDueForReview(items, departments), nil
+}
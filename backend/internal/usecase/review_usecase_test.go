@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKnowledgeReviewRepository はKnowledgeReviewRepositoryのモック
+type MockKnowledgeReviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeReviewRepository) GetExpiring(now time.Time) ([]*domain.KnowledgeItem, error) {
+	args := m.Called(now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeItem), args.Error(1)
+}
+
+func (m *MockKnowledgeReviewRepository) GetDueForReview(before time.Time, departmentID *int) ([]*domain.KnowledgeItem, error) {
+	args := m.Called(before, departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeItem), args.Error(1)
+}
+
+// MockKnowledgeWorkflowRepository はKnowledgeWorkflowRepositoryのモック
+type MockKnowledgeWorkflowRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeWorkflowRepository) Transition(item *domain.KnowledgeItem, expectedVersion int, transition *domain.StatusTransition) error {
+	args := m.Called(item, expectedVersion, transition)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeWorkflowRepository) GetByKnowledgeID(knowledgeID int) ([]*domain.StatusTransition, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.StatusTransition), args.Error(1)
+}
+
+// MockDepartmentRepository はDepartmentRepositoryのモック
+type MockDepartmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockDepartmentRepository) GetAll() ([]*domain.Department, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) GetByID(id int) (*domain.Department, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Department), args.Error(1)
+}
+
+func TestReviewUseCase_MarkDueForReview(t *testing.T) {
+	reviewRepo := new(MockKnowledgeReviewRepository)
+	workflowRepo := new(MockKnowledgeWorkflowRepository)
+	usecase := NewReviewUseCase(reviewRepo, workflowRepo, new(MockDepartmentRepository))
+
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, 0, -7)
+	expired := &domain.KnowledgeItem{ID: 1, Status: domain.StatusPublished, ValidUntil: &past, Version: 3}
+	conflicted := &domain.KnowledgeItem{ID: 2, Status: domain.StatusApproved, ReviewDueAt: &past, Version: 5}
+
+	reviewRepo.On("GetExpiring", now).Return([]*domain.KnowledgeItem{expired, conflicted}, nil)
+	workflowRepo.On("Transition", expired, 3, mock.MatchedBy(func(tr *domain.StatusTransition) bool {
+		return tr.FromStatus == domain.StatusPublished && tr.ToStatus == domain.StatusNeedsReview && tr.Actor == domain.ReviewSchedulerActor
+	})).Return(nil)
+	// 取得後に編集されたアイテムは次回に持ち越す
+	workflowRepo.On("Transition", conflicted, 5, mock.Anything).Return(domain.ErrVersionConflict)
+
+	result, err := usecase.MarkDueForReview(now)
+	require.NoError(t, err)
+	assert.Equal(t, &ReviewScanResult{Checked: 2, Marked: 1, Skipped: 1}, result)
+	assert.Equal(t, domain.StatusNeedsReview, expired.Status)
+	assert.Equal(t, 4, expired.Version)
+	workflowRepo.AssertExpectations(t)
+}
+
+func TestReviewUseCase_ListDueForReview(t *testing.T) {
+	reviewRepo := new(MockKnowledgeReviewRepository)
+	departmentRepo := new(MockDepartmentRepository)
+	usecase := NewReviewUseCase(reviewRepo, new(MockKnowledgeWorkflowRepository), departmentRepo)
+
+	deptID := 2
+	items := []*domain.KnowledgeItem{
+		{ID: 1, DepartmentID: &deptID, Status: domain.StatusNeedsReview},
+		{ID: 2, Status: domain.StatusPublished},
+	}
+
+	start := time.Now()
+	reviewRepo.On("GetDueForReview", mock.MatchedBy(func(before time.Time) bool {
+		// within_days分だけ先の期限まで含める
+		return !before.Before(start.AddDate(0, 0, 30))
+	}), (*int)(nil)).Return(items, nil)
+	departmentRepo.On("GetAll").Return([]*domain.Department{{ID: 1, Name: "情報システム"}, {ID: 2, Name: "法務"}}, nil)
+
+	groups, err := usecase.ListDueForReview(30, nil)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "法務", groups[0].DepartmentName)
+	assert.Equal(t, 1, groups[0].Count)
+	assert.Nil(t, groups[1].DepartmentID)
+	assert.Equal(t, 2, groups[1].Items[0].ID)
+
+	_, err = usecase.ListDueForReview(MaxReviewWithinDays+1, nil)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
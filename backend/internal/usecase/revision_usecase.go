@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// RevisionUseCase はナレッジの変更履歴に関するビジネスロジックを提供する
+type RevisionUseCase interface {
+	ListRevisions(knowledgeID int) ([]*domain.KnowledgeRevision, error)
+	DiffRevisions(knowledgeID int, fromRevision int, toRevision int) (*domain.RevisionDiff, error)
+	// RestoreRevision は履歴の内容に戻す。expectedVersionに0を指定した場合は読み込み時の版で確認する
+	RestoreRevision(knowledgeID int, revision int, restoredBy string, expectedVersion int) (*domain.KnowledgeItem, error)
+}
+
+// RevisionUseCaseImpl はRevisionUseCaseの実装
+type RevisionUseCaseImpl struct {
+	knowledgeRepo domain.KnowledgeRepository
+	revisionRepo  domain.KnowledgeRevisionRepository
+	// knowledgeUseCase は復元を通常の編集と同じ検証で保存するために使う
+	knowledgeUseCase KnowledgeUseCase
+}
+
+// NewRevisionUseCase は新しいRevisionUseCaseを生成する
+func NewRevisionUseCase(
+	knowledgeRepo domain.KnowledgeRepository,
+	revisionRepo domain.KnowledgeRevisionRepository,
+	knowledgeUseCase KnowledgeUseCase,
+) RevisionUseCase {
+	return &RevisionUseCaseImpl{
+		knowledgeRepo:    knowledgeRepo,
+		revisionRepo:     revisionRepo,
+		knowledgeUseCase: knowledgeUseCase,
+	}
+}
+
+// ListRevisions はナレッジアイテムの履歴を新しい順に取得する
+func (u *RevisionUseCaseImpl) ListRevisions(knowledgeID int) ([]*domain.KnowledgeRevision, error) {
+	// 存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	return u.revisionRepo.GetByKnowledgeID(knowledgeID)
+}
+
+// DiffRevisions は2つの履歴の差分を取得する
+func (u *RevisionUseCaseImpl) DiffRevisions(knowledgeID int, fromRevision int, toRevision int) (*domain.RevisionDiff, error) {
+	// 存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	from, err := u.revisionRepo.GetByRevision(knowledgeID, fromRevision)
+	if err != nil {
+		return nil, fmt.Errorf("履歴が存在しません (revision: %d): %w", fromRevision, err)
+	}
+
+	to, err := u.revisionRepo.GetByRevision(knowledgeID, toRevision)
+	if err != nil {
+		return nil, fmt.Errorf("履歴が存在しません (revision: %d): %w", toRevision, err)
+	}
+
+	return domain.DiffRevisions(from, to), nil
+}
+
+// RestoreRevision はナレッジアイテムを指定された履歴の内容に戻す
+// 復元は新しい版として記録され、既存の履歴は変更しない
+// 通常の編集と同じく、質問・回答を戻せるのはdraft/rejectedのアイテムに限り、回答バリエーションとプレースホルダーも検証する
+func (u *RevisionUseCaseImpl) RestoreRevision(knowledgeID int, revision int, restoredBy string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	item, err := u.knowledgeRepo.GetByID(knowledgeID)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+	if expectedVersion == 0 {
+		expectedVersion = item.Version
+	}
+
+	rev, err := u.revisionRepo.GetByRevision(knowledgeID, revision)
+	if err != nil {
+		return nil, fmt.Errorf("履歴が存在しません (revision: %d): %w", revision, err)
+	}
+
+	return u.knowledgeUseCase.UpdateKnowledge(knowledgeID, rev.RestorePatch(restoredBy), expectedVersion)
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKnowledgeRevisionRepository はKnowledgeRevisionRepositoryのモック
+type MockKnowledgeRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnowledgeRevisionRepository) GetByKnowledgeID(knowledgeID int) ([]*domain.KnowledgeRevision, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.KnowledgeRevision), args.Error(1)
+}
+
+func (m *MockKnowledgeRevisionRepository) GetByRevision(knowledgeID int, revision int) (*domain.KnowledgeRevision, error) {
+	args := m.Called(knowledgeID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.KnowledgeRevision), args.Error(1)
+}
+
+// newTestRevisionUseCase は復元を実際のKnowledgeUseCaseで検証するRevisionUseCaseを生成する
+func newTestRevisionUseCase(knowledgeRepo *MockKnowledgeRepository, answerRepo *MockKnowledgeAnswerRepository, revisionRepo *MockKnowledgeRevisionRepository) RevisionUseCase {
+	knowledgeUseCase := NewKnowledgeUseCase(knowledgeRepo, new(MockProjectRepository), answerRepo, new(MockDepartmentRepository))
+	return NewRevisionUseCase(knowledgeRepo, revisionRepo, knowledgeUseCase)
+}
+
+func TestRevisionUseCase_RestoreRevision(t *testing.T) {
+	oldRevision := &domain.KnowledgeRevision{KnowledgeItemID: 1, Revision: 1, Version: 1, Question: "古い質問", Answer: "古い回答", Status: domain.StatusDraft}
+
+	t.Run("下書きのアイテムを履歴の内容に戻す", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		answerRepo := new(MockKnowledgeAnswerRepository)
+		revisionRepo := new(MockKnowledgeRevisionRepository)
+		usecase := newTestRevisionUseCase(knowledgeRepo, answerRepo, revisionRepo)
+
+		current := &domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "新しい質問", Answer: "新しい回答", Status: domain.StatusDraft, Version: 3}
+		knowledgeRepo.On("GetByID", 1).Return(current, nil)
+		revisionRepo.On("GetByRevision", 1, 1).Return(oldRevision, nil)
+		answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+		knowledgeRepo.On("Update", current, 3).Return(nil)
+
+		item, err := usecase.RestoreRevision(1, 1, "佐藤花子", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "古い質問", item.Question)
+		assert.Equal(t, "古い回答", item.Answer)
+		assert.Equal(t, domain.StatusDraft, item.Status)
+		assert.Equal(t, 4, item.Version)
+		knowledgeRepo.AssertExpectations(t)
+	})
+
+	t.Run("公開済みのアイテムは戻せない", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		answerRepo := new(MockKnowledgeAnswerRepository)
+		revisionRepo := new(MockKnowledgeRevisionRepository)
+		usecase := newTestRevisionUseCase(knowledgeRepo, answerRepo, revisionRepo)
+
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "新しい質問", Answer: "新しい回答", Status: domain.StatusPublished, Version: 3}, nil)
+		revisionRepo.On("GetByRevision", 1, 1).Return(oldRevision, nil)
+		answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{}, nil)
+
+		_, err := usecase.RestoreRevision(1, 1, "佐藤花子", 3)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("回答バリエーションがあるアイテムの回答は戻せない", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		answerRepo := new(MockKnowledgeAnswerRepository)
+		revisionRepo := new(MockKnowledgeRevisionRepository)
+		usecase := newTestRevisionUseCase(knowledgeRepo, answerRepo, revisionRepo)
+
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "新しい質問", Answer: "新しい回答", Status: domain.StatusDraft, Version: 3}, nil)
+		revisionRepo.On("GetByRevision", 1, 1).Return(oldRevision, nil)
+		answerRepo.On("GetByKnowledgeID", 1).Return([]*domain.KnowledgeAnswer{{ID: 5, KnowledgeItemID: 1, Answer: "新しい回答"}}, nil)
+
+		_, err := usecase.RestoreRevision(1, 1, "佐藤花子", 3)
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "answer", validationErr.Field)
+		knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("版が古い場合は最新の内容とともに競合を返す", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		answerRepo := new(MockKnowledgeAnswerRepository)
+		revisionRepo := new(MockKnowledgeRevisionRepository)
+		usecase := newTestRevisionUseCase(knowledgeRepo, answerRepo, revisionRepo)
+
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "新しい質問", Status: domain.StatusDraft, Version: 4}, nil)
+		revisionRepo.On("GetByRevision", 1, 1).Return(oldRevision, nil)
+
+		_, err := usecase.RestoreRevision(1, 1, "佐藤花子", 3)
+		var conflict *domain.VersionConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, 4, conflict.Current.Version)
+		knowledgeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRevisionUseCase_DiffRevisions_KnowledgeNotFound(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	revisionRepo := new(MockKnowledgeRevisionRepository)
+	usecase := newTestRevisionUseCase(knowledgeRepo, new(MockKnowledgeAnswerRepository), revisionRepo)
+
+	knowledgeRepo.On("GetByID", 99).Return(nil, sql.ErrNoRows)
+
+	_, err := usecase.DiffRevisions(99, 1, 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Contains(t, err.Error(), "ナレッジアイテムが存在しません")
+	revisionRepo.AssertNotCalled(t, "GetByRevision", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// BulkTagRequest は複数のナレッジアイテムへの一括タグ付け
+type BulkTagRequest struct {
+	KnowledgeIDs []int
+	AddTagIDs    []int
+	RemoveTagIDs []int
+	Actor        string
+}
+
+// BulkTagResult は一括タグ付けの結果
+type BulkTagResult struct {
+	// Added は新たに追加した紐づけの数、Removed は削除した紐づけの数（既存の紐づけは数えない）
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+}
+
+// TagUseCase はタグ分類に関するビジネスロジックを提供する
+type TagUseCase interface {
+	CreateTag(tag *domain.Tag) error
+	GetTag(id int) (*domain.Tag, error)
+	// ListTags はタグを親の直後に子が並ぶ順で取得する
+	ListTags() ([]*domain.Tag, error)
+	UpdateTag(tag *domain.Tag) error
+	DeleteTag(id int) error
+	GetKnowledgeTags(knowledgeID int) ([]*domain.Tag, error)
+	SetKnowledgeTags(knowledgeID int, tagIDs []int, actor string) ([]*domain.Tag, error)
+	BulkTag(req BulkTagRequest) (*BulkTagResult, error)
+	// MigrateQuestionGroups は既存の質問グループの値をタグに移行する（繰り返し実行しても結果は変わらない）
+	MigrateQuestionGroups(actor string) (*domain.QuestionGroupMigrationResult, error)
+}
+
+// TagUseCaseImpl はTagUseCaseの実装
+type TagUseCaseImpl struct {
+	tagRepo       domain.TagRepository
+	knowledgeRepo domain.KnowledgeRepository
+}
+
+// NewTagUseCase は新しいTagUseCaseを生成する
+func NewTagUseCase(tagRepo domain.TagRepository, knowledgeRepo domain.KnowledgeRepository) TagUseCase {
+	return &TagUseCaseImpl{
+		tagRepo:       tagRepo,
+		knowledgeRepo: knowledgeRepo,
+	}
+}
+
+// CreateTag はタグを作成する
+func (u *TagUseCaseImpl) CreateTag(tag *domain.Tag) error {
+	if err := tag.Validate(); err != nil {
+		return err
+	}
+
+	tags, err := u.tagRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("タグの取得に失敗しました: %w", err)
+	}
+	if err := domain.NewTagTree(tags).ValidatePlacement(tag); err != nil {
+		return err
+	}
+
+	if err := u.tagRepo.Create(tag); err != nil {
+		return fmt.Errorf("タグの作成に失敗しました: %w", err)
+	}
+
+	// 階層パスを設定する
+	domain.NewTagTree(append(tags, tag))
+	return nil
+}
+
+// GetTag はタグを取得する
+func (u *TagUseCaseImpl) GetTag(id int) (*domain.Tag, error) {
+	if _, err := u.tagRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("タグが存在しません: %w", err)
+	}
+
+	tree, err := u.tagTree()
+	if err != nil {
+		return nil, err
+	}
+	return tree.Get(id), nil
+}
+
+// ListTags はタグの一覧を階層順に取得する
+func (u *TagUseCaseImpl) ListTags() ([]*domain.Tag, error) {
+	tree, err := u.tagTree()
+	if err != nil {
+		return nil, err
+	}
+	return tree.Flatten(), nil
+}
+
+// UpdateTag はタグの名前・親・説明を更新する
+func (u *TagUseCaseImpl) UpdateTag(tag *domain.Tag) error {
+	current, err := u.tagRepo.GetByID(tag.ID)
+	if err != nil {
+		return fmt.Errorf("タグが存在しません: %w", err)
+	}
+
+	tag.Name = strings.TrimSpace(tag.Name)
+	if err := tag.Validate(); err != nil {
+		return err
+	}
+
+	tags, err := u.tagRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("タグの取得に失敗しました: %w", err)
+	}
+	if err := domain.NewTagTree(tags).ValidatePlacement(tag); err != nil {
+		return err
+	}
+
+	tag.ItemCount = current.ItemCount
+	tag.CreatedAt = current.CreatedAt
+	tag.UpdatedAt = time.Now()
+
+	if err := u.tagRepo.Update(tag); err != nil {
+		return fmt.Errorf("タグの更新に失敗しました: %w", err)
+	}
+
+	// 更新後の階層パスを設定する
+	for i, t := range tags {
+		if t.ID == tag.ID {
+			tags[i] = tag
+		}
+	}
+	domain.NewTagTree(tags)
+	return nil
+}
+
+// DeleteTag はタグを削除する。子タグがある場合は削除できない
+func (u *TagUseCaseImpl) DeleteTag(id int) error {
+	// 存在確認
+	if _, err := u.tagRepo.GetByID(id); err != nil {
+		return fmt.Errorf("タグが存在しません: %w", err)
+	}
+
+	tree, err := u.tagTree()
+	if err != nil {
+		return err
+	}
+	if tree.HasChildren(id) {
+		return &domain.ValidationError{Field: "id", Message: "子タグがあるタグは削除できません。先に子タグを削除または移動してください"}
+	}
+
+	return u.tagRepo.Delete(id)
+}
+
+// GetKnowledgeTags はナレッジアイテムに紐づくタグを取得する
+func (u *TagUseCaseImpl) GetKnowledgeTags(knowledgeID int) ([]*domain.Tag, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	return u.knowledgeTags(knowledgeID)
+}
+
+// SetKnowledgeTags はナレッジアイテムに紐づけるタグを置き換える
+func (u *TagUseCaseImpl) SetKnowledgeTags(knowledgeID int, tagIDs []int, actor string) ([]*domain.Tag, error) {
+	// ナレッジアイテムの存在確認
+	if _, err := u.knowledgeRepo.GetByID(knowledgeID); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	ids, err := u.validateTagIDs(tagIDs, "tag_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.tagRepo.ReplaceKnowledgeTags(knowledgeID, ids, actor); err != nil {
+		return nil, fmt.Errorf("タグの紐づけに失敗しました: %w", err)
+	}
+
+	return u.knowledgeTags(knowledgeID)
+}
+
+// BulkTag は複数のナレッジアイテムにタグを一括で追加・削除する
+func (u *TagUseCaseImpl) BulkTag(req BulkTagRequest) (*BulkTagResult, error) {
+	knowledgeIDs := uniqueInts(req.KnowledgeIDs)
+	if len(knowledgeIDs) == 0 {
+		return nil, &domain.ValidationError{Field: "knowledge_ids", Message: "ナレッジIDを1件以上指定してください"}
+	}
+	if len(req.AddTagIDs) == 0 && len(req.RemoveTagIDs) == 0 {
+		return nil, &domain.ValidationError{Field: "add_tag_ids", Message: "追加または削除するタグを指定してください"}
+	}
+
+	addIDs, err := u.validateTagIDs(req.AddTagIDs, "add_tag_ids")
+	if err != nil {
+		return nil, err
+	}
+	removeIDs, err := u.validateTagIDs(req.RemoveTagIDs, "remove_tag_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	for _, id := range knowledgeIDs {
+		if _, err := u.knowledgeRepo.GetByID(id); err != nil {
+			missing = append(missing, fmt.Sprint(id))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &domain.ValidationError{Field: "knowledge_ids", Message: fmt.Sprintf("ナレッジアイテムが存在しません: %s", strings.Join(missing, ", "))}
+	}
+
+	added, removed, err := u.tagRepo.BulkTag(knowledgeIDs, addIDs, removeIDs, req.Actor)
+	if err != nil {
+		return nil, fmt.Errorf("一括タグ付けに失敗しました: %w", err)
+	}
+
+	return &BulkTagResult{Added: added, Removed: removed}, nil
+}
+
+// MigrateQuestionGroups は既存の質問グループの値をタグに移行する
+// 表記ゆれ（全角・半角、空白）は正規化して同じタグにまとめ、「>」で区切られた値は階層として扱う
+// 移行後も質問グループの値は残し、統制項目との対応付けと検索の絞り込みは引き続き質問グループを基準にする
+func (u *TagUseCaseImpl) MigrateQuestionGroups(actor string) (*domain.QuestionGroupMigrationResult, error) {
+	groups, err := u.tagRepo.GetQuestionGroups()
+	if err != nil {
+		return nil, fmt.Errorf("質問グループの取得に失敗しました: %w", err)
+	}
+
+	// タグを作成する前にすべての値を検証し、移行できない値があれば何も変更しない
+	paths := make([]*domain.QuestionGroupTagPath, 0, len(groups))
+	for _, group := range groups {
+		path, err := domain.NewQuestionGroupTagPath(group)
+		if err != nil {
+			return nil, fmt.Errorf("質問グループ「%s」を移行できません: %w", group, err)
+		}
+		if path != nil {
+			paths = append(paths, path)
+		}
+	}
+
+	result, err := u.tagRepo.MigrateQuestionGroups(paths, actor)
+	if err != nil {
+		return nil, fmt.Errorf("質問グループの移行に失敗しました: %w", err)
+	}
+
+	return result, nil
+}
+
+// tagTree はすべてのタグから木構造を組み立てる
+func (u *TagUseCaseImpl) tagTree() (*domain.TagTree, error) {
+	tags, err := u.tagRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("タグの取得に失敗しました: %w", err)
+	}
+	return domain.NewTagTree(tags), nil
+}
+
+// knowledgeTags はアイテムに紐づくタグを階層パス付きで取得する
+func (u *TagUseCaseImpl) knowledgeTags(knowledgeID int) ([]*domain.Tag, error) {
+	tree, err := u.tagTree()
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := u.tagRepo.GetByKnowledgeID(knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*domain.Tag, 0, len(linked))
+	for _, t := range linked {
+		if full := tree.Get(t.ID); full != nil {
+			t = full
+		}
+		tags = append(tags, t)
+	}
+	return tags, nil
+}
+
+// validateTagIDs はタグIDの重複を除き、すべて存在することを確認する
+func (u *TagUseCaseImpl) validateTagIDs(tagIDs []int, field string) ([]int, error) {
+	ids := uniqueInts(tagIDs)
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	count, err := u.tagRepo.CountTags(ids)
+	if err != nil {
+		return nil, fmt.Errorf("タグの確認に失敗しました: %w", err)
+	}
+	if count != len(ids) {
+		return nil, &domain.ValidationError{Field: field, Message: "存在しないタグが含まれています"}
+	}
+
+	return ids, nil
+}
+
+// uniqueInts は出現順を保ったまま重複を除く
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
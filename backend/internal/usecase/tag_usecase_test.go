@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTagRepository はTagRepositoryのモック
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(tag *domain.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(id int) (*domain.Tag, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetAll() ([]*domain.Tag, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Update(tag *domain.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) CountTags(ids []int) (int, error) {
+	args := m.Called(ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByKnowledgeID(knowledgeID int) ([]*domain.Tag, error) {
+	args := m.Called(knowledgeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) ReplaceKnowledgeTags(knowledgeID int, tagIDs []int, actor string) error {
+	args := m.Called(knowledgeID, tagIDs, actor)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) BulkTag(knowledgeIDs []int, addTagIDs []int, removeTagIDs []int, actor string) (int, int, error) {
+	args := m.Called(knowledgeIDs, addTagIDs, removeTagIDs, actor)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockTagRepository) GetQuestionGroups() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTagRepository) MigrateQuestionGroups(paths []*domain.QuestionGroupTagPath, actor string) (*domain.QuestionGroupMigrationResult, error) {
+	args := m.Called(paths, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.QuestionGroupMigrationResult), args.Error(1)
+}
+
+func TestTagUseCase_CreateTag(t *testing.T) {
+	tagRepo := new(MockTagRepository)
+	usecase := NewTagUseCase(tagRepo, new(MockKnowledgeRepository))
+
+	tagRepo.On("GetAll").Return([]*domain.Tag{{ID: 1, Name: "アクセス制御"}}, nil)
+	tagRepo.On("Create", mock.AnythingOfType("*domain.Tag")).Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.Tag).ID = 2
+	}).Return(nil)
+
+	parentID := 1
+	tag := domain.NewTag("認証", &parentID, "")
+	require.NoError(t, usecase.CreateTag(tag))
+	assert.Equal(t, "アクセス制御 > 認証", tag.Path)
+	assert.Equal(t, 1, tag.Depth)
+}
+
+func TestTagUseCase_CreateTag_DuplicateSibling(t *testing.T) {
+	tagRepo := new(MockTagRepository)
+	usecase := NewTagUseCase(tagRepo, new(MockKnowledgeRepository))
+
+	tagRepo.On("GetAll").Return([]*domain.Tag{{ID: 1, Name: "アクセス制御"}}, nil)
+
+	var validationErr *domain.ValidationError
+	err := usecase.CreateTag(domain.NewTag("アクセス制御", nil, ""))
+	assert.ErrorAs(t, err, &validationErr)
+	tagRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTagUseCase_UpdateTag_RejectsCycle(t *testing.T) {
+	tagRepo := new(MockTagRepository)
+	usecase := NewTagUseCase(tagRepo, new(MockKnowledgeRepository))
+
+	parentID := 1
+	tags := []*domain.Tag{{ID: 1, Name: "アクセス制御"}, {ID: 2, Name: "認証", ParentID: &parentID}}
+	tagRepo.On("GetByID", 1).Return(tags[0], nil)
+	tagRepo.On("GetAll").Return(tags, nil)
+
+	newParentID := 2
+	var validationErr *domain.ValidationError
+	err := usecase.UpdateTag(&domain.Tag{ID: 1, Name: "アクセス制御", ParentID: &newParentID})
+	assert.ErrorAs(t, err, &validationErr)
+	tagRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTagUseCase_DeleteTag_WithChildren(t *testing.T) {
+	tagRepo := new(MockTagRepository)
+	usecase := NewTagUseCase(tagRepo, new(MockKnowledgeRepository))
+
+	parentID := 1
+	tagRepo.On("GetByID", 1).Return(&domain.Tag{ID: 1}, nil)
+	tagRepo.On("GetAll").Return([]*domain.Tag{{ID: 1, Name: "アクセス制御"}, {ID: 2, Name: "認証", ParentID: &parentID}}, nil)
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, usecase.DeleteTag(1), &validationErr)
+	tagRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestTagUseCase_BulkTag(t *testing.T) {
+	tagRepo := new(MockTagRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewTagUseCase(tagRepo, knowledgeRepo)
+
+	tagRepo.On("CountTags", []int{1}).Return(1, nil)
+	tagRepo.On("CountTags", []int{2}).Return(1, nil)
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	knowledgeRepo.On("GetByID", 11).Return(&domain.KnowledgeItem{ID: 11}, nil)
+	tagRepo.On("BulkTag", []int{10, 11}, []int{1}, []int{2}, "山田太郎").Return(2, 1, nil)
+
+	result, err := usecase.BulkTag(BulkTagRequest{
+		KnowledgeIDs: []int{10, 11, 10},
+		AddTagIDs:    []int{1},
+		RemoveTagIDs: []int{2},
+		Actor:        "山田太郎",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Added)
+	assert.Equal(t, 1, result.Removed)
+	tagRepo.AssertExpectations(t)
+}
+
+func TestTagUseCase_BulkTag_MissingItems(t *testing.T) {
+	tagRepo := new(MockTagRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewTagUseCase(tagRepo, knowledgeRepo)
+
+	tagRepo.On("CountTags", []int{1}).Return(1, nil)
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10}, nil)
+	knowledgeRepo.On("GetByID", 99).Return(nil, sql.ErrNoRows)
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.BulkTag(BulkTagRequest{KnowledgeIDs: []int{10, 99}, AddTagIDs: []int{1}})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Message, "99")
+	tagRepo.AssertNotCalled(t, "BulkTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTagUseCase_MigrateQuestionGroups(t *testing.T) {
+	t.Run("正規化したタグの階層をまとめて移行する", func(t *testing.T) {
+		tagRepo := new(MockTagRepository)
+		usecase := NewTagUseCase(tagRepo, new(MockKnowledgeRepository))
+
+		// 全角・半角や空白の違いは同じタグ名にし、「>」は階層として扱う
+		tagRepo.On("GetQuestionGroups").Return([]string{"アクセス制御", "ｱｸｾｽ制御 ", "アクセス制御 ＞ 認証", " > "}, nil)
+		expected := &domain.QuestionGroupMigrationResult{TagsCreated: 2, ItemsTagged: 6}
+		tagRepo.On("MigrateQuestionGroups", mock.MatchedBy(func(paths []*domain.QuestionGroupTagPath) bool {
+			return len(paths) == 3 &&
+				paths[0].QuestionGroup == "アクセス制御" && reflect.DeepEqual(paths[0].Names, []string{"アクセス制御"}) &&
+				paths[1].QuestionGroup == "ｱｸｾｽ制御 " && reflect.DeepEqual(paths[1].Names, []string{"アクセス制御"}) &&
+				reflect.DeepEqual(paths[2].Names, []string{"アクセス制御", "認証"})
+		}), "山田太郎").Return(expected, nil)
+
+		result, err := usecase.MigrateQuestionGroups("山田太郎")
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+		tagRepo.AssertExpectations(t)
+	})
+
+	t.Run("移行できない値があれば何も変更しない", func(t *testing.T) {
+		tagRepo := new(MockTagRepository)
+		usecase := NewTagUseCase(tagRepo, new(MockKnowledgeRepository))
+
+		tagRepo.On("GetQuestionGroups").Return([]string{"アクセス制御", strings.Repeat("あ", 101)}, nil)
+
+		_, err := usecase.MigrateQuestionGroups("山田太郎")
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		tagRepo.AssertNotCalled(t, "MigrateQuestionGroups", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// TemplateUseCase は回答のプレースホルダーと案件変数に関するビジネスロジックを提供する
+type TemplateUseCase interface {
+	// SetProjectVariables は案件変数を置き換える
+	SetProjectVariables(projectID int, variables map[string]string) (*domain.Project, error)
+	// RenderAnswer は任意の回答文を案件の値でプレビューする
+	RenderAnswer(projectID int, template string) (*domain.RenderedAnswer, error)
+	// RenderKnowledge はナレッジアイテムの回答を案件の値でプレビューする。projectIDに0を指定した場合はアイテムの案件を使う
+	RenderKnowledge(knowledgeID int, projectID int) (*domain.RenderedAnswer, error)
+}
+
+// TemplateUseCaseImpl はTemplateUseCaseの実装
+type TemplateUseCaseImpl struct {
+	projectRepo   domain.ProjectRepository
+	knowledgeRepo domain.KnowledgeRepository
+}
+
+// NewTemplateUseCase は新しいTemplateUseCaseを生成する
+func NewTemplateUseCase(projectRepo domain.ProjectRepository, knowledgeRepo domain.KnowledgeRepository) TemplateUseCase {
+	return &TemplateUseCaseImpl{
+		projectRepo:   projectRepo,
+		knowledgeRepo: knowledgeRepo,
+	}
+}
+
+// SetProjectVariables は案件変数を置き換える
+func (u *TemplateUseCaseImpl) SetProjectVariables(projectID int, variables map[string]string) (*domain.Project, error) {
+	project, err := u.projectRepo.GetByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	project.Variables = variables
+	if err := project.ValidateVariables(); err != nil {
+		return nil, err
+	}
+
+	if err := u.projectRepo.UpdateVariables(projectID, variables); err != nil {
+		return nil, fmt.Errorf("案件変数の更新に失敗しました: %w", err)
+	}
+
+	return project, nil
+}
+
+// RenderAnswer は任意の回答文を案件の値でプレビューする
+func (u *TemplateUseCaseImpl) RenderAnswer(projectID int, template string) (*domain.RenderedAnswer, error) {
+	project, err := u.projectRepo.GetByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("案件が存在しません: %w", err)
+	}
+
+	return domain.RenderAnswer(template, project)
+}
+
+// RenderKnowledge はナレッジアイテムの回答を案件の値でプレビューする
+func (u *TemplateUseCaseImpl) RenderKnowledge(knowledgeID int, projectID int) (*domain.RenderedAnswer, error) {
+	item, err := u.knowledgeRepo.GetByID(knowledgeID)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	if projectID == 0 {
+		projectID = item.ProjectID
+	}
+
+	return u.RenderAnswer(projectID, item.Answer)
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateUseCase_SetProjectVariables(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	usecase := NewTemplateUseCase(projectRepo, new(MockKnowledgeRepository))
+
+	variables := map[string]string{"product": "SecureBox", "retention_days": "90"}
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1, CustomerName: "テスト株式会社"}, nil)
+	projectRepo.On("UpdateVariables", 1, variables).Return(nil)
+
+	project, err := usecase.SetProjectVariables(1, variables)
+	require.NoError(t, err)
+	assert.Equal(t, "90", project.Variables["retention_days"])
+	projectRepo.AssertExpectations(t)
+}
+
+func TestTemplateUseCase_SetProjectVariables_Invalid(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	usecase := NewTemplateUseCase(projectRepo, new(MockKnowledgeRepository))
+
+	projectRepo.On("GetByID", 1).Return(&domain.Project{ID: 1, CustomerName: "テスト株式会社"}, nil)
+
+	var validationErr *domain.ValidationError
+	_, err := usecase.SetProjectVariables(1, map[string]string{"customer_name": "別名"})
+	assert.ErrorAs(t, err, &validationErr)
+	projectRepo.AssertNotCalled(t, "UpdateVariables", mock.Anything, mock.Anything)
+}
+
+func TestTemplateUseCase_RenderKnowledge_OtherProject(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	knowledgeRepo := new(MockKnowledgeRepository)
+	usecase := NewTemplateUseCase(projectRepo, knowledgeRepo)
+
+	knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10, ProjectID: 1, Answer: "{{customer_name}}様のデータは{{retention_days}}日間保持します"}, nil)
+	projectRepo.On("GetByID", 2).Return(&domain.Project{ID: 2, CustomerName: "サンプル商事", Variables: map[string]string{"retention_days": "30"}}, nil)
+
+	// 別の案件で再利用した場合の回答をプレビューする
+	rendered, err := usecase.RenderKnowledge(10, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "サンプル商事様のデータは30日間保持します", rendered.Rendered)
+	assert.Empty(t, rendered.Unknown)
+}
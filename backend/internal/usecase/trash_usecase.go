@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// TrashUseCase はゴミ箱（削除した案件・ファイル・ナレッジの復元と完全な削除）に関するビジネスロジックを提供する
+type TrashUseCase interface {
+	// ListTrash はゴミ箱のデータを削除日時の新しい順に、完全に削除される日時とともに取得する
+	ListTrash() ([]*domain.TrashItem, error)
+	// RestoreProject は案件と、案件と一緒に削除されたファイル・ナレッジアイテムを復元する
+	RestoreProject(id int) (*domain.Project, error)
+	// RestoreFile はファイルを復元する
+	RestoreFile(id int) (*domain.UploadedFile, error)
+	// RestoreKnowledge はナレッジアイテムを復元する
+	RestoreKnowledge(id int) (*domain.KnowledgeItem, error)
+	// PurgeExpired は保持期間を過ぎたデータと物理ファイルを完全に削除する（スケジューラーから定期的に呼ばれる）
+	PurgeExpired(now time.Time) (*domain.PurgeResult, error)
+}
+
+// TrashUseCaseImpl はTrashUseCaseの実装
+type TrashUseCaseImpl struct {
+	trashRepo     domain.TrashRepository
+	projectRepo   domain.ProjectRepository
+	fileRepo      domain.FileRepository
+	knowledgeRepo domain.KnowledgeRepository
+	retention     time.Duration
+}
+
+// NewTrashUseCase は新しいTrashUseCaseを生成する
+// retentionDaysが0以下の場合はdomain.DefaultTrashRetentionDaysを使う
+func NewTrashUseCase(
+	trashRepo domain.TrashRepository,
+	projectRepo domain.ProjectRepository,
+	fileRepo domain.FileRepository,
+	knowledgeRepo domain.KnowledgeRepository,
+	retentionDays int,
+) TrashUseCase {
+	if retentionDays <= 0 {
+		retentionDays = domain.DefaultTrashRetentionDays
+	}
+	return &TrashUseCaseImpl{
+		trashRepo:     trashRepo,
+		projectRepo:   projectRepo,
+		fileRepo:      fileRepo,
+		knowledgeRepo: knowledgeRepo,
+		retention:     time.Duration(retentionDays) * 24 * time.Hour,
+	}
+}
+
+// ListTrash はゴミ箱のデータを取得する
+func (u *TrashUseCaseImpl) ListTrash() ([]*domain.TrashItem, error) {
+	items, err := u.trashRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("ゴミ箱の取得に失敗しました: %w", err)
+	}
+
+	for _, item := range items {
+		item.PurgeAt = item.DeletedAt.Add(u.retention)
+	}
+	return items, nil
+}
+
+// RestoreProject は案件を復元する
+func (u *TrashUseCaseImpl) RestoreProject(id int) (*domain.Project, error) {
+	if err := u.trashRepo.RestoreProject(id); err != nil {
+		return nil, fmt.Errorf("ゴミ箱に案件がありません: %w", err)
+	}
+	return u.projectRepo.GetByID(id)
+}
+
+// RestoreFile はファイルを復元する
+func (u *TrashUseCaseImpl) RestoreFile(id int) (*domain.UploadedFile, error) {
+	if err := u.trashRepo.RestoreFile(id); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ゴミ箱にファイルがありません: %w", err)
+	}
+	return u.fileRepo.GetByID(id)
+}
+
+// RestoreKnowledge はナレッジアイテムを復元する
+func (u *TrashUseCaseImpl) RestoreKnowledge(id int) (*domain.KnowledgeItem, error) {
+	if err := u.trashRepo.RestoreKnowledge(id); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ゴミ箱にナレッジアイテムがありません: %w", err)
+	}
+	return u.knowledgeRepo.GetByID(id)
+}
+
+// PurgeExpired は保持期間を過ぎたデータを完全に削除し、物理ファイルを削除する
+// 物理ファイルの削除に失敗しても残りのファイルの削除は続け、失敗をまとめてエラーとして返す
+func (u *TrashUseCaseImpl) PurgeExpired(now time.Time) (*domain.PurgeResult, error) {
+	result, err := u.trashRepo.Purge(now.Add(-u.retention))
+	if err != nil {
+		return nil, fmt.Errorf("ゴミ箱のデータの削除に失敗しました: %w", err)
+	}
+
+	var errs []error
+	for _, filePath := range result.FilePaths {
+		if err := removeStoredFile(filePath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
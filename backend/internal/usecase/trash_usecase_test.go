@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTrashRepository はTrashRepositoryのモック
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) List() ([]*domain.TrashItem, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TrashItem), args.Error(1)
+}
+
+func (m *MockTrashRepository) RestoreProject(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) RestoreFile(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) RestoreKnowledge(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) Purge(before time.Time) (*domain.PurgeResult, error) {
+	args := m.Called(before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PurgeResult), args.Error(1)
+}
+
+func TestTrashUseCase_ListTrash(t *testing.T) {
+	trashRepo := new(MockTrashRepository)
+	usecase := NewTrashUseCase(trashRepo, nil, nil, nil, 7)
+
+	deletedAt := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	trashRepo.On("List").Return([]*domain.TrashItem{
+		{Type: domain.TrashKnowledge, ID: 10, ProjectID: 1, Name: "パスワードの最小文字数は？", DeletedAt: deletedAt},
+	}, nil)
+
+	items, err := usecase.ListTrash()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, deletedAt.AddDate(0, 0, 7), items[0].PurgeAt)
+}
+
+func TestTrashUseCase_RestoreKnowledge(t *testing.T) {
+	t.Run("復元したアイテムを返す", func(t *testing.T) {
+		trashRepo := new(MockTrashRepository)
+		knowledgeRepo := new(MockKnowledgeRepository)
+		usecase := NewTrashUseCase(trashRepo, nil, nil, knowledgeRepo, 0)
+
+		trashRepo.On("RestoreKnowledge", 10).Return(nil)
+		knowledgeRepo.On("GetByID", 10).Return(&domain.KnowledgeItem{ID: 10, Version: 3}, nil)
+
+		item, err := usecase.RestoreKnowledge(10)
+		require.NoError(t, err)
+		assert.Equal(t, 10, item.ID)
+	})
+
+	t.Run("案件がゴミ箱にある場合は検証エラーをそのまま返す", func(t *testing.T) {
+		trashRepo := new(MockTrashRepository)
+		knowledgeRepo := new(MockKnowledgeRepository)
+		usecase := NewTrashUseCase(trashRepo, nil, nil, knowledgeRepo, 0)
+
+		trashRepo.On("RestoreKnowledge", 10).Return(&domain.ValidationError{Field: "project_id", Message: "案件がゴミ箱にあります"})
+
+		_, err := usecase.RestoreKnowledge(10)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		knowledgeRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+
+	t.Run("ゴミ箱にない場合はエラー", func(t *testing.T) {
+		trashRepo := new(MockTrashRepository)
+		usecase := NewTrashUseCase(trashRepo, nil, nil, new(MockKnowledgeRepository), 0)
+
+		trashRepo.On("RestoreKnowledge", 99).Return(sql.ErrNoRows)
+
+		_, err := usecase.RestoreKnowledge(99)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestTrashUseCase_PurgeExpired(t *testing.T) {
+	trashRepo := new(MockTrashRepository)
+	usecase := NewTrashUseCase(trashRepo, nil, nil, nil, 30)
+
+	filePath := filepath.Join(t.TempDir(), "checksheet.xlsx")
+	require.NoError(t, os.WriteFile(filePath, []byte("xlsx"), 0644))
+
+	now := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	trashRepo.On("Purge", now.AddDate(0, 0, -30)).Return(&domain.PurgeResult{
+		Files:     2,
+		Knowledge: 5,
+		// 既に存在しないファイルはエラーにしない
+		FilePaths: []string{filePath, filepath.Join(t.TempDir(), "removed.xlsx")},
+	}, nil)
+
+	result, err := usecase.PurgeExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Knowledge)
+
+	_, err = os.Stat(filePath)
+	assert.True(t, os.IsNotExist(err))
+	trashRepo.AssertExpectations(t)
+}
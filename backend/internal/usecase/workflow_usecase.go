@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/security-checksheets/backend/internal/domain"
+)
+
+// WorkflowUseCase はナレッジのレビュー・承認ワークフローに関するビジネスロジックを提供する
+// expectedVersionに0を指定した場合は版の確認を行わない
+type WorkflowUseCase interface {
+	Submit(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+	Approve(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+	Reject(id int, actor string, reason string, expectedVersion int) (*domain.KnowledgeItem, error)
+	Publish(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+	Archive(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error)
+	ListTransitions(id int) ([]*domain.StatusTransition, error)
+}
+
+// WorkflowUseCaseImpl はWorkflowUseCaseの実装
+type WorkflowUseCaseImpl struct {
+	knowledgeRepo domain.KnowledgeRepository
+	workflowRepo  domain.KnowledgeWorkflowRepository
+}
+
+// NewWorkflowUseCase は新しいWorkflowUseCaseを生成する
+func NewWorkflowUseCase(
+	knowledgeRepo domain.KnowledgeRepository,
+	workflowRepo domain.KnowledgeWorkflowRepository,
+) WorkflowUseCase {
+	return &WorkflowUseCaseImpl{
+		knowledgeRepo: knowledgeRepo,
+		workflowRepo:  workflowRepo,
+	}
+}
+
+// Submit はレビュー依頼を行う
+func (u *WorkflowUseCaseImpl) Submit(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	return u.transition(id, expectedVersion, func(item *domain.KnowledgeItem) (*domain.StatusTransition, error) {
+		return item.Submit(actor)
+	})
+}
+
+// Approve はレビューを承認する
+func (u *WorkflowUseCaseImpl) Approve(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	return u.transition(id, expectedVersion, func(item *domain.KnowledgeItem) (*domain.StatusTransition, error) {
+		return item.Approve(actor)
+	})
+}
+
+// Reject は理由を付けてレビューを差し戻す
+func (u *WorkflowUseCaseImpl) Reject(id int, actor string, reason string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	return u.transition(id, expectedVersion, func(item *domain.KnowledgeItem) (*domain.StatusTransition, error) {
+		return item.Reject(actor, reason)
+	})
+}
+
+// Publish は承認済みのナレッジを公開する
+func (u *WorkflowUseCaseImpl) Publish(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	return u.transition(id, expectedVersion, func(item *domain.KnowledgeItem) (*domain.StatusTransition, error) {
+		return item.Publish(actor)
+	})
+}
+
+// Archive はナレッジをアーカイブする
+func (u *WorkflowUseCaseImpl) Archive(id int, actor string, expectedVersion int) (*domain.KnowledgeItem, error) {
+	return u.transition(id, expectedVersion, func(item *domain.KnowledgeItem) (*domain.StatusTransition, error) {
+		return item.Archive(actor)
+	})
+}
+
+// ListTransitions はステータス遷移の履歴を取得する
+func (u *WorkflowUseCaseImpl) ListTransitions(id int) ([]*domain.StatusTransition, error) {
+	// 存在確認
+	if _, err := u.knowledgeRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	return u.workflowRepo.GetByKnowledgeID(id)
+}
+
+// transition はナレッジアイテムを読み込んで遷移を適用し、遷移記録とともに保存する
+func (u *WorkflowUseCaseImpl) transition(
+	id int,
+	expectedVersion int,
+	apply func(item *domain.KnowledgeItem) (*domain.StatusTransition, error),
+) (*domain.KnowledgeItem, error) {
+	item, err := u.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", err)
+	}
+
+	if expectedVersion != 0 && item.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{Current: item}
+	}
+
+	loadedVersion := item.Version
+	transition, err := apply(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.workflowRepo.Transition(item, loadedVersion, transition); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			current, getErr := u.knowledgeRepo.GetByID(id)
+			if getErr != nil {
+				return nil, fmt.Errorf("ナレッジアイテムが存在しません: %w", getErr)
+			}
+			return nil, &domain.VersionConflictError{Current: current}
+		}
+		return nil, fmt.Errorf("ステータスの変更に失敗しました: %w", err)
+	}
+
+	return item, nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/security-checksheets/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowUseCase_Submit(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	workflowRepo := new(MockKnowledgeWorkflowRepository)
+	usecase := NewWorkflowUseCase(knowledgeRepo, workflowRepo)
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Answer: "回答", Status: domain.StatusDraft, Version: 3}, nil)
+	workflowRepo.On("Transition", mock.AnythingOfType("*domain.KnowledgeItem"), 3, mock.MatchedBy(func(tr *domain.StatusTransition) bool {
+		return tr.FromStatus == domain.StatusDraft && tr.ToStatus == domain.StatusInReview && tr.Actor == "山田太郎"
+	})).Return(nil)
+
+	item, err := usecase.Submit(1, "山田太郎", 3)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusInReview, item.Status)
+	assert.Equal(t, 4, item.Version)
+	workflowRepo.AssertExpectations(t)
+}
+
+func TestWorkflowUseCase_InvalidTransition(t *testing.T) {
+	knowledgeRepo := new(MockKnowledgeRepository)
+	workflowRepo := new(MockKnowledgeWorkflowRepository)
+	usecase := NewWorkflowUseCase(knowledgeRepo, workflowRepo)
+
+	knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: domain.StatusDraft, Version: 3}, nil)
+
+	// 下書きは承認できない
+	_, err := usecase.Approve(1, "佐藤花子", 0)
+	assert.Error(t, err)
+	workflowRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWorkflowUseCase_VersionConflict(t *testing.T) {
+	t.Run("指定した版が古い場合は保存せずに競合を返す", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		workflowRepo := new(MockKnowledgeWorkflowRepository)
+		usecase := NewWorkflowUseCase(knowledgeRepo, workflowRepo)
+
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: domain.StatusInReview, Version: 5}, nil)
+
+		_, err := usecase.Approve(1, "佐藤花子", 4)
+		var conflict *domain.VersionConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, 5, conflict.Current.Version)
+		workflowRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("保存時に更新されていた場合は最新の内容とともに競合を返す", func(t *testing.T) {
+		knowledgeRepo := new(MockKnowledgeRepository)
+		workflowRepo := new(MockKnowledgeWorkflowRepository)
+		usecase := NewWorkflowUseCase(knowledgeRepo, workflowRepo)
+
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: domain.StatusInReview, Version: 5}, nil).Once()
+		knowledgeRepo.On("GetByID", 1).Return(&domain.KnowledgeItem{ID: 1, ProjectID: 1, Question: "質問", Status: domain.StatusRejected, Version: 6}, nil).Once()
+		workflowRepo.On("Transition", mock.AnythingOfType("*domain.KnowledgeItem"), 5, mock.Anything).Return(domain.ErrVersionConflict)
+
+		_, err := usecase.Approve(1, "佐藤花子", 5)
+		var conflict *domain.VersionConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, 6, conflict.Current.Version)
+		assert.Equal(t, domain.StatusRejected, conflict.Current.Status)
+		knowledgeRepo.AssertExpectations(t)
+	})
+}
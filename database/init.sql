@@ -13,13 +13,18 @@ CREATE TABLE projects (
     description TEXT,
     owner VARCHAR(255),
     status VARCHAR(50) DEFAULT 'active',
+    -- 回答のプレースホルダー（{{name}}）に埋め込む案件ごとの値
+    variables JSONB NOT NULL DEFAULT '{}',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- ゴミ箱に移した日時（NULLは未削除）。保持期間の経過後に完全に削除する
+    deleted_at TIMESTAMP
 );
 
 -- プロジェクトテーブルにインデックス
 CREATE INDEX idx_projects_customer ON projects(customer_name);
 CREATE INDEX idx_projects_status ON projects(status);
+CREATE INDEX idx_projects_deleted ON projects(deleted_at) WHERE deleted_at IS NOT NULL;
 
 -- uploaded_files（アップロードファイル）テーブル
 CREATE TABLE uploaded_files (
@@ -30,11 +35,15 @@ CREATE TABLE uploaded_files (
     file_size BIGINT,
     uploaded_by VARCHAR(255),
     uploaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(project_id, file_name)
+    -- ゴミ箱に移した日時（NULLは未削除）
+    deleted_at TIMESTAMP
 );
 
 -- ファイルテーブルにインデックス
 CREATE INDEX idx_files_project ON uploaded_files(project_id);
+-- ファイル名は案件内の未削除のファイルで一意（ゴミ箱のファイルと同じ名前で再アップロードできる）
+CREATE UNIQUE INDEX idx_files_project_name ON uploaded_files(project_id, file_name) WHERE deleted_at IS NULL;
+CREATE INDEX idx_files_deleted ON uploaded_files(deleted_at) WHERE deleted_at IS NOT NULL;
 
 -- departments（部門マスタ）テーブル
 CREATE TABLE departments (
@@ -54,6 +63,23 @@ INSERT INTO departments (name, display_order) VALUES
     ('CS', 5),
     ('営業', 6);
 
+-- canonical_questions（標準質問ライブラリ）テーブル
+-- 顧客ごとに表現の異なる同じ統制項目の質問を1つの標準質問にまとめ、承認済みの標準回答を管理する
+CREATE TABLE canonical_questions (
+    id SERIAL PRIMARY KEY,
+    question TEXT NOT NULL,
+    master_answer TEXT NOT NULL DEFAULT '',
+    -- 乖離判定用に正規化した標準回答
+    normalized_master_answer TEXT NOT NULL DEFAULT '',
+    department_id INTEGER REFERENCES departments(id),
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_canonical_questions_department ON canonical_questions(department_id);
+
 -- knowledge_items（ナレッジQ/A）テーブル
 CREATE TABLE knowledge_items (
     id SERIAL PRIMARY KEY,
@@ -66,20 +92,267 @@ CREATE TABLE knowledge_items (
     department_id INTEGER REFERENCES departments(id),
     question_group VARCHAR(100),
     status VARCHAR(50) DEFAULT 'draft',
+    rejection_reason TEXT NOT NULL DEFAULT '',
+    answer_source_id INTEGER REFERENCES knowledge_items(id) ON DELETE SET NULL,
+    canonical_question_id INTEGER REFERENCES canonical_questions(id) ON DELETE SET NULL,
+    -- 紐づく標準質問の標準回答と回答が異なるかどうか
+    answer_drifted BOOLEAN NOT NULL DEFAULT false,
+    -- 次回の定期レビューの期限と回答の有効期限（NULLは期限なし）
+    review_due_at TIMESTAMP,
+    valid_until TIMESTAMP,
+    -- 再レビューの承認時に次回のレビュー期限を決める間隔（日数、NULLは既定の間隔）
+    review_interval_days INTEGER,
+    -- 回答の担当者・担当の期限・担当タスクの状態（todo/in_progress/done）
+    assignee VARCHAR(255) NOT NULL DEFAULT '',
+    assignment_due_at TIMESTAMP,
+    task_state VARCHAR(20) NOT NULL DEFAULT 'todo',
     version INTEGER DEFAULT 1,
     created_by VARCHAR(255),
+    updated_by VARCHAR(255),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- 検索用に正規化した質問・回答（表示には元のquestion・answerを使う）
+    normalized_question TEXT NOT NULL DEFAULT '',
+    normalized_answer TEXT NOT NULL DEFAULT '',
+    -- ゴミ箱に移した日時（NULLは未削除）
+    deleted_at TIMESTAMP
 );
 
 -- ナレッジテーブルにインデックス
 CREATE INDEX idx_knowledge_project ON knowledge_items(project_id);
 CREATE INDEX idx_knowledge_department ON knowledge_items(department_id);
+CREATE INDEX idx_knowledge_canonical ON knowledge_items(canonical_question_id);
 CREATE INDEX idx_knowledge_status ON knowledge_items(status);
+CREATE INDEX idx_knowledge_review_due ON knowledge_items(review_due_at) WHERE review_due_at IS NOT NULL;
+CREATE INDEX idx_knowledge_valid_until ON knowledge_items(valid_until) WHERE valid_until IS NOT NULL;
+CREATE INDEX idx_knowledge_assignee ON knowledge_items(assignee) WHERE assignee <> '';
+CREATE INDEX idx_knowledge_deleted ON knowledge_items(deleted_at) WHERE deleted_at IS NOT NULL;
 
 -- 日本語全文検索用インデックス（pg_trgmを使用）
-CREATE INDEX idx_knowledge_question_trgm ON knowledge_items USING gin (question gin_trgm_ops);
-CREATE INDEX idx_knowledge_answer_trgm ON knowledge_items USING gin (answer gin_trgm_ops);
+CREATE INDEX idx_knowledge_question_trgm ON knowledge_items USING gin (normalized_question gin_trgm_ops);
+CREATE INDEX idx_knowledge_answer_trgm ON knowledge_items USING gin (normalized_answer gin_trgm_ops);
+
+-- knowledge_answers（回答バリエーション）テーブル
+-- 1つの質問に対するプラン別・部署別などの複数回答を保持する
+CREATE TABLE knowledge_answers (
+    id SERIAL PRIMARY KEY,
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    answer TEXT NOT NULL DEFAULT '',
+    department_id INTEGER REFERENCES departments(id),
+    display_order INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(50) DEFAULT 'draft',
+    created_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_knowledge_answers_item ON knowledge_answers(knowledge_item_id, display_order);
+
+-- knowledge_lineage（ナレッジの派生記録）テーブル
+-- 分割・統合で作成されたアイテムの派生元を記録する。派生元はゴミ箱に移され、保持期間後に完全に削除されるため位置と内容を保持する
+-- 派生元が完全に削除されても派生先の記録は残し、source_item_idのみNULLにする
+CREATE TABLE knowledge_lineage (
+    id SERIAL PRIMARY KEY,
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    source_item_id INTEGER REFERENCES knowledge_items(id) ON DELETE SET NULL,
+    operation VARCHAR(20) NOT NULL,
+    source_sheet_name VARCHAR(255),
+    source_range VARCHAR(100),
+    source_question TEXT NOT NULL,
+    source_answer TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_knowledge_lineage_item ON knowledge_lineage(knowledge_item_id);
+CREATE INDEX idx_knowledge_lineage_source ON knowledge_lineage(source_item_id);
+
+-- knowledge_recommendations（回答の推薦候補）テーブル
+-- 未回答のアイテムごとに、他案件の公開済みナレッジから類似度順に候補を保持する
+CREATE TABLE knowledge_recommendations (
+    id SERIAL PRIMARY KEY,
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    source_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    accepted_by VARCHAR(255),
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(knowledge_item_id, source_item_id)
+);
+
+CREATE INDEX idx_recommendations_item ON knowledge_recommendations(knowledge_item_id, rank);
+
+-- control_frameworks（統制フレームワーク）テーブル
+-- 同梱のカタログ（JSON/CSV）から読み込む
+CREATE TABLE control_frameworks (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    version VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- controls（統制項目）テーブル
+CREATE TABLE controls (
+    id SERIAL PRIMARY KEY,
+    framework_id INTEGER NOT NULL REFERENCES control_frameworks(id) ON DELETE CASCADE,
+    control_id VARCHAR(50) NOT NULL,
+    title VARCHAR(500) NOT NULL DEFAULT '',
+    category VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    display_order INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(framework_id, control_id)
+);
+
+-- knowledge_item_controls（ナレッジと統制項目の紐づけ）テーブル
+CREATE TABLE knowledge_item_controls (
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    control_id INTEGER NOT NULL REFERENCES controls(id) ON DELETE CASCADE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (knowledge_item_id, control_id)
+);
+
+CREATE INDEX idx_knowledge_item_controls_control ON knowledge_item_controls(control_id);
+
+-- question_group_controls（質問グループと統制項目の紐づけ）テーブル
+-- 同じ質問グループのアイテムすべてに統制項目を紐づける
+CREATE TABLE question_group_controls (
+    question_group VARCHAR(100) NOT NULL,
+    control_id INTEGER NOT NULL REFERENCES controls(id) ON DELETE CASCADE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (question_group, control_id)
+);
+
+CREATE INDEX idx_question_group_controls_control ON question_group_controls(control_id);
+
+-- ナレッジアイテムに直接または質問グループを通じて紐づく統制項目
+CREATE VIEW knowledge_effective_controls AS
+    SELECT knowledge_item_id, control_id, 'item' AS source
+    FROM knowledge_item_controls
+    UNION
+    SELECT k.id, g.control_id, 'question_group'
+    FROM knowledge_items k
+    JOIN question_group_controls g ON g.question_group = k.question_group;
+
+-- tags（タグ分類）テーブル
+-- 親子関係を持つ階層構造（例: アクセス制御 > 認証）。自由入力のquestion_groupを整理する管理された分類
+-- 統制項目との対応付けと検索の絞り込みは引き続きquestion_groupを基準にする
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    parent_id INTEGER REFERENCES tags(id) ON DELETE RESTRICT,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 同じ親の下で同名のタグは作れない
+CREATE UNIQUE INDEX idx_tags_parent_name ON tags(COALESCE(parent_id, 0), name);
+CREATE INDEX idx_tags_parent ON tags(parent_id);
+
+-- knowledge_item_tags（ナレッジとタグの紐づけ）テーブル
+CREATE TABLE knowledge_item_tags (
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (knowledge_item_id, tag_id)
+);
+
+CREATE INDEX idx_knowledge_item_tags_tag ON knowledge_item_tags(tag_id);
+
+-- evidence_files（エビデンス）テーブル
+-- 顧客に提出する規程類・診断結果・証明書などのファイル。案件に属さず、複数のナレッジから参照できる
+CREATE TABLE evidence_files (
+    id SERIAL PRIMARY KEY,
+    file_name VARCHAR(255) NOT NULL,
+    file_path VARCHAR(500) NOT NULL,
+    file_size BIGINT,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    uploaded_by VARCHAR(255),
+    uploaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- knowledge_item_evidence（ナレッジとエビデンスの紐づけ）テーブル
+CREATE TABLE knowledge_item_evidence (
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    evidence_file_id INTEGER NOT NULL REFERENCES evidence_files(id) ON DELETE CASCADE,
+    linked_by VARCHAR(255),
+    linked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (knowledge_item_id, evidence_file_id)
+);
+
+CREATE INDEX idx_knowledge_item_evidence_file ON knowledge_item_evidence(evidence_file_id);
+
+-- knowledge_comments（ナレッジのコメント）テーブル
+CREATE TABLE knowledge_comments (
+    id SERIAL PRIMARY KEY,
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    author VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_knowledge_comments_item ON knowledge_comments(knowledge_item_id, created_at);
+
+-- knowledge_comment_mentions（コメントのメンション）テーブル
+-- ユーザー名または部門のどちらか一方を持つ
+CREATE TABLE knowledge_comment_mentions (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES knowledge_comments(id) ON DELETE CASCADE,
+    user_name VARCHAR(255),
+    department_id INTEGER REFERENCES departments(id) ON DELETE CASCADE,
+    CHECK ((user_name IS NULL) <> (department_id IS NULL))
+);
+
+CREATE INDEX idx_knowledge_comment_mentions_comment ON knowledge_comment_mentions(comment_id);
+CREATE INDEX idx_knowledge_comment_mentions_user ON knowledge_comment_mentions(user_name);
+CREATE INDEX idx_knowledge_comment_mentions_department ON knowledge_comment_mentions(department_id);
+
+-- knowledge_comment_threads（ナレッジごとのコメントスレッドの解決状態）テーブル
+CREATE TABLE knowledge_comment_threads (
+    knowledge_item_id INTEGER PRIMARY KEY REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    resolved BOOLEAN NOT NULL DEFAULT false,
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP
+);
+
+-- knowledge_item_revisions（ナレッジ変更履歴）テーブル
+-- 作成・更新のたびにその時点の内容を1行記録する
+CREATE TABLE knowledge_item_revisions (
+    id SERIAL PRIMARY KEY,
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT,
+    department_id INTEGER REFERENCES departments(id),
+    status VARCHAR(50),
+    edited_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(knowledge_item_id, revision)
+);
+
+-- knowledge_status_transitions（ナレッジのステータス遷移履歴）テーブル
+CREATE TABLE knowledge_status_transitions (
+    id SERIAL PRIMARY KEY,
+    knowledge_item_id INTEGER NOT NULL REFERENCES knowledge_items(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    actor VARCHAR(255),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_status_transitions_item ON knowledge_status_transitions(knowledge_item_id);
 
 -- extraction_sessions（抽出セッション・将来用）テーブル
 CREATE TABLE extraction_sessions (
@@ -0,0 +1,85 @@
+import axios from 'axios';
+import { getProjectKnowledge, searchKnowledge } from '../knowledgeService';
+import type { KnowledgeItem } from '../../types/knowledge';
+import type { Page } from '../../types/pagination';
+
+// axiosのモック
+jest.mock('axios');
+const mockedAxios = axios as jest.Mocked<typeof axios>;
+
+const makeKnowledge = (id: number): KnowledgeItem => ({
+  id,
+  project_id: 1,
+  sheet_name: 'Sheet1',
+  source_range: `A${id}`,
+  question: `質問${id}`,
+  answer: `回答${id}`,
+  question_group: '',
+  status: 'draft',
+  assignee: '',
+  task_state: 'todo',
+  version: 1,
+  created_by: 'テスト太郎',
+  created_at: '2026-01-12T00:00:00Z',
+  updated_at: '2026-01-12T00:00:00Z',
+});
+
+describe('knowledgeService', () => {
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  describe('getProjectKnowledge', () => {
+    it('指定したページだけを取得し、件数とともに返す', async () => {
+      // Arrange
+      const page: Page<KnowledgeItem> = {
+        items: [makeKnowledge(51), makeKnowledge(52)],
+        total: 120,
+        limit: 50,
+        offset: 50,
+        sort: 'created_at',
+        order: 'desc',
+      };
+      mockedAxios.get.mockResolvedValueOnce({ data: page });
+
+      // Act
+      const result = await getProjectKnowledge(1, { limit: 50, offset: 50 });
+
+      // Assert
+      expect(mockedAxios.get).toHaveBeenCalledTimes(1);
+      expect(mockedAxios.get).toHaveBeenCalledWith('http://localhost:8080/api/projects/1/knowledge', {
+        params: { limit: 50, offset: 50 },
+      });
+      expect(result).toEqual(page);
+    });
+  });
+
+  describe('searchKnowledge', () => {
+    it('検索条件とページング条件を指定して1ページ分を取得する', async () => {
+      // Arrange
+      const page: Page<KnowledgeItem> = {
+        items: [makeKnowledge(1)],
+        total: 1,
+        limit: 20,
+        offset: 0,
+        sort: 'updated_at',
+        order: 'asc',
+      };
+      mockedAxios.get.mockResolvedValueOnce({ data: page });
+
+      // Act
+      const result = await searchKnowledge(
+        { q: 'パスワード', status: 'draft' },
+        { limit: 20, sort: 'updated_at', order: 'asc' }
+      );
+
+      // Assert
+      expect(mockedAxios.get).toHaveBeenCalledTimes(1);
+      expect(mockedAxios.get).toHaveBeenCalledWith('http://localhost:8080/api/knowledge/search', {
+        params: { q: 'パスワード', status: 'draft', limit: 20, sort: 'updated_at', order: 'asc' },
+      });
+      expect(result.total).toBe(1);
+      expect(result.items).toEqual(page.items);
+    });
+  });
+});
@@ -35,16 +35,52 @@ describe('projectService', () => {
         },
       ];
 
-      mockedAxios.get.mockResolvedValueOnce({ data: mockProjects });
+      mockedAxios.get.mockResolvedValueOnce({
+        data: { items: mockProjects, total: 1, limit: 50, offset: 0, sort: 'created_at', order: 'desc' },
+      });
 
       // Act
       const result = await getProjects();
 
       // Assert
-      expect(mockedAxios.get).toHaveBeenCalledWith('http://localhost:8080/api/projects');
+      expect(mockedAxios.get).toHaveBeenCalledWith('http://localhost:8080/api/projects', {
+        params: { limit: 500, offset: 0 },
+      });
       expect(result).toEqual(mockProjects);
     });
 
+    it('totalに達するまで次のページを取得する', async () => {
+      // Arrange
+      const makeProject = (id: number): Project => ({
+        id,
+        customer_name: `株式会社テスト${id}`,
+        description: 'テスト案件',
+        owner: 'テスト太郎',
+        status: 'active',
+        created_at: '2026-01-12T00:00:00Z',
+        updated_at: '2026-01-12T00:00:00Z',
+      });
+      const firstPage = Array.from({ length: 500 }, (_, i) => makeProject(i + 1));
+
+      mockedAxios.get
+        .mockResolvedValueOnce({
+          data: { items: firstPage, total: 501, limit: 500, offset: 0, sort: 'created_at', order: 'desc' },
+        })
+        .mockResolvedValueOnce({
+          data: { items: [makeProject(501)], total: 501, limit: 500, offset: 500, sort: 'created_at', order: 'desc' },
+        });
+
+      // Act
+      const result = await getProjects();
+
+      // Assert
+      expect(mockedAxios.get).toHaveBeenCalledTimes(2);
+      expect(mockedAxios.get).toHaveBeenLastCalledWith('http://localhost:8080/api/projects', {
+        params: { limit: 500, offset: 500 },
+      });
+      expect(result).toHaveLength(501);
+    });
+
     it('エラー時に例外をスローする', async () => {
       // Arrange
       mockedAxios.get.mockRejectedValueOnce(new Error('Network error'));
@@ -168,14 +204,17 @@ describe('projectService', () => {
         },
       ];
 
-      mockedAxios.get.mockResolvedValueOnce({ data: mockFiles });
+      mockedAxios.get.mockResolvedValueOnce({
+        data: { items: mockFiles, total: 1, limit: 50, offset: 0, sort: 'uploaded_at', order: 'desc' },
+      });
 
       // Act
       const result = await getProjectFiles(1);
 
       // Assert
       expect(mockedAxios.get).toHaveBeenCalledWith(
-        'http://localhost:8080/api/projects/1/files'
+        'http://localhost:8080/api/projects/1/files',
+        { params: { limit: 500, offset: 0 } }
       );
       expect(result).toEqual(mockFiles);
     });
@@ -6,6 +6,8 @@ import type {
   Department,
   KnowledgeSearchFilters,
 } from '../types/knowledge';
+import type { Page, PageParams } from '../types/pagination';
+import { getPage } from './pagination';
 
 const API_BASE_URL = 'http://localhost:8080';
 
@@ -46,13 +48,13 @@ export const getKnowledge = async (id: number): Promise<KnowledgeItem> => {
 };
 
 /**
- * 案件のナレッジ一覧を取得
+ * 案件のナレッジ一覧を1ページ分取得
  */
-export const getProjectKnowledge = async (projectId: number): Promise<KnowledgeItem[]> => {
-  const response = await axios.get<KnowledgeItem[]>(
-    `${API_BASE_URL}/api/projects/${projectId}/knowledge`
-  );
-  return response.data;
+export const getProjectKnowledge = async (
+  projectId: number,
+  page: PageParams = {}
+): Promise<Page<KnowledgeItem>> => {
+  return getPage<KnowledgeItem>(`${API_BASE_URL}/api/projects/${projectId}/knowledge`, {}, page);
 };
 
 /**
@@ -77,22 +79,19 @@ export const deleteKnowledge = async (id: number): Promise<void> => {
 };
 
 /**
- * ナレッジを検索
+ * ナレッジを検索し、1ページ分の結果を取得
  */
 export const searchKnowledge = async (
-  filters: KnowledgeSearchFilters
-): Promise<KnowledgeItem[]> => {
-  const params = new URLSearchParams();
-  if (filters.q) params.append('q', filters.q);
-  if (filters.project_id) params.append('project_id', filters.project_id.toString());
-  if (filters.department_id)
-    params.append('department_id', filters.department_id.toString());
-  if (filters.status) params.append('status', filters.status);
+  filters: KnowledgeSearchFilters,
+  page: PageParams = {}
+): Promise<Page<KnowledgeItem>> => {
+  const params: Record<string, string | number> = {};
+  if (filters.q) params.q = filters.q;
+  if (filters.project_id) params.project_id = filters.project_id;
+  if (filters.department_id) params.department_id = filters.department_id;
+  if (filters.status) params.status = filters.status;
 
-  const response = await axios.get<KnowledgeItem[]>(
-    `${API_BASE_URL}/api/knowledge/search?${params.toString()}`
-  );
-  return response.data;
+  return getPage<KnowledgeItem>(`${API_BASE_URL}/api/knowledge/search`, params, page);
 };
 
 /**
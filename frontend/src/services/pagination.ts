@@ -0,0 +1,42 @@
+import axios from 'axios';
+import type { Page, PageParams } from '../types/pagination';
+
+/**
+ * 一覧APIから1回に取得する件数（APIで指定できる上限）
+ */
+export const PAGE_LIMIT = 500;
+
+/**
+ * ページングされた一覧から1ページ分を取得
+ */
+export const getPage = async <T>(
+  url: string,
+  params: Record<string, string | number> = {},
+  page: PageParams = {}
+): Promise<Page<T>> => {
+  const response = await axios.get<Page<T>>(url, {
+    params: { ...params, ...page },
+  });
+  return response.data;
+};
+
+/**
+ * ページングされた一覧をtotalに達するまで取得
+ * 選択肢の表示など、全件が必要な場合にのみ使用する
+ */
+export const getAllPages = async <T>(
+  url: string,
+  params: Record<string, string | number> = {}
+): Promise<T[]> => {
+  const items: T[] = [];
+  for (;;) {
+    const response = await axios.get<Page<T>>(url, {
+      params: { ...params, limit: PAGE_LIMIT, offset: items.length },
+    });
+    const page = response.data;
+    items.push(...page.items);
+    if (page.items.length === 0 || items.length >= page.total) {
+      return items;
+    }
+  }
+};
@@ -6,15 +6,15 @@ import type {
   UploadedFile,
   UploadFileRequest,
 } from '../types/project';
+import { getAllPages } from './pagination';
 
 const API_BASE_URL = 'http://localhost:8080';
 
 /**
- * 案件一覧を取得
+ * 案件一覧を取得（全ページ）
  */
 export const getProjects = async (): Promise<Project[]> => {
-  const response = await axios.get<Project[]>(`${API_BASE_URL}/api/projects`);
-  return response.data;
+  return getAllPages<Project>(`${API_BASE_URL}/api/projects`);
 };
 
 /**
@@ -52,13 +52,10 @@ export const deleteProject = async (id: number): Promise<void> => {
 };
 
 /**
- * 案件のファイル一覧を取得
+ * 案件のファイル一覧を取得（全ページ）
  */
 export const getProjectFiles = async (projectId: number): Promise<UploadedFile[]> => {
-  const response = await axios.get<UploadedFile[]>(
-    `${API_BASE_URL}/api/projects/${projectId}/files`
-  );
-  return response.data;
+  return getAllPages<UploadedFile>(`${API_BASE_URL}/api/projects/${projectId}/files`);
 };
 
 /**
@@ -14,6 +14,12 @@ export interface KnowledgeItem {
   department_id?: number;
   question_group: string;
   status: KnowledgeStatus;
+  review_due_at?: string;
+  valid_until?: string;
+  review_interval_days?: number;
+  assignee: string;
+  assignment_due_at?: string;
+  task_state: TaskState;
   version: number;
   created_by: string;
   created_at: string;
@@ -25,6 +31,11 @@ export interface KnowledgeItem {
  */
 export type KnowledgeStatus = 'draft' | 'published' | 'archived';
 
+/**
+ * 担当タスクの状態
+ */
+export type TaskState = 'todo' | 'in_progress' | 'done';
+
 /**
  * ナレッジ作成リクエスト
  */
@@ -38,6 +49,11 @@ export interface CreateKnowledgeRequest {
   department_id?: number;
   question_group?: string;
   created_by: string;
+  review_due_at?: string;
+  valid_until?: string;
+  review_interval_days?: number;
+  assignee?: string;
+  assignment_due_at?: string;
 }
 
 /**
@@ -102,3 +118,23 @@ export interface KnowledgeSearchFilters {
   department_id?: number;
   status?: KnowledgeStatus;
 }
+
+/**
+ * ファセットの値ごとの件数
+ */
+export interface FacetCount {
+  value: string;
+  label?: string;
+  count: number;
+}
+
+/**
+ * ナレッジ検索のファセット集計
+ */
+export interface KnowledgeFacets {
+  department: FacetCount[];
+  status: FacetCount[];
+  question_group: FacetCount[];
+  project: FacetCount[];
+  year: FacetCount[];
+}
@@ -0,0 +1,24 @@
+// ページング関連の型定義
+
+/**
+ * ページングされた一覧のレスポンス
+ */
+export interface Page<T> {
+  items: T[];
+  total: number;
+  limit: number;
+  offset: number;
+  sort: string;
+  order: 'asc' | 'desc';
+}
+
+/**
+ * 一覧取得のページング・並び替え条件
+ * 省略した項目はAPIの既定値になる
+ */
+export interface PageParams {
+  limit?: number;
+  offset?: number;
+  sort?: string;
+  order?: 'asc' | 'desc';
+}
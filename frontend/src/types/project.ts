@@ -9,6 +9,8 @@ export interface Project {
   description: string;
   owner: string;
   status: ProjectStatus;
+  /** 回答のプレースホルダー（{{name}}）に埋め込む案件ごとの値 */
+  variables?: Record<string, string>;
   created_at: string;
   updated_at: string;
 }
@@ -25,16 +27,17 @@ export interface CreateProjectRequest {
   customer_name: string;
   description: string;
   owner: string;
+  variables?: Record<string, string>;
 }
 
 /**
- * 案件更新リクエスト
+ * 案件更新リクエスト（省略した項目は変更しない）
  */
 export interface UpdateProjectRequest {
-  customer_name: string;
-  description: string;
-  owner: string;
-  status: ProjectStatus;
+  customer_name?: string;
+  description?: string;
+  owner?: string;
+  status?: ProjectStatus;
 }
 
 /**